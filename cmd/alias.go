@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "블루/그린 컬렉션 alias 관리 (검증, 전환, 롤백)",
+	Long: `collections.yaml에서 같은 alias를 가진 컬렉션들은 한 컬렉션의 버전이다.
+검색 API는 alias 이름으로 활성 버전을 조회하므로, 모델/전략을 바꿀 때는
+새 버전(doc_type)에 'index -c <버전>'으로 인덱싱하는 동안 기존 버전이 계속 검색을 처리한다.
+
+  alias status                     alias별 활성/직전 버전
+  alias validate <alias> <버전>    새 버전을 활성 버전과 비교 (coverage, self recall, overlap)
+  alias cutover <alias> <버전>     검증 후 활성 버전 전환 (serve는 ALIAS_REFRESH_INTERVAL 안에 반영)
+  alias rollback <alias>           직전 버전으로 되돌리기`,
+}
+
+var aliasStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "alias별 활성/직전 버전",
+	Args:  cobra.NoArgs,
+	RunE:  runAliasStatus,
+}
+
+var aliasValidateCmd = &cobra.Command{
+	Use:   "validate <alias> <version>",
+	Short: "새 버전을 활성 버전과 비교",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runAliasValidate,
+}
+
+var aliasCutoverCmd = &cobra.Command{
+	Use:   "cutover <alias> <version>",
+	Short: "검증 후 활성 버전 전환",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runAliasCutover,
+}
+
+var aliasRollbackCmd = &cobra.Command{
+	Use:   "rollback <alias>",
+	Short: "직전 활성 버전으로 되돌리기",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAliasRollback,
+}
+
+var (
+	aliasValidate     service.ValidateOptions
+	aliasSkipValidate bool
+)
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasStatusCmd, aliasValidateCmd, aliasCutoverCmd, aliasRollbackCmd)
+	for _, cmd := range []*cobra.Command{aliasValidateCmd, aliasCutoverCmd} {
+		cmd.Flags().IntVar(&aliasValidate.Samples, "samples", service.DefaultValidateSamples, "표본 쿼리 문서 수")
+		cmd.Flags().IntVar(&aliasValidate.K, "k", service.DefaultValidateK, "비교할 상위 결과 수")
+		cmd.Flags().Float64Var(&aliasValidate.MinCoverage, "min-coverage", service.DefaultValidateMinCoverage, "기존 문서 중 새 버전에 있어야 하는 비율")
+		cmd.Flags().Float64Var(&aliasValidate.MinSelfRecall, "min-self-recall", service.DefaultValidateMinSelf, "표본 문서가 자기 자신을 찾아야 하는 비율")
+		cmd.Flags().Float64Var(&aliasValidate.MinOverlap, "min-overlap", service.DefaultValidateMinOverlap, "상위 K 이웃이 겹쳐야 하는 평균 비율")
+	}
+	aliasCutoverCmd.Flags().BoolVar(&aliasSkipValidate, "skip-validate", false, "검증 없이 전환")
+}
+
+// aliasEnv alias 명령 공통 준비물
+type aliasEnv struct {
+	collections *repository.Collections
+	aliases     repository.AliasStore
+	store       repository.VectorStore
+}
+
+// setupAlias DB, 컬렉션 레지스트리, alias 저장소 (withStore면 벡터 저장소까지)
+func setupAlias(ctx context.Context, withStore bool) (*aliasEnv, func(), error) {
+	cfg := GetConfig()
+
+	fmt.Println("[1] DB/컬렉션 설정...")
+	db, err := config.NewDB(&cfg.DB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DB 연결 실패: %w", err)
+	}
+	collections, err := buildCollections(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("컬렉션 설정 오류: %w", err)
+	}
+	aliases, err := newAliasStore(cfg, db)
+	if err != nil {
+		return nil, nil, fmt.Errorf("alias 저장소 설정 오류: %w", err)
+	}
+	if err := repository.ApplyAliases(ctx, aliases, collections); err != nil {
+		return nil, nil, err
+	}
+	fmt.Printf("    [OK] DB 연결 성공: %s:%s/%s, alias %d개\n", cfg.DB.Host, cfg.DB.Port, cfg.DB.Name, len(collections.Aliases()))
+	env := &aliasEnv{collections: collections, aliases: aliases}
+	cleanup := func() {}
+	if withStore {
+		store, backend, err := newVectorStore(cfg, collections)
+		if err != nil {
+			return nil, nil, fmt.Errorf("벡터 저장소 설정 오류: %w", err)
+		}
+		env.store = store
+		cleanup = func() { closeVectorStore(store) }
+		fmt.Printf("    [OK] 벡터 저장소: %s\n", backend)
+	}
+	fmt.Println()
+	return env, cleanup, nil
+}
+
+func runAliasStatus(cmd *cobra.Command, args []string) error {
+	fmt.Println("=== Embedder Worker - Alias Status ===")
+	fmt.Println()
+
+	ctx := context.Background()
+	env, cleanup, err := setupAlias(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	fmt.Println("[2] alias 목록...")
+	if len(env.collections.Aliases()) == 0 {
+		fmt.Println("    [SKIP] collections.yaml에 alias가 지정된 컬렉션 없음")
+	}
+	for _, alias := range env.collections.Aliases() {
+		active, _ := env.collections.Active(alias)
+		previous := "-"
+		if row, err := env.aliases.Alias(ctx, alias); err == nil && row.Previous != "" {
+			previous = row.Previous
+		}
+		fmt.Printf("    %s → %s (직전: %s)\n", alias, active, previous)
+		for _, version := range env.collections.Versions(alias) {
+			marker := " "
+			if version.Name == active {
+				marker = "*"
+			}
+			fmt.Printf("      %s %s (%s/%s)\n", marker, version.Name, version.Namespace, version.DocType)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func runAliasValidate(cmd *cobra.Command, args []string) error {
+	fmt.Println("=== Embedder Worker - Alias Validate ===")
+	fmt.Println()
+
+	ctx := context.Background()
+	env, cleanup, err := setupAlias(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	report, err := validateVersion(ctx, env, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("검증 실패: %v", report.Reasons)
+	}
+	fmt.Println("=== Validate Passed ===")
+	return nil
+}
+
+func runAliasCutover(cmd *cobra.Command, args []string) error {
+	fmt.Println("=== Embedder Worker - Alias Cutover ===")
+	fmt.Println()
+
+	ctx := context.Background()
+	env, cleanup, err := setupAlias(ctx, !aliasSkipValidate)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	alias, target := args[0], args[1]
+
+	if aliasSkipValidate {
+		fmt.Println("[2] [SKIP] --skip-validate")
+		fmt.Println()
+	} else {
+		report, err := validateVersion(ctx, env, alias, target)
+		if err != nil {
+			return err
+		}
+		if !report.Passed {
+			return fmt.Errorf("검증 실패로 전환 중단 (--skip-validate로 강제 가능): %v", report.Reasons)
+		}
+	}
+
+	fmt.Printf("[3] %s → %s 전환...\n", alias, target)
+	row, err := service.Cutover(ctx, env.aliases, env.collections, alias, target)
+	if err != nil {
+		return fmt.Errorf("전환 실패: %w", err)
+	}
+	fmt.Printf("    [OK] %s → %s (직전: %s)\n", row.Alias, row.Active, row.Previous)
+	fmt.Printf("    롤백: embedder-worker alias rollback %s\n", row.Alias)
+	fmt.Println()
+
+	fmt.Println("=== Cutover Completed ===")
+	return nil
+}
+
+func runAliasRollback(cmd *cobra.Command, args []string) error {
+	fmt.Println("=== Embedder Worker - Alias Rollback ===")
+	fmt.Println()
+
+	ctx := context.Background()
+	env, cleanup, err := setupAlias(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	fmt.Printf("[2] %s 롤백...\n", args[0])
+	row, err := service.Rollback(ctx, env.aliases, env.collections, args[0])
+	if err != nil {
+		return fmt.Errorf("롤백 실패: %w", err)
+	}
+	fmt.Printf("    [OK] %s → %s (직전: %s)\n", row.Alias, row.Active, row.Previous)
+	fmt.Println()
+
+	fmt.Println("=== Rollback Completed ===")
+	return nil
+}
+
+// validateVersion 활성 버전과 target 비교 후 결과 출력
+func validateVersion(ctx context.Context, env *aliasEnv, alias, target string) (*service.ValidationReport, error) {
+	active, ok := env.collections.Active(alias)
+	if !ok {
+		return nil, fmt.Errorf("알 수 없는 alias: %s", alias)
+	}
+	from, _ := env.collections.Get(active)
+	to, ok := env.collections.Get(target)
+	if !ok || to.Alias != alias {
+		return nil, fmt.Errorf("컬렉션 %s는 alias %s의 버전이 아님", target, alias)
+	}
+
+	fmt.Printf("[2] %s(활성) ↔ %s 비교...\n", from.Name, to.Name)
+	report, err := service.ValidateCollection(ctx, env.store, from, to, aliasValidate)
+	if err != nil {
+		return nil, fmt.Errorf("검증 실패: %w", err)
+	}
+	data, _ := json.MarshalIndent(report, "    ", "  ")
+	fmt.Printf("    %s\n", data)
+	if report.Passed {
+		fmt.Println("    [OK] 검증 통과")
+	} else {
+		fmt.Printf("    [FAIL] %v\n", report.Reasons)
+	}
+	fmt.Println()
+	return report, nil
+}
+
+// applyAliases 저장된 alias 포인터 반영 후 interval마다 다시 읽는다 (alias가 없으면 아무것도 하지 않는다)
+func applyAliases(cfg *config.Config, db *gorm.DB, collections *repository.Collections) (stop func(), err error) {
+	if len(collections.Aliases()) == 0 {
+		fmt.Println("    [SKIP] alias 없음")
+		return func() {}, nil
+	}
+	aliases, err := newAliasStore(cfg, db)
+	if err != nil {
+		return nil, fmt.Errorf("alias 저장소 설정 오류: %w", err)
+	}
+	if err := repository.ApplyAliases(context.Background(), aliases, collections); err != nil {
+		return nil, err
+	}
+	for _, alias := range collections.Aliases() {
+		active, _ := collections.Active(alias)
+		fmt.Printf("    [OK] %s → %s\n", alias, active)
+	}
+
+	interval := cfg.Index.AliasRefreshInterval
+	if interval <= 0 {
+		return func() {}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := repository.ApplyAliases(ctx, aliases, collections); err != nil && ctx.Err() == nil {
+				fmt.Printf("[Alias] [WARN] alias 갱신 실패: %v\n", err)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// buildCollections 설정의 컬렉션 목록으로 레지스트리 생성
+func buildCollections(cfg *config.Config) (*repository.Collections, error) {
+	schemas := make([]repository.Schema, 0, len(cfg.Collections))
+	for _, c := range cfg.Collections {
+		vectors := make([]repository.VectorField, 0, len(c.Vectors))
+		for _, v := range c.Vectors {
+			vectors = append(vectors, repository.VectorField{Name: v.Name, Dimension: v.Dimension})
+		}
+		schemas = append(schemas, repository.Schema{
+			Name:      c.Name,
+			Alias:     c.Alias,
+			Namespace: c.Namespace,
+			DocType:   c.DocType,
+			Cluster:   c.Cluster,
+			Vectors:   vectors,
+		})
+	}
+	return repository.NewCollections(schemas...)
+}
@@ -10,18 +10,25 @@ import (
 )
 
 var (
-	forceDownload bool
+	forceDownload    bool
+	downloadReranker bool
 )
 
 var downloadCmd = &cobra.Command{
 	Use:   "download",
 	Short: "모델 파일 다운로드",
-	Long:  `HuggingFace Hub에서 ONNX 모델 파일을 다운로드한다.`,
-	RunE:  runDownload,
+	Long: `HuggingFace Hub에서 ONNX 모델 파일을 다운로드한다.
+--reranker면 임베딩 모델 대신 RERANKER_MODEL_REPO의 재순위 모델을 받는다.
+
+저장소에 sparse_linear.pt(BGE-M3 sparse 헤드)가 있으면 함께 받아 학습된 sparse 가중치를 만든다.
+없으면 토큰 빈도 sparse를 쓰며, 이 값은 Python 서비스가 채운 컬렉션의 keywords와 척도가 달라
+Go 워커로 전환하기 전에 컬렉션을 재색인해야 한다.`,
+	RunE: runDownload,
 }
 
 func init() {
 	downloadCmd.Flags().BoolVarP(&forceDownload, "force", "f", false, "기존 파일 덮어쓰기")
+	downloadCmd.Flags().BoolVar(&downloadReranker, "reranker", false, "재순위 모델(RERANKER_MODEL_REPO) 다운로드")
 	rootCmd.AddCommand(downloadCmd)
 }
 
@@ -30,17 +37,24 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	cfg := GetConfig()
+	repo, cacheDir := cfg.HuggingFace.ModelRepo, cfg.HuggingFace.CacheDir
+	if downloadReranker {
+		if cfg.Reranker.ModelRepo == "" {
+			return fmt.Errorf("RERANKER_MODEL_REPO가 비어 있음")
+		}
+		repo, cacheDir = cfg.Reranker.ModelRepo, cfg.Reranker.CacheDir
+	}
 
 	fmt.Println("[1] 설정 확인...")
-	fmt.Printf("    Model repo: %s\n", cfg.HuggingFace.ModelRepo)
-	fmt.Printf("    Cache dir: %s\n", cfg.HuggingFace.CacheDir)
+	fmt.Printf("    Model repo: %s\n", repo)
+	fmt.Printf("    Cache dir: %s\n", cacheDir)
 	fmt.Println()
 
 	// 강제 다운로드 시 기존 파일 삭제
 	if forceDownload {
 		fmt.Println("[1.5] 기존 파일 삭제 (--force)...")
 		for _, filename := range downloader.ModelFiles {
-			filePath := cfg.HuggingFace.CacheDir + "/" + filename
+			filePath := cacheDir + "/" + filename
 			if err := os.Remove(filePath); err == nil {
 				fmt.Printf("    삭제: %s\n", filename)
 			}
@@ -49,7 +63,7 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println("[2] 모델 파일 다운로드...")
-	dl := downloader.NewHuggingFaceDownloader(cfg.HuggingFace.Token, cfg.HuggingFace.ModelRepo, cfg.HuggingFace.CacheDir)
+	dl := downloader.NewHuggingFaceDownloader(cfg.HuggingFace.Token, repo, cacheDir)
 	if err := dl.Download(); err != nil {
 		return fmt.Errorf("다운로드 실패: %w", err)
 	}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/internal/downloader"
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+)
+
+// newEmbedder 다운로드된 모델로 ONNX 임베더 생성
+func newEmbedder(cfg *config.Config) (*embedder.ONNXEmbedder, error) {
+	dl := downloader.NewHuggingFaceDownloader(cfg.HuggingFace.Token, cfg.HuggingFace.ModelRepo, cfg.HuggingFace.CacheDir)
+	modelPath := dl.GetModelPath()
+	if _, err := os.Stat(modelPath); err != nil {
+		return nil, fmt.Errorf("모델 파일 없음: %s (먼저 'download' 명령 실행)", modelPath)
+	}
+
+	libPath := cfg.Embedder.LibraryPath
+	if libPath == "" {
+		libPath = getONNXRuntimeLibPath()
+	}
+	if _, err := os.Stat(libPath); err != nil {
+		return nil, fmt.Errorf("ONNX Runtime 라이브러리 없음: %s\n설치 방법: %s", libPath, getInstallHint())
+	}
+
+	// sparse 헤드가 없으면 토큰 빈도 sparse: Python(BGE-M3 학습 가중치)이 채운 컬렉션과 척도가 다르다
+	sparseHeadPath := dl.GetSparseHeadPath()
+	if _, err := os.Stat(sparseHeadPath); err != nil {
+		fmt.Printf("[WARN] sparse 헤드 없음 (%s): 토큰 빈도 sparse 사용, Python이 색인한 컬렉션은 재색인 필요\n", sparseHeadPath)
+		sparseHeadPath = ""
+	}
+
+	return embedder.NewONNXEmbedder(embedder.Config{
+		ModelPath:      modelPath,
+		TokenizerPath:  dl.GetTokenizerPath(),
+		LibraryPath:    libPath,
+		MaxLength:      cfg.Embedder.MaxLength,
+		Threads:        cfg.Embedder.Threads,
+		SparseHeadPath: sparseHeadPath,
+	})
+}
+
+// newCrossEncoder 다운로드된 재순위 모델로 ONNX cross-encoder 생성 (RERANKER_MODEL_REPO가 비면 nil)
+func newCrossEncoder(cfg *config.Config) (*embedder.ONNXCrossEncoder, error) {
+	if cfg.Reranker.ModelRepo == "" {
+		return nil, nil
+	}
+	dl := downloader.NewHuggingFaceDownloader(cfg.HuggingFace.Token, cfg.Reranker.ModelRepo, cfg.Reranker.CacheDir)
+	modelPath := dl.GetModelPath()
+	if _, err := os.Stat(modelPath); err != nil {
+		return nil, fmt.Errorf("재순위 모델 파일 없음: %s (먼저 'download --reranker' 명령 실행)", modelPath)
+	}
+
+	libPath := cfg.Embedder.LibraryPath
+	if libPath == "" {
+		libPath = getONNXRuntimeLibPath()
+	}
+	return embedder.NewONNXCrossEncoder(embedder.Config{
+		ModelPath:     modelPath,
+		TokenizerPath: dl.GetTokenizerPath(),
+		LibraryPath:   libPath,
+		MaxLength:     cfg.Reranker.MaxLength,
+		Threads:       cfg.Embedder.Threads,
+	})
+}
+
+// modelRevision 다운로드된 모델 리비전 (작업 기록용)
+func modelRevision(cfg *config.Config) (string, error) {
+	dl := downloader.NewHuggingFaceDownloader(cfg.HuggingFace.Token, cfg.HuggingFace.ModelRepo, cfg.HuggingFace.CacheDir)
+	revision, err := dl.ModelRevision()
+	if err != nil {
+		return "", fmt.Errorf("모델 리비전 계산 실패: %w", err)
+	}
+	return revision, nil
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "DB → 임베딩 → 벡터 저장소 인덱싱",
+	Long: `MySQL의 술 데이터를 페이지 단위로 읽어 전략 텍스트를 만들고,
+ONNX 임베더로 배치 임베딩한 뒤 벡터 저장소에 저장한다.
+--dry-run이면 전략 텍스트만 만들고 임베딩/저장은 하지 않는다.
+
+실행마다 작업(job)을 기록하며, 중단된 작업은 --resume <job>으로
+마지막 커밋 지점 다음부터 이어서 실행한다.
+
+--incremental이면 마지막 동기화 이후 last_modify_at이 바뀐 술
+(연결된 region/distillery/tasting tag 변경 포함)만 다시 임베딩한다.
+연관 테이블에 last_modify_at 컬럼이 없으면 경고 후 그 테이블 변경은 감지하지 않는다.
+늦게 커밋된 행을 놓치지 않도록 기준 시각보다 SYNC_OVERLAP(기본 5m)만큼
+앞당겨 다시 조회하며, 이미 반영된 술은 내용 해시가 같아 건너뛴다.
+alcohol_tasting_tags 연결만 추가/삭제된 경우는 수정 시각이 없어 잡지 못한다.
+이 변경은 outbox(OUTBOX_INTERVAL)로 받거나 'reconcile --repair'로 반영한다.`,
+	RunE: runIndex,
+}
+
+var (
+	indexCollection  string
+	indexFromID      int64
+	indexToID        int64
+	indexLimit       int
+	indexDryRun      bool
+	indexPageSize    int
+	indexBatchSize   int
+	indexWorkers     int
+	indexResume      string
+	indexIncremental bool
+)
+
+func init() {
+	rootCmd.AddCommand(indexCmd)
+	indexCmd.Flags().StringVarP(&indexCollection, "collection", "c", "whisky", "대상 컬렉션")
+	indexCmd.Flags().Int64Var(&indexFromID, "from-id", 0, "시작 ID (포함)")
+	indexCmd.Flags().Int64Var(&indexToID, "to-id", 0, "끝 ID (포함, 0이면 끝까지)")
+	indexCmd.Flags().IntVar(&indexLimit, "limit", 0, "최대 처리 건수 (0이면 제한 없음)")
+	indexCmd.Flags().BoolVar(&indexDryRun, "dry-run", false, "임베딩/저장 없이 전략 텍스트만 생성")
+	indexCmd.Flags().IntVar(&indexPageSize, "page-size", service.DefaultIndexPageSize, "DB 페이지 크기")
+	indexCmd.Flags().IntVar(&indexBatchSize, "batch-size", service.DefaultIndexBatchSize, "임베딩/저장 배치 크기")
+	indexCmd.Flags().IntVar(&indexWorkers, "workers", service.DefaultIndexWorkers, "동시에 임베딩하는 배치 수")
+	indexCmd.Flags().StringVar(&indexResume, "resume", "", "중단된 작업 ID (범위 플래그는 작업 기록을 따른다)")
+	indexCmd.Flags().BoolVar(&indexIncremental, "incremental", false, "마지막 동기화 이후 바뀐 술만 인덱싱")
+}
+
+func runIndex(cmd *cobra.Command, args []string) error {
+	fmt.Println("=== Embedder Worker - Index ===")
+	fmt.Println()
+
+	cfg := GetConfig()
+	if indexResume != "" && indexDryRun {
+		return fmt.Errorf("--resume과 --dry-run은 함께 쓸 수 없음")
+	}
+	if indexIncremental && (indexResume != "" || indexFromID > 0 || indexToID > 0 || indexLimit > 0) {
+		return fmt.Errorf("--incremental은 --resume, --from-id, --to-id, --limit과 함께 쓸 수 없음")
+	}
+	if indexToID > 0 && indexToID < indexFromID {
+		return fmt.Errorf("--to-id(%d)가 --from-id(%d)보다 작음", indexToID, indexFromID)
+	}
+
+	// 1. DB 연결
+	fmt.Println("[1] DB 연결 중...")
+	db, err := config.NewDB(&cfg.DB)
+	if err != nil {
+		return fmt.Errorf("DB 연결 실패: %w", err)
+	}
+	fmt.Printf("    [OK] DB 연결 성공: %s:%s/%s\n", cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
+	fmt.Println()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 2. 컬렉션, 전략, 작업 기록
+	fmt.Println("[2] 컬렉션/전략/작업 설정...")
+	var jobs repository.JobStore
+	var job *domain.IndexJob
+	if !indexDryRun || indexIncremental {
+		jobs, err = newJobStore(cfg, db)
+		if err != nil {
+			return fmt.Errorf("작업 저장소 설정 오류: %w", err)
+		}
+	}
+	if indexResume != "" {
+		job, err = jobs.Get(ctx, indexResume)
+		if err != nil {
+			return fmt.Errorf("작업 %s 조회 실패: %w", indexResume, err)
+		}
+		indexCollection = job.Collection
+	}
+
+	collections, err := buildCollections(cfg)
+	if err != nil {
+		return fmt.Errorf("컬렉션 설정 오류: %w", err)
+	}
+	schema, ok := collections.Get(indexCollection)
+	if !ok {
+		return fmt.Errorf("알 수 없는 컬렉션: %s", indexCollection)
+	}
+	strategy, err := newStrategy(cfg)
+	if err != nil {
+		return fmt.Errorf("전략 설정 오류: %w", err)
+	}
+	fmt.Printf("    [OK] 컬렉션: %s, 전략: %s\n", schema.Name, strategy.Version())
+
+	opts := service.IndexOptions{
+		FromID:    indexFromID,
+		ToID:      indexToID,
+		Limit:     indexLimit,
+		DryRun:    indexDryRun,
+		PageSize:  indexPageSize,
+		BatchSize: indexBatchSize,
+		Workers:   indexWorkers,
+	}
+	if !indexDryRun {
+		opts.ModelRevision, err = modelRevision(cfg)
+		if err != nil {
+			return err
+		}
+	}
+	if !indexDryRun && !indexIncremental {
+		if job == nil {
+			job = service.NewIndexJob(schema.Name, opts, opts.ModelRevision, strategy.Version())
+			fmt.Printf("    [OK] 새 작업: %s\n", job.ID)
+		} else {
+			if err := service.CheckResume(job, opts.ModelRevision, strategy.Version()); err != nil {
+				return fmt.Errorf("작업 재개 불가: %w", err)
+			}
+			fmt.Printf("    [OK] 작업 재개: %s (last_committed_id=%d, 처리 %d)\n", job.ID, job.LastCommittedID, job.Processed)
+		}
+	}
+	fmt.Println()
+
+	// 3. 임베더, 벡터 저장소 (dry-run이면 생략)
+	var emb embedder.Embedder
+	var store repository.VectorStore
+	if indexDryRun {
+		fmt.Println("[3] [SKIP] dry-run: 임베더/벡터 저장소 생략")
+	} else {
+		fmt.Println("[3] 임베더/벡터 저장소 설정...")
+		onnx, err := newEmbedder(cfg)
+		if err != nil {
+			return fmt.Errorf("임베더 생성 실패: %w", err)
+		}
+		defer onnx.Close()
+		emb = onnx
+
+		var backend repository.Backend
+		store, backend, err = newVectorStore(cfg, collections)
+		if err != nil {
+			return fmt.Errorf("벡터 저장소 설정 오류: %w", err)
+		}
+		defer closeVectorStore(store)
+		fmt.Printf("    [OK] 임베더 로드, 벡터 저장소: %s\n", backend)
+	}
+	fmt.Println()
+
+	// 4. 인덱싱 (SIGINT/SIGTERM이면 진행 중인 배치까지 보고 후 중단)
+	fmt.Println("[4] 인덱싱...")
+	indexer := service.NewIndexer(repository.NewAlcoholRepository(db), strategy, emb, store, schema)
+	progress := func(p service.IndexProgress) {
+		fmt.Printf("    읽음 %d, 저장 %d, 변경 없음 %d, 실패 %d (last_id=%d, %s)\n", p.Read, p.Indexed, p.Skipped, p.Failed, p.LastID, p.Elapsed.Round(time.Millisecond))
+	}
+
+	if indexIncremental {
+		syncReport, err := indexer.Sync(ctx, jobs, opts, cfg.Index.SyncOverlap, progress)
+		if syncReport != nil {
+			printSyncReport(syncReport)
+		}
+		if err != nil {
+			return fmt.Errorf("증분 동기화 중단: %w", err)
+		}
+		fmt.Println("=== Index Completed ===")
+		return nil
+	}
+
+	var report *service.IndexReport
+	if job != nil {
+		report, err = indexer.RunJob(ctx, jobs, job, opts, progress)
+	} else {
+		report, err = indexer.Run(ctx, opts, progress)
+	}
+	if report != nil {
+		printIndexReport(report)
+	}
+	if job != nil {
+		fmt.Printf("    작업 %s: %s (last_committed_id=%d)\n", job.ID, job.Status, job.LastCommittedID)
+		if job.Status != domain.JobCompleted {
+			fmt.Printf("    재개: embedder-worker index --resume %s\n", job.ID)
+		}
+		fmt.Println()
+	}
+	if err != nil {
+		return fmt.Errorf("인덱싱 중단: %w", err)
+	}
+
+	fmt.Println("=== Index Completed ===")
+	return nil
+}
+
+func printIndexReport(report *service.IndexReport) {
+	fmt.Println()
+	fmt.Printf("    전략 버전: %s\n", report.StrategyVersion)
+	fmt.Printf("    읽음: %d, 저장: %d, 변경 없음: %d, 실패: %d, 정규화 불가 값: %d\n", report.Read, report.Indexed, report.Skipped, report.Failed, report.Issues)
+	if report.HashLookupFailed > 0 {
+		fmt.Printf("    [WARN] 해시 조회 실패로 전체 저장한 배치: %d (%s)\n", report.HashLookupFailed, report.HashLookupError)
+	}
+	for _, failure := range report.Failures {
+		fmt.Printf("    [FAIL] id=%d (%s): %s\n", failure.ID, failure.Stage, failure.Error)
+	}
+	fmt.Println()
+}
+
+func printSyncReport(report *service.SyncReport) {
+	if report.Index != nil {
+		printIndexReport(report.Index)
+	}
+	fmt.Printf("    변경 구간: %s ~ %s (기준 시각 %s), 변경된 술: %d\n", formatWatermark(report.ScanFrom), formatWatermark(report.Until), formatWatermark(report.Since), report.Changed)
+	if report.Advanced {
+		fmt.Println("    [OK] 기준 시각 전진")
+	} else if report.Changed > 0 && report.Until.After(report.Since) {
+		fmt.Println("    [WARN] 기준 시각 유지 (실패 또는 dry-run, 다음 동기화에서 다시 처리)")
+	}
+	fmt.Println()
+}
+
+func formatWatermark(t time.Time) string {
+	if t.IsZero() {
+		return "처음"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// newJobStore JOB_STORE 설정에 맞는 작업 기록 저장소 (비우면 백엔드에 따라 선택)
+func newJobStore(cfg *config.Config, db *gorm.DB) (repository.JobStore, error) {
+	kind, err := jobStoreKind(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "file" {
+		return repository.NewFileJobStore(cfg.Index.JobDir)
+	}
+	return repository.NewGormJobStore(db)
+}
+
+// newAliasStore 컬렉션 alias 포인터 저장소 (JOB_STORE 설정을 따른다)
+func newAliasStore(cfg *config.Config, db *gorm.DB) (repository.AliasStore, error) {
+	kind, err := jobStoreKind(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "file" {
+		return repository.NewFileAliasStore(cfg.Index.JobDir)
+	}
+	return repository.NewGormAliasStore(db)
+}
+
+// jobStoreKind JOB_STORE 값 (비우면 embedded/memory 백엔드는 file, 그 외는 db)
+func jobStoreKind(cfg *config.Config) (string, error) {
+	kind := cfg.Index.JobStore
+	if kind == "" {
+		kind = "db"
+		backend, err := repository.ParseBackend(cfg.Vector.Backend)
+		if err == nil && (backend == repository.BackendEmbedded || backend == repository.BackendMemory) {
+			kind = "file"
+		}
+	}
+	if kind != "db" && kind != "file" {
+		return "", fmt.Errorf("알 수 없는 JOB_STORE: %q (db, file)", kind)
+	}
+	return kind, nil
+}
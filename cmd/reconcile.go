@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "MySQL과 벡터 저장소 비교 (누락/고아/오래된 문서)",
+	Long: `벡터 저장소 전체를 Visit으로 순회해 ID별 내용 해시를 모으고,
+DB를 페이지 단위로 읽어 지금 인덱싱했을 때의 해시와 비교한다.
+
+  missing   DB에는 있지만 저장소에 없는 문서
+  orphaned  DB에서 삭제되었지만 저장소에 남은 문서
+  stale     해시가 달라 다시 임베딩이 필요한 문서
+
+결과는 JSON 보고서로 --output에 쓴다 ("-"이면 표준 출력, 진행 로그는 표준 에러).
+--repair이면 고아 문서를 삭제하고 누락/오래된 문서를 다시 인덱싱한다.`,
+	RunE: runReconcile,
+}
+
+var (
+	reconcileCollection string
+	reconcileOutput     string
+	reconcileRepair     bool
+	reconcilePageSize   int
+	reconcileBatchSize  int
+	reconcileWorkers    int
+)
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.Flags().StringVarP(&reconcileCollection, "collection", "c", "whisky", "대상 컬렉션")
+	reconcileCmd.Flags().StringVarP(&reconcileOutput, "output", "o", "reconcile-report.json", "보고서 경로 (\"-\"이면 표준 출력)")
+	reconcileCmd.Flags().BoolVar(&reconcileRepair, "repair", false, "고아 문서 삭제 + 누락/오래된 문서 재인덱싱")
+	reconcileCmd.Flags().IntVar(&reconcilePageSize, "page-size", service.DefaultIndexPageSize, "DB 페이지 크기")
+	reconcileCmd.Flags().IntVar(&reconcileBatchSize, "batch-size", service.DefaultIndexBatchSize, "재인덱싱 배치 크기")
+	reconcileCmd.Flags().IntVar(&reconcileWorkers, "workers", service.DefaultIndexWorkers, "재인덱싱 동시 배치 수")
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	// 보고서를 표준 출력으로 내보내면 진행 로그는 표준 에러로
+	var log io.Writer = os.Stdout
+	if reconcileOutput == "-" {
+		log = os.Stderr
+	}
+
+	fmt.Fprintln(log, "=== Embedder Worker - Reconcile ===")
+	fmt.Fprintln(log)
+
+	cfg := GetConfig()
+
+	// 1. DB 연결
+	fmt.Fprintln(log, "[1] DB 연결 중...")
+	db, err := config.NewDB(&cfg.DB)
+	if err != nil {
+		return fmt.Errorf("DB 연결 실패: %w", err)
+	}
+	fmt.Fprintf(log, "    [OK] DB 연결 성공: %s:%s/%s\n", cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
+	fmt.Fprintln(log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 2. 컬렉션, 전략, 벡터 저장소
+	fmt.Fprintln(log, "[2] 컬렉션/전략/벡터 저장소 설정...")
+	collections, err := buildCollections(cfg)
+	if err != nil {
+		return fmt.Errorf("컬렉션 설정 오류: %w", err)
+	}
+	schema, ok := collections.Get(reconcileCollection)
+	if !ok {
+		return fmt.Errorf("알 수 없는 컬렉션: %s", reconcileCollection)
+	}
+	strategy, err := newStrategy(cfg)
+	if err != nil {
+		return fmt.Errorf("전략 설정 오류: %w", err)
+	}
+	revision, err := modelRevision(cfg)
+	if err != nil {
+		return err
+	}
+	store, backend, err := newVectorStore(cfg, collections)
+	if err != nil {
+		return fmt.Errorf("벡터 저장소 설정 오류: %w", err)
+	}
+	defer closeVectorStore(store)
+	fmt.Fprintf(log, "    [OK] 컬렉션: %s, 전략: %s, 모델: %s, 벡터 저장소: %s\n", schema.Name, strategy.Version(), revision, backend)
+	fmt.Fprintln(log)
+
+	opts := service.IndexOptions{
+		ModelRevision: revision,
+		PageSize:      reconcilePageSize,
+		BatchSize:     reconcileBatchSize,
+		Workers:       reconcileWorkers,
+	}
+
+	// 3. 비교
+	fmt.Fprintln(log, "[3] DB ↔ 벡터 저장소 비교...")
+	indexer := service.NewIndexer(repository.NewAlcoholRepository(db), strategy, nil, store, schema)
+	report, err := indexer.Reconcile(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("비교 실패: %w", err)
+	}
+	fmt.Fprintf(log, "    DB %d건, 저장소 %d건\n", report.DBCount, report.StoreCount)
+	fmt.Fprintf(log, "    누락 %d, 고아 %d, 오래됨 %d, 비교 불가 %d (%s)\n",
+		len(report.Missing), len(report.Orphaned), len(report.Stale), len(report.Failures), report.Elapsed.Round(time.Millisecond))
+	fmt.Fprintln(log)
+
+	// 4. 복구 (선택)
+	var repairErr error
+	switch {
+	case !reconcileRepair:
+		fmt.Fprintln(log, "[4] [SKIP] --repair 미지정")
+	case !report.Drifted():
+		fmt.Fprintln(log, "[4] [SKIP] 차이 없음")
+	default:
+		fmt.Fprintln(log, "[4] 복구...")
+		emb, err := newEmbedder(cfg)
+		if err != nil {
+			return fmt.Errorf("임베더 생성 실패: %w", err)
+		}
+		defer emb.Close()
+
+		indexer = service.NewIndexer(repository.NewAlcoholRepository(db), strategy, emb, store, schema)
+		repairErr = indexer.Repair(ctx, report, opts, func(p service.IndexProgress) {
+			fmt.Fprintf(log, "    읽음 %d, 저장 %d, 변경 없음 %d, 실패 %d (last_id=%d)\n", p.Read, p.Indexed, p.Skipped, p.Failed, p.LastID)
+		})
+		fmt.Fprintf(log, "    고아 문서 삭제 %d건\n", report.Repair.Deleted)
+		if report.Repair.Index != nil {
+			fmt.Fprintf(log, "    재인덱싱 저장 %d건, 실패 %d건\n", report.Repair.Index.Indexed, report.Repair.Index.Failed)
+		}
+	}
+	fmt.Fprintln(log)
+
+	// 5. 보고서 (복구가 중간에 실패해도 남긴다)
+	if err := writeReconcileReport(report, reconcileOutput); err != nil {
+		return fmt.Errorf("보고서 저장 실패: %w", err)
+	}
+	if reconcileOutput != "-" {
+		fmt.Fprintf(log, "[5] [OK] 보고서: %s\n", reconcileOutput)
+		fmt.Fprintln(log)
+	}
+	if repairErr != nil {
+		return fmt.Errorf("복구 중단: %w", repairErr)
+	}
+
+	fmt.Fprintln(log, "=== Reconcile Completed ===")
+	return nil
+}
+
+func writeReconcileReport(report *service.ReconcileReport, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		_, err = fmt.Println(string(data))
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+var (
+	schemaOutDir string
+	schemaCheck  bool
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Vespa 애플리케이션 패키지 생성",
+	Long: `Go 스키마 정의로부터 Vespa 애플리케이션 패키지(services.xml, schemas/*.sd)를 생성한다.
+--check 옵션은 파일을 기록하지 않고 생성 결과와 디스크 내용이 다르면 실패한다.`,
+	// 스키마 생성은 환경변수 설정이 필요 없다
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE:              runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutDir, "out", "o", "vespa-app", "애플리케이션 패키지 디렉토리")
+	schemaCmd.Flags().BoolVar(&schemaCheck, "check", false, "생성 결과와 디스크 내용 비교만 수행")
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	fmt.Println("=== Embedder Worker - Vespa Schema ===")
+	fmt.Println()
+
+	app := schema.DefaultApplication()
+
+	if schemaCheck {
+		fmt.Printf("[1] 생성 결과 비교: %s\n", schemaOutDir)
+		changed, err := schema.Diff(app, schemaOutDir)
+		if err != nil {
+			return err
+		}
+		if len(changed) > 0 {
+			return fmt.Errorf("스키마 불일치 ('schema' 명령으로 재생성 필요): %s", strings.Join(changed, ", "))
+		}
+		fmt.Println("    [OK] 스키마 일치")
+		return nil
+	}
+
+	fmt.Printf("[1] 애플리케이션 패키지 생성: %s\n", schemaOutDir)
+	if err := schema.Write(app, schemaOutDir); err != nil {
+		return fmt.Errorf("스키마 생성 실패: %w", err)
+	}
+	files := schema.Files(app)
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Printf("    [OK] %s\n", path)
+	}
+	fmt.Println()
+
+	fmt.Println("=== Schema Generated ===")
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/query"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// searchOptions SEARCH_* 설정 → 검색 설정 (컬렉션, 융합 방식, 가중치 필드, 다양화 확인)
+func searchOptions(cfg *config.Config, collections *repository.Collections) (service.SearchOptions, error) {
+	collection, ok := collections.Get(cfg.Search.Collection)
+	if !ok {
+		return service.SearchOptions{}, fmt.Errorf("알 수 없는 검색 컬렉션: %s", cfg.Search.Collection)
+	}
+	fusion, err := repository.ParseFusion(cfg.Search.Fusion)
+	if err != nil {
+		return service.SearchOptions{}, err
+	}
+	weights, err := parseWeights(cfg.Search.Weights)
+	if err != nil {
+		return service.SearchOptions{}, err
+	}
+
+	fields := map[string]bool{schema.SparseKeywords: true}
+	for _, v := range collection.Vectors {
+		fields[v.Name] = true
+	}
+	for field := range weights {
+		if !fields[field] {
+			return service.SearchOptions{}, fmt.Errorf("SEARCH_WEIGHTS: %s 컬렉션에 없는 필드: %s", collection.Name, field)
+		}
+	}
+	diversity := service.Diversity{
+		MMR:    cfg.Search.MMR,
+		Lambda: cfg.Search.MMRLambda,
+		MaxPerGroup: map[string]int{
+			"distillery_id": cfg.Search.MaxPerDistillery,
+			"categoryGroup": cfg.Search.MaxPerCategoryGroup,
+		},
+	}
+	if err := diversity.Validate(); err != nil {
+		return service.SearchOptions{}, fmt.Errorf("SEARCH_MMR_LAMBDA/SEARCH_MAX_PER_*: %w", err)
+	}
+	return service.SearchOptions{
+		Collection:          cfg.Search.Collection,
+		Fusion:              fusion,
+		Weights:             weights,
+		Diversity:           diversity,
+		DiversityCandidates: cfg.Search.DiversityCandidates,
+	}, nil
+}
+
+// parseWeights "flavor=2,keywords=0.5" → 필드별 가중치
+func parseWeights(value string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		field, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("SEARCH_WEIGHTS 형식 오류 (필드=가중치): %q", pair)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("SEARCH_WEIGHTS 가중치 오류: %q", pair)
+		}
+		weights[strings.TrimSpace(field)] = weight
+	}
+	return weights, nil
+}
+
+// startQueryParser SEARCH_PARSE_QUERY면 DB 사전으로 검색어 해석기 생성
+// SEARCH_DICTIONARY_REFRESH마다 사전을 다시 읽어 새 지역/증류소를 반영한다
+func startQueryParser(cfg *config.Config, alcohols *repository.AlcoholRepository) (parser *query.Parser, stop func(), err error) {
+	if !cfg.Search.ParseQuery {
+		fmt.Println("    [SKIP] 검색어 해석 꺼짐 (SEARCH_PARSE_QUERY=false)")
+		return nil, func() {}, nil
+	}
+	dict, err := service.LoadQueryDictionary(context.Background(), alcohols)
+	if err != nil {
+		return nil, nil, fmt.Errorf("검색어 사전 로드 실패: %w", err)
+	}
+	parser = query.NewParser(dict)
+	fmt.Printf("    [OK] 검색어 사전: 지역 %d, 증류소 %d\n", len(dict.Regions), len(dict.Distilleries))
+
+	interval := cfg.Search.DictionaryRefresh
+	if interval <= 0 {
+		return parser, func() {}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			dict, err := service.LoadQueryDictionary(ctx, alcohols)
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Printf("[Search] [WARN] 검색어 사전 갱신 실패: %v\n", err)
+				}
+				continue
+			}
+			parser.SetDictionary(dict)
+		}
+	}()
+	return parser, func() {
+		cancel()
+		<-done
+	}, nil
+}
@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -10,13 +17,18 @@ import (
 	"github.com/Whale0928/embedding-worker/internal/config"
 	"github.com/Whale0928/embedding-worker/pkg/handler"
 	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/service"
 )
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "HTTP 서버 시작",
-	Long:  `임베딩 워커 HTTP 서버를 시작한다.`,
-	RunE:  runServe,
+	Long: `임베딩 워커 HTTP 서버를 시작한다.
+VECTOR_BACKEND=embedded이면 외부 벡터 DB 없이 프로세스 내 HNSW 인덱스로 동작한다.
+SYNC_INTERVAL을 설정하면 주기적으로 증분 동기화(index --incremental)를 실행한다.
+GET /search는 SEARCH_COLLECTION(alias 가능)을 하이브리드 검색하므로 모델이 다운로드되어 있어야 한다.
+RERANKER_MODEL_REPO를 설정하면 POST /rerank와 /search?rerank=true 재순위를 제공한다.`,
+	RunE: runServe,
 }
 
 func init() {
@@ -47,42 +59,157 @@ func runServe(cmd *cobra.Command, args []string) error {
 	fmt.Printf("    [OK] DB 연결 성공: %s:%s/%s\n", cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
 	fmt.Println()
 
-	// 2. Vespa 클라이언트 생성
-	fmt.Println("[2] Vespa 클라이언트 설정...")
-	vespaClient := repository.NewVespaClient(
-		fmt.Sprintf("http://%s:%s", cfg.Vector.Host, cfg.Vector.Port),
-	)
-	fmt.Println("    [OK] Vespa 클라이언트 생성 완료")
+	// 2. 벡터 저장소 생성
+	fmt.Println("[2] 벡터 저장소 설정...")
+	collections, err := buildCollections(cfg)
+	if err != nil {
+		return fmt.Errorf("컬렉션 설정 오류: %w", err)
+	}
+	store, backend, err := newVectorStore(cfg, collections)
+	if err != nil {
+		return fmt.Errorf("벡터 저장소 설정 오류: %w", err)
+	}
+	defer closeVectorStore(store)
+	fmt.Printf("    [OK] 벡터 저장소 생성 완료: %s\n", backend)
 	fmt.Println()
 
-	// 3. Echo 서버 설정
-	fmt.Println("[3] HTTP 서버 설정...")
+	// 3. 컬렉션 검증
+	fmt.Println("[3] 컬렉션 검증...")
+	// 배포된 애플리케이션 대조 검증은 Vespa만 지원
+	if backend == repository.BackendVespa {
+		configClient := repository.NewVespaConfigClient(
+			fmt.Sprintf("http://%s:%s", cfg.Vector.Host, cfg.Vector.ConfigPort),
+		)
+		for _, schema := range collections.All() {
+			if err := configClient.Validate(schema); err != nil {
+				return fmt.Errorf("컬렉션 검증 실패: %w", err)
+			}
+			fmt.Printf("    [OK] %s (%s/%s, cluster=%s)\n", schema.Name, schema.Namespace, schema.DocType, schema.Cluster)
+		}
+	} else {
+		fmt.Printf("    [SKIP] %s 백엔드는 배포 검증 생략\n", backend)
+	}
+	// 블루/그린 alias 활성 버전 (ALIAS_REFRESH_INTERVAL마다 다시 읽는다)
+	stopAliases, err := applyAliases(cfg, db, collections)
+	if err != nil {
+		return fmt.Errorf("alias 설정 오류: %w", err)
+	}
+	defer stopAliases()
+	fmt.Println()
+
+	// 4. 임베더 로드 (검색어 임베딩, 백그라운드 인덱싱 공용)
+	fmt.Println("[4] 임베더 로드...")
+	emb, err := newEmbedder(cfg)
+	if err != nil {
+		return fmt.Errorf("임베더 생성 실패: %w", err)
+	}
+	defer func() {
+		if err := emb.Close(); err != nil {
+			fmt.Printf("    [WARN] 임베더 정리 실패: %v\n", err)
+		}
+	}()
+	searchOpts, err := searchOptions(cfg, collections)
+	if err != nil {
+		return fmt.Errorf("검색 설정 오류: %w", err)
+	}
+	alcohols := repository.NewAlcoholRepository(db)
+	parser, stopParser, err := startQueryParser(cfg, alcohols)
+	if err != nil {
+		return fmt.Errorf("검색어 해석 설정 오류: %w", err)
+	}
+	defer stopParser()
+	searchOpts.Parser = parser
+	crossEncoder, err := newCrossEncoder(cfg)
+	if err != nil {
+		return fmt.Errorf("재순위 모델 생성 실패: %w", err)
+	}
+	var reranker *service.Reranker
+	if crossEncoder != nil {
+		defer func() {
+			if err := crossEncoder.Close(); err != nil {
+				fmt.Printf("    [WARN] 재순위 모델 정리 실패: %v\n", err)
+			}
+		}()
+		reranker = service.NewReranker(crossEncoder, cfg.Reranker.BatchSize)
+		searchOpts.Reranker = reranker
+		searchOpts.Rerank = cfg.Search.Rerank
+		searchOpts.RerankCandidates = cfg.Search.RerankCandidates
+		fmt.Printf("    [OK] 재순위 모델: %s (기본 재순위=%v, 후보 %d)\n", cfg.Reranker.ModelRepo, cfg.Search.Rerank, cfg.Search.RerankCandidates)
+	} else {
+		fmt.Println("    [SKIP] 재순위 모델 없음 (RERANKER_MODEL_REPO)")
+	}
+	searcher := service.NewSearcher(emb, store, collections, searchOpts)
+	fmt.Printf("    [OK] 검색 컬렉션: %s (fusion=%s, weights=%v)\n", searchOpts.Collection, searchOpts.Fusion, searchOpts.Weights)
+	fmt.Println()
+
+	// 5. 백그라운드 인덱싱 (SYNC_INTERVAL, OUTBOX_INTERVAL)
+	fmt.Println("[5] 증분 동기화/outbox 설정...")
+	stopIndexing, err := startBackgroundIndexing(cfg, db, store, collections, emb)
+	if err != nil {
+		return fmt.Errorf("백그라운드 인덱싱 설정 오류: %w", err)
+	}
+	defer stopIndexing()
+	fmt.Println()
+
+	// 6. Echo 서버 설정
+	fmt.Println("[6] HTTP 서버 설정...")
 	e := echo.New()
 	e.HideBanner = true
-	defer func() { _ = e.Close() }()
 
 	// 미들웨어
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
 	// 라우터 등록
-	registerRoutes(e, vespaClient)
+	registerRoutes(e, store, collections, alcohols, searcher, reranker)
 	fmt.Println("    [OK] 라우터 등록 완료")
 	fmt.Println()
 
-	// 4. 서버 시작
+	// 7. 서버 시작
 	addr := fmt.Sprintf(":%s", cfg.HttpConfig.Port)
-	fmt.Printf("[4] 서버 시작: http://localhost%s\n", addr)
+	fmt.Printf("[7] 서버 시작: http://localhost%s\n", addr)
 	fmt.Println()
 
-	return e.Start(addr)
+	return startServer(e, addr)
+}
+
+// startServer 서버 실행 후 SIGINT/SIGTERM 수신 시 graceful shutdown
+func startServer(e *echo.Echo, addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := e.Start(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	fmt.Println()
+	fmt.Println("[Shutdown] 서버 종료 중...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return e.Shutdown(shutdownCtx)
 }
 
-func registerRoutes(e *echo.Echo, vespaClient *repository.VespaClient) {
+func registerRoutes(e *echo.Echo, store repository.VectorStore, collections *repository.Collections, alcohols *repository.AlcoholRepository, searcher *service.Searcher, reranker *service.Reranker) {
 	// Health check
 	healthHandler := handler.NewHealthHandler()
-	vectorHandler := handler.NewVectorHandler(vespaClient)
+	vectorHandler := handler.NewVectorHandler(store, collections)
+	alcoholHandler := handler.NewAlcoholHandler(alcohols)
+	searchHandler := handler.NewSearchHandler(searcher)
+	rerankHandler := handler.NewRerankHandler(reranker)
 
 	healthHandler.Register(e)
 	vectorHandler.Register(e)
+	alcoholHandler.Register(e)
+	searchHandler.Register(e)
+	rerankHandler.Register(e)
 }
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// newVectorStore 설정의 VECTOR_BACKEND에 맞는 벡터 저장소 생성
+func newVectorStore(cfg *config.Config, collections *repository.Collections) (repository.VectorStore, repository.Backend, error) {
+	backend, err := repository.ParseBackend(cfg.Vector.Backend)
+	if err != nil {
+		return nil, "", err
+	}
+
+	baseURL := fmt.Sprintf("http://%s:%s", cfg.Vector.Host, cfg.Vector.Port)
+	switch backend {
+	case repository.BackendQdrant:
+		return repository.NewQdrantStore(baseURL, cfg.Vector.APIKey), backend, nil
+	case repository.BackendMemory:
+		return repository.NewMemoryStore(), backend, nil
+	case repository.BackendEmbedded:
+		store, err := repository.NewEmbeddedStore(cfg.Vector.DataDir, collections.All(), cfg.Vector.SaveInterval)
+		if err != nil {
+			return nil, "", err
+		}
+		return store, backend, nil
+	default:
+		return repository.NewVespaStore(repository.NewVespaClient(baseURL)), backend, nil
+	}
+}
+
+// closeVectorStore 종료가 필요한 저장소(embedded 스냅샷 등) 정리
+func closeVectorStore(store repository.VectorStore) {
+	closer, ok := store.(interface{ Close() error })
+	if !ok {
+		return
+	}
+	fmt.Println("[Cleanup] 벡터 저장소 정리...")
+	if err := closer.Close(); err != nil {
+		fmt.Printf("    [WARN] 벡터 저장소 정리 실패: %v\n", err)
+		return
+	}
+	fmt.Println("    [OK] 벡터 저장소 정리 완료")
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// newStrategy 전략 정의 파일이 있으면 템플릿 전략, 없으면 내장 v2 전략
+func newStrategy(cfg *config.Config) (service.Strategy, error) {
+	if cfg.Strategy == nil {
+		return service.NewWhiskyV2Strategy(), nil
+	}
+	return service.NewTemplateStrategy(service.StrategyDefinition{
+		Name:       cfg.Strategy.Name,
+		Vectors:    cfg.Strategy.Vectors,
+		RAGContext: cfg.Strategy.RAGContext,
+	})
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Whale0928/embedding-worker/internal/config"
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// startBackgroundIndexing SYNC_INTERVAL 증분 동기화와 OUTBOX_INTERVAL outbox 소비를 검색 API와 같은 임베더로 실행
+// SYNC_COLLECTION이 alias면 실행할 때마다 활성 버전을 다시 조회한다 (전환 후 재시작 불필요)
+// 반환된 stop은 진행 중인 작업이 끝날 때까지 기다린다 (임베더는 닫지 않는다), 둘 다 0이면 아무것도 하지 않는다
+func startBackgroundIndexing(cfg *config.Config, db *gorm.DB, store repository.VectorStore, collections *repository.Collections, emb embedder.Embedder) (stop func(), err error) {
+	syncInterval, outboxInterval := cfg.Index.SyncInterval, cfg.Index.OutboxInterval
+	if syncInterval <= 0 && outboxInterval <= 0 {
+		fmt.Println("    [SKIP] SYNC_INTERVAL, OUTBOX_INTERVAL 미설정")
+		return func() {}, nil
+	}
+
+	schema, ok := collections.Get(cfg.Index.SyncCollection)
+	if !ok {
+		return nil, fmt.Errorf("알 수 없는 동기화 컬렉션: %s", cfg.Index.SyncCollection)
+	}
+	strategy, err := newStrategy(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("전략 설정 오류: %w", err)
+	}
+	var jobs repository.JobStore
+	if syncInterval > 0 {
+		jobs, err = newJobStore(cfg, db)
+		if err != nil {
+			return nil, fmt.Errorf("작업 저장소 설정 오류: %w", err)
+		}
+	}
+	var outbox *repository.OutboxRepository
+	if outboxInterval > 0 {
+		outbox, err = repository.NewOutboxRepository(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	revision, err := modelRevision(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	alcohols := repository.NewAlcoholRepository(db)
+	indexer := func() *service.Indexer {
+		active, _ := collections.Get(cfg.Index.SyncCollection)
+		return service.NewIndexer(alcohols, strategy, emb, store, active)
+	}
+	opts := service.IndexOptions{ModelRevision: revision}
+	ctx, cancel := context.WithCancel(context.Background())
+	var loops sync.WaitGroup
+
+	if syncInterval > 0 {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			every(ctx, syncInterval, func() bool {
+				runSync(ctx, indexer(), jobs, opts, cfg.Index.SyncOverlap)
+				return false
+			})
+		}()
+		fmt.Printf("    [OK] %s 컬렉션 %s마다 증분 동기화\n", schema.Name, syncInterval)
+	}
+	if outboxInterval > 0 {
+		outboxOpts := service.OutboxOptions{
+			BatchSize:   cfg.Index.OutboxBatchSize,
+			MaxAttempts: cfg.Index.OutboxMaxAttempts,
+			Lease:       cfg.Index.OutboxLease,
+			Index:       opts,
+		}
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			every(ctx, outboxInterval, func() bool {
+				return runOutbox(ctx, indexer(), outbox, outboxOpts)
+			})
+		}()
+		fmt.Printf("    [OK] %s 컬렉션 %s마다 outbox 소비\n", schema.Name, outboxInterval)
+	}
+
+	return func() {
+		cancel()
+		loops.Wait()
+	}, nil
+}
+
+// every 바로 한 번 실행 후 interval마다 반복 (fn이 true면 기다리지 않고 다시 실행)
+func every(ctx context.Context, interval time.Duration, fn func() (again bool)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if fn() && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runSync 한 번 동기화하고 결과를 로그로 남긴다 (오류는 다음 주기에 재시도)
+func runSync(ctx context.Context, indexer *service.Indexer, jobs repository.JobStore, opts service.IndexOptions, overlap time.Duration) {
+	report, err := indexer.Sync(ctx, jobs, opts, overlap, nil)
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Printf("[Sync] [WARN] 증분 동기화 실패: %v\n", err)
+		}
+		return
+	}
+	// overlap 구간은 매번 다시 보므로 실제로 저장하거나 실패한 문서가 있을 때만 남긴다
+	if report.Index == nil || report.Index.Indexed+report.Index.Failed == 0 {
+		return
+	}
+	fmt.Printf("[Sync] 변경 %d건: 저장 %d, 변경 없음 %d, 실패 %d (기준 시각 전진: %t)\n",
+		report.Changed, report.Index.Indexed, report.Index.Skipped, report.Index.Failed, report.Advanced)
+}
+
+// runOutbox outbox 한 묶음 처리 후 로그, 꽉 찬 묶음이었으면 true (밀린 이벤트가 더 있다)
+func runOutbox(ctx context.Context, indexer *service.Indexer, outbox *repository.OutboxRepository, opts service.OutboxOptions) bool {
+	report, err := indexer.ConsumeOutbox(ctx, outbox, opts)
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Printf("[Outbox] [WARN] 소비 실패: %v\n", err)
+		}
+		return false
+	}
+	if report.Claimed == 0 {
+		return false
+	}
+	fmt.Printf("[Outbox] 이벤트 %d건: 저장 %d, 변경 없음 %d, 삭제 %d, 재시도 %d, 격리 %d\n",
+		report.Claimed, report.Indexed, report.Skipped, report.Deleted, report.Retried, report.Parked)
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = service.DefaultOutboxBatchSize
+	}
+	return report.Claimed >= batchSize
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// CollectionConfig 벡터 컬렉션 설정 (Vespa namespace/docType/content cluster)
+// 같은 Alias를 가진 컬렉션들은 블루/그린 버전이며, 검색 API는 Alias로 활성 버전을 조회한다
+type CollectionConfig struct {
+	Name      string              `mapstructure:"name"`
+	Alias     string              `mapstructure:"alias"`
+	Namespace string              `mapstructure:"namespace"`
+	DocType   string              `mapstructure:"doc_type"`
+	Cluster   string              `mapstructure:"cluster"`
+	Vectors   []VectorFieldConfig `mapstructure:"vectors"`
+}
+
+// VectorFieldConfig 컬렉션의 dense 벡터 필드
+type VectorFieldConfig struct {
+	Name      string `mapstructure:"name"`
+	Dimension int    `mapstructure:"dimension"`
+}
+
+// loadCollections 컬렉션 설정 파일(YAML) 로드, 파일이 없으면 기본 컬렉션 사용
+func loadCollections(path string) ([]CollectionConfig, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return defaultCollections(), nil
+		}
+		return nil, fmt.Errorf("컬렉션 설정 파일 확인 실패: %w", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("컬렉션 설정 파일 읽기 실패: %w", err)
+	}
+
+	var collections []CollectionConfig
+	if err := v.UnmarshalKey("collections", &collections); err != nil {
+		return nil, fmt.Errorf("컬렉션 설정 파싱 실패: %w", err)
+	}
+	return collections, nil
+}
+
+// defaultCollections 생성된 whisky 스키마와 동일한 기본 컬렉션
+func defaultCollections() []CollectionConfig {
+	vectors := make([]VectorFieldConfig, 0, len(schema.DenseVectors))
+	for _, name := range schema.DenseVectors {
+		vectors = append(vectors, VectorFieldConfig{Name: name, Dimension: schema.EmbeddingDimension})
+	}
+	return []CollectionConfig{
+		{
+			Name:      schema.WhiskyName,
+			Namespace: schema.WhiskyName,
+			DocType:   schema.WhiskyName,
+			Cluster:   schema.WhiskyName,
+			Vectors:   vectors,
+		},
+	}
+}
+
+// validateCollections 컬렉션 설정 필수값/중복 검증
+func validateCollections(collections []CollectionConfig) error {
+	if len(collections) == 0 {
+		return fmt.Errorf("collections: 최소 1개 이상 필요")
+	}
+	seen := make(map[string]bool)
+	for _, c := range collections {
+		if c.Name == "" || c.Namespace == "" || c.DocType == "" || c.Cluster == "" {
+			return fmt.Errorf("collections: name, namespace, doc_type, cluster는 필수 (%q)", c.Name)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("collections: 중복된 이름 %q", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Alias == c.Name {
+			return fmt.Errorf("collections: %q alias는 컬렉션 이름과 달라야 함", c.Name)
+		}
+		for _, v := range c.Vectors {
+			if v.Name == "" || v.Dimension <= 0 {
+				return fmt.Errorf("collections: %q 벡터 필드 설정 오류 (%q, %d)", c.Name, v.Name, v.Dimension)
+			}
+		}
+	}
+	for _, c := range collections {
+		if c.Alias != "" && seen[c.Alias] {
+			return fmt.Errorf("collections: alias %q가 컬렉션 이름과 겹침", c.Alias)
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,7 +14,16 @@ type Config struct {
 	HuggingFace HuggingFaceConfig
 	DB          DBConfig
 	Vector      VectorConfig
+	Embedder    EmbedderConfig
+	Reranker    RerankerConfig
+	Index       IndexConfig
+	Search      SearchConfig
 	HttpConfig  EchoHttpConfig
+	Collections []CollectionConfig
+	// Strategy 템플릿 전략 정의 (nil이면 내장 v2 전략)
+	Strategy *StrategyConfig
+	// StrategyFile 전략 정의 YAML 경로
+	StrategyFile string
 }
 
 // HuggingFaceConfig HuggingFace 관련 설정
@@ -34,8 +44,92 @@ type DBConfig struct {
 
 // VectorConfig 벡터 DB 설정
 type VectorConfig struct {
-	Host string `mapstructure:"VECTOR_HOST"`
-	Port string `mapstructure:"VECTOR_PORT"`
+	// Backend 벡터 저장소 종류 (vespa, qdrant, memory, embedded)
+	Backend    string `mapstructure:"VECTOR_BACKEND"`
+	APIKey     string `mapstructure:"VECTOR_API_KEY"`
+	Host       string `mapstructure:"VECTOR_HOST"`
+	Port       string `mapstructure:"VECTOR_PORT"`
+	ConfigPort string `mapstructure:"VECTOR_CONFIG_PORT"`
+	// DataDir embedded 백엔드 스냅샷 디렉토리 (기본: 캐시 디렉토리/vectors)
+	DataDir string `mapstructure:"VECTOR_DATA_DIR"`
+	// SaveInterval embedded 백엔드 스냅샷 주기 (0이면 종료 시에만 저장)
+	SaveInterval time.Duration `mapstructure:"VECTOR_SAVE_INTERVAL"`
+	// CollectionsFile 컬렉션 레지스트리 YAML 경로
+	CollectionsFile string `mapstructure:"COLLECTIONS_FILE"`
+}
+
+// EmbedderConfig ONNX 임베더 설정
+type EmbedderConfig struct {
+	// LibraryPath ONNX Runtime 공유 라이브러리 경로 (비우면 OS별 기본 경로)
+	LibraryPath string `mapstructure:"ONNX_RUNTIME_LIB"`
+	// MaxLength 토큰 최대 길이
+	MaxLength int `mapstructure:"EMBED_MAX_LENGTH"`
+	// Threads ONNX Runtime 연산 스레드 수 (0이면 기본값)
+	Threads int `mapstructure:"EMBED_THREADS"`
+}
+
+// RerankerConfig cross-encoder 재순위 모델 설정 (ONNX Runtime, 스레드 수는 임베더와 공유)
+type RerankerConfig struct {
+	// ModelRepo HuggingFace ONNX 재순위 모델 저장소 (비우면 재순위 끔)
+	ModelRepo string `mapstructure:"RERANKER_MODEL_REPO"`
+	// MaxLength (질의, 문서) 쌍 토큰 최대 길이
+	MaxLength int `mapstructure:"RERANK_MAX_LENGTH"`
+	// BatchSize 한 번에 추론하는 쌍 수
+	BatchSize int `mapstructure:"RERANK_BATCH_SIZE"`
+	// CacheDir 모델 다운로드 경로 (환경변수 아님, 코드에서 설정)
+	CacheDir string
+}
+
+// IndexConfig 인덱싱 작업 기록 설정
+type IndexConfig struct {
+	// JobStore 작업 기록/컬렉션 alias 저장소 (db, file), 비우면 embedded/memory 백엔드는 file, 그 외는 db
+	JobStore string `mapstructure:"JOB_STORE"`
+	// JobDir file 저장소 디렉토리 (기본: 캐시 디렉토리/jobs)
+	JobDir string `mapstructure:"JOB_DIR"`
+	// SyncInterval serve 중 증분 동기화 주기 (0이면 끔, 예: 5m)
+	SyncInterval time.Duration `mapstructure:"SYNC_INTERVAL"`
+	// SyncOverlap 증분 동기화가 기준 시각보다 앞당겨 다시 조회하는 구간 (늦게 커밋된 행 대비, 0이면 겹치지 않음)
+	SyncOverlap time.Duration `mapstructure:"SYNC_OVERLAP"`
+	// SyncCollection 증분 동기화/outbox 소비 대상 컬렉션
+	SyncCollection string `mapstructure:"SYNC_COLLECTION"`
+	// OutboxInterval serve 중 embedding_outbox 폴링 주기 (0이면 끔, 예: 2s)
+	OutboxInterval time.Duration `mapstructure:"OUTBOX_INTERVAL"`
+	// OutboxBatchSize 한 번에 잠그는 이벤트 수
+	OutboxBatchSize int `mapstructure:"OUTBOX_BATCH_SIZE"`
+	// OutboxMaxAttempts 이 횟수만큼 실패한 이벤트는 격리(parked_at)
+	OutboxMaxAttempts int `mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+	// OutboxLease 가져간 이벤트를 다른 워커가 다시 가져가지 못하는 시간 (처리 중 죽으면 이후 재시도)
+	OutboxLease time.Duration `mapstructure:"OUTBOX_LEASE"`
+	// AliasRefreshInterval serve 중 컬렉션 alias(활성 버전)를 다시 읽는 주기 (0이면 시작 시 한 번)
+	AliasRefreshInterval time.Duration `mapstructure:"ALIAS_REFRESH_INTERVAL"`
+}
+
+// SearchConfig 검색 API 설정
+type SearchConfig struct {
+	// Collection 검색 대상 컬렉션 (alias면 활성 버전)
+	Collection string `mapstructure:"SEARCH_COLLECTION"`
+	// Fusion 하이브리드 검색 융합 방식 (rrf, linear, dbsf, zscore)
+	Fusion string `mapstructure:"SEARCH_FUSION"`
+	// Weights 필드별 융합 가중치 (예: flavor=2,keywords=0.5), 없는 필드는 1.0
+	Weights string `mapstructure:"SEARCH_WEIGHTS"`
+	// ParseQuery 검색어에서 도수/숙성 연수/용량/지역/증류소를 뽑아 필터로 쓸지
+	ParseQuery bool `mapstructure:"SEARCH_PARSE_QUERY"`
+	// DictionaryRefresh 지역/증류소 이름 사전을 DB에서 다시 읽는 주기 (0이면 시작 시 한 번)
+	DictionaryRefresh time.Duration `mapstructure:"SEARCH_DICTIONARY_REFRESH"`
+	// Rerank 하이브리드 결과를 기본으로 재순위할지 (요청마다 rerank=로 바꿀 수 있다)
+	Rerank bool `mapstructure:"SEARCH_RERANK"`
+	// RerankCandidates 재순위할 융합 상위 후보 수
+	RerankCandidates int `mapstructure:"SEARCH_RERANK_CANDIDATES"`
+	// MMR 저장 벡터로 결과를 MMR 재정렬할지 (요청마다 mmr=로 바꿀 수 있다)
+	MMR bool `mapstructure:"SEARCH_MMR"`
+	// MMRLambda MMR 관련도 비중 (1이면 관련도만, 0이면 다양성만)
+	MMRLambda float64 `mapstructure:"SEARCH_MMR_LAMBDA"`
+	// MaxPerDistillery 증류소별 최대 결과 수 (0이면 제한 없음)
+	MaxPerDistillery int `mapstructure:"SEARCH_MAX_PER_DISTILLERY"`
+	// MaxPerCategoryGroup 카테고리 그룹별 최대 결과 수 (0이면 제한 없음)
+	MaxPerCategoryGroup int `mapstructure:"SEARCH_MAX_PER_CATEGORY_GROUP"`
+	// DiversityCandidates MMR/그룹 제한을 적용할 상위 후보 수
+	DiversityCandidates int `mapstructure:"SEARCH_DIVERSITY_CANDIDATES"`
 }
 
 type EchoHttpConfig struct {
@@ -59,8 +153,33 @@ func Load() (*Config, error) {
 	viper.SetDefault("HOST", "0.0.0.0")
 	viper.SetDefault("PORT", "8000")
 	viper.SetDefault("DB_PORT", "3306")
+	viper.SetDefault("VECTOR_BACKEND", "vespa")
 	viper.SetDefault("VECTOR_HOST", "localhost")
 	viper.SetDefault("VECTOR_PORT", "8080")
+	viper.SetDefault("VECTOR_CONFIG_PORT", "19071")
+	viper.SetDefault("VECTOR_SAVE_INTERVAL", "30s")
+	viper.SetDefault("COLLECTIONS_FILE", "collections.yaml")
+	viper.SetDefault("STRATEGY_FILE", "strategy.yaml")
+	viper.SetDefault("EMBED_MAX_LENGTH", 512)
+	viper.SetDefault("SYNC_INTERVAL", "0s")
+	viper.SetDefault("SYNC_OVERLAP", "5m")
+	viper.SetDefault("SYNC_COLLECTION", "whisky")
+	viper.SetDefault("OUTBOX_INTERVAL", "0s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("OUTBOX_LEASE", "5m")
+	viper.SetDefault("ALIAS_REFRESH_INTERVAL", "10s")
+	viper.SetDefault("SEARCH_COLLECTION", "whisky")
+	viper.SetDefault("SEARCH_FUSION", "rrf")
+	viper.SetDefault("SEARCH_PARSE_QUERY", true)
+	viper.SetDefault("SEARCH_DICTIONARY_REFRESH", "10m")
+	viper.SetDefault("SEARCH_RERANK", false)
+	viper.SetDefault("SEARCH_RERANK_CANDIDATES", 50)
+	viper.SetDefault("SEARCH_MMR", false)
+	viper.SetDefault("SEARCH_MMR_LAMBDA", 0.7)
+	viper.SetDefault("SEARCH_DIVERSITY_CANDIDATES", 50)
+	viper.SetDefault("RERANK_MAX_LENGTH", 512)
+	viper.SetDefault("RERANK_BATCH_SIZE", 16)
 
 	cfg := &Config{}
 
@@ -84,12 +203,54 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("vector 설정 로드 실패: %w", err)
 	}
 
+	// Embedder 설정
+	if err := viper.Unmarshal(&cfg.Embedder); err != nil {
+		return nil, fmt.Errorf("embedder 설정 로드 실패: %w", err)
+	}
+
+	// Reranker 설정
+	if err := viper.Unmarshal(&cfg.Reranker); err != nil {
+		return nil, fmt.Errorf("reranker 설정 로드 실패: %w", err)
+	}
+
+	// Index 설정
+	if err := viper.Unmarshal(&cfg.Index); err != nil {
+		return nil, fmt.Errorf("index 설정 로드 실패: %w", err)
+	}
+
+	// Search 설정
+	if err := viper.Unmarshal(&cfg.Search); err != nil {
+		return nil, fmt.Errorf("search 설정 로드 실패: %w", err)
+	}
+
+	// 컬렉션 레지스트리
+	collections, err := loadCollections(cfg.Vector.CollectionsFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Collections = collections
+
+	// 임베딩 전략 정의
+	cfg.StrategyFile = viper.GetString("STRATEGY_FILE")
+	strategy, err := loadStrategy(cfg.StrategyFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Strategy = strategy
+
 	// CacheDir 설정 (환경변수 아님)
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("홈 디렉토리 조회 실패: %w", err)
 	}
 	cfg.HuggingFace.CacheDir = filepath.Join(homeDir, ".cache", "embedding-worker")
+	cfg.Reranker.CacheDir = filepath.Join(cfg.HuggingFace.CacheDir, "reranker")
+	if cfg.Vector.DataDir == "" {
+		cfg.Vector.DataDir = filepath.Join(cfg.HuggingFace.CacheDir, "vectors")
+	}
+	if cfg.Index.JobDir == "" {
+		cfg.Index.JobDir = filepath.Join(cfg.HuggingFace.CacheDir, "jobs")
+	}
 
 	// 필수값 검증
 	if err := cfg.validate(); err != nil {
@@ -116,6 +277,9 @@ func (c *Config) validate() error {
 	if c.DB.User == "" {
 		return fmt.Errorf("DB_USER is required")
 	}
+	if err := validateCollections(c.Collections); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// StrategyConfig 템플릿 기반 임베딩 전략 정의 (벡터 이름 → text/template)
+type StrategyConfig struct {
+	Name       string            `mapstructure:"name"`
+	RAGContext string            `mapstructure:"rag_context"`
+	Vectors    map[string]string `mapstructure:"vectors"`
+}
+
+// loadStrategy 전략 정의 파일(YAML) 로드, 파일이 없으면 nil (내장 v2 전략 사용)
+func loadStrategy(path string) (*StrategyConfig, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("전략 정의 파일 확인 실패: %w", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("전략 정의 파일 읽기 실패: %w", err)
+	}
+
+	var strategy StrategyConfig
+	if err := v.Unmarshal(&strategy); err != nil {
+		return nil, fmt.Errorf("전략 정의 파싱 실패: %w", err)
+	}
+	return &strategy, nil
+}
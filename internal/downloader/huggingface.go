@@ -1,6 +1,9 @@
 package downloader
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,8 +32,21 @@ var ModelFiles = []string{
 	"tokenizer.json",
 	"tokenizer_config.json",
 	"special_tokens_map.json",
+	"sparse_linear.pt",
 }
 
+// OptionalModelFiles 저장소에 없어도 되는 파일 (작은 모델은 외부 가중치 파일이 없다)
+// sparse_linear.pt는 BGE-M3 sparse 헤드로, 없으면 토큰 빈도 sparse를 쓴다
+var OptionalModelFiles = map[string]bool{
+	"model.onnx.data":         true,
+	"tokenizer_config.json":   true,
+	"special_tokens_map.json": true,
+	"sparse_linear.pt":        true,
+}
+
+// errNotFound 저장소에 파일 없음 (HTTP 404)
+var errNotFound = errors.New("file not found in repository")
+
 // Download 모든 모델 파일을 다운로드
 func (d *HuggingFaceDownloader) Download() error {
 	// 캐시 디렉토리 생성
@@ -48,7 +64,12 @@ func (d *HuggingFaceDownloader) Download() error {
 		}
 
 		fmt.Printf("[DOWNLOAD] %s ...\n", filename)
-		if err := d.downloadFile(filename, localPath); err != nil {
+		err := d.downloadFile(filename, localPath)
+		if errors.Is(err, errNotFound) && OptionalModelFiles[filename] {
+			fmt.Printf("[SKIP] %s not in repository (optional)\n", filename)
+			continue
+		}
+		if err != nil {
 			return fmt.Errorf("failed to download %s: %w", filename, err)
 		}
 		fmt.Printf("[OK] %s downloaded\n", filename)
@@ -81,6 +102,9 @@ func (d *HuggingFaceDownloader) downloadFile(filename, localPath string) error {
 		}
 	}(resp.Body)
 
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
@@ -144,3 +168,28 @@ func (pr *progressReader) Read(p []byte) (int, error) {
 
 	return n, err
 }
+
+// GetSparseHeadPath sparse 헤드 파일 경로 반환
+func (d *HuggingFaceDownloader) GetSparseHeadPath() string {
+	return filepath.Join(d.cacheDir, "sparse_linear.pt")
+}
+
+// ModelRevision 저장소 이름 + model.onnx/tokenizer.json(+ sparse_linear.pt) 내용 해시 (다운로드한 파일이 바뀌면 달라진다)
+func (d *HuggingFaceDownloader) ModelRevision() (string, error) {
+	h := sha256.New()
+	for _, path := range []string{d.GetModelPath(), d.GetTokenizerPath(), d.GetSparseHeadPath()} {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) && path == d.GetSparseHeadPath() {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s@%s", d.repo, hex.EncodeToString(h.Sum(nil))[:12]), nil
+}
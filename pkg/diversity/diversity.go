@@ -0,0 +1,176 @@
+// Package diversity 검색 결과 다양화: MMR(Maximal Marginal Relevance) 재정렬과 그룹별 최대 개수 제한
+// 비슷한 문서(같은 증류소의 다른 표현 등)가 상위를 채우지 않도록 관련도와 이미 고른 문서와의 유사도를 함께 본다
+package diversity
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultLambda MMR 관련도 비중 기본값
+const DefaultLambda = 0.7
+
+// Candidate 다양화 후보 (관련도 내림차순으로 넘긴다)
+type Candidate struct {
+	ID int64
+	// Relevance 관련도 점수 (융합/재순위 점수, 후보 안에서 min-max 정규화해 쓴다)
+	Relevance float64
+	// Vectors 저장된 named dense 벡터 (MMR 유사도 계산용)
+	Vectors map[string][]float32
+	// Groups 그룹 필드 → 값 (distillery_id → "12"), 없는 필드는 제한하지 않는다
+	Groups map[string]string
+}
+
+// Options 다양화 설정
+type Options struct {
+	// MMR false면 관련도 순서를 유지하고 그룹 제한만 적용
+	MMR bool
+	// Lambda 관련도 비중 [0, 1] (1이면 관련도만, 0이면 다양성만)
+	Lambda float64
+	// Fields 유사도에 쓸 벡터 필드 (비우면 두 후보에 모두 있는 필드 전부의 평균)
+	Fields []string
+	// MaxPerGroup 그룹 필드별 최대 결과 수 (0 이하면 제한 없음)
+	MaxPerGroup map[string]int
+	// Limit 고를 개수 (0이면 가능한 만큼)
+	Limit int
+}
+
+// Select 후보 중 고른 순서대로 인덱스 반환
+// 매 단계 λ·관련도 − (1−λ)·(이미 고른 문서와의 최대 유사도)가 가장 큰 후보를 고르고, 동점이면 앞쪽 후보
+// 그룹 제한을 넘는 후보는 건너뛰므로 결과가 Limit보다 적을 수 있다
+func Select(candidates []Candidate, opts Options) []int {
+	limit := opts.Limit
+	if limit <= 0 || limit > len(candidates) {
+		limit = len(candidates)
+	}
+	relevance := normalize(candidates)
+	// maxSim[i] 후보 i와 이미 고른 문서 사이의 최대 유사도 (음수 유사도도 그대로 쓴다)
+	maxSim := make([]float64, len(candidates))
+	for i := range maxSim {
+		maxSim[i] = math.Inf(-1)
+	}
+	used := make([]bool, len(candidates))
+	counts := make(map[string]map[string]int, len(opts.MaxPerGroup))
+
+	selected := make([]int, 0, limit)
+	for len(selected) < limit {
+		best, bestScore := -1, math.Inf(-1)
+		for i, c := range candidates {
+			if used[i] || !withinCaps(c, opts.MaxPerGroup, counts) {
+				continue
+			}
+			score := relevance[i]
+			if opts.MMR && len(selected) > 0 {
+				score = opts.Lambda*relevance[i] - (1-opts.Lambda)*maxSim[i]
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+			// MMR이 아니면 관련도 순서대로 처음 통과한 후보가 답
+			if !opts.MMR {
+				break
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		used[best] = true
+		selected = append(selected, best)
+		for field, value := range candidates[best].Groups {
+			if opts.MaxPerGroup[field] <= 0 {
+				continue
+			}
+			if counts[field] == nil {
+				counts[field] = make(map[string]int)
+			}
+			counts[field][value]++
+		}
+		if opts.MMR {
+			for i, c := range candidates {
+				if !used[i] {
+					maxSim[i] = math.Max(maxSim[i], Similarity(c, candidates[best], opts.Fields))
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// withinCaps 후보를 골라도 그룹 제한 안인지
+func withinCaps(c Candidate, caps map[string]int, counts map[string]map[string]int) bool {
+	for field, value := range c.Groups {
+		if max := caps[field]; max > 0 && counts[field][value] >= max {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize 관련도 min-max 정규화 (모두 같으면 1)
+func normalize(candidates []Candidate) []float64 {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, c := range candidates {
+		min = math.Min(min, c.Relevance)
+		max = math.Max(max, c.Relevance)
+	}
+	normalized := make([]float64, len(candidates))
+	for i, c := range candidates {
+		if max > min {
+			normalized[i] = (c.Relevance - min) / (max - min)
+		} else {
+			normalized[i] = 1
+		}
+	}
+	return normalized
+}
+
+// Similarity 두 후보의 fields 벡터 코사인 유사도 평균 (fields가 비면 공통 필드 전부, 공통 필드가 없으면 0)
+func Similarity(a, b Candidate, fields []string) float64 {
+	var sum float64
+	n := 0
+	add := func(field string) {
+		va, okA := a.Vectors[field]
+		vb, okB := b.Vectors[field]
+		if okA && okB {
+			sum += Cosine(va, vb)
+			n++
+		}
+	}
+	if len(fields) > 0 {
+		for _, field := range fields {
+			add(field)
+		}
+	} else {
+		// 부동소수 합 순서가 실행마다 같도록 필드 이름순
+		names := make([]string, 0, len(a.Vectors))
+		for field := range a.Vectors {
+			names = append(names, field)
+		}
+		sort.Strings(names)
+		for _, field := range names {
+			add(field)
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// Cosine 코사인 유사도 (길이가 다르거나 영벡터면 0)
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,108 @@
+package diversity
+
+import (
+	"testing"
+)
+
+// testCandidates 관련도 내림차순 후보
+//
+//	0: 관련도 1.0, [1 0], 증류소 A, single
+//	1: 관련도 0.9, [1 0] (0과 같은 벡터), 증류소 A, single
+//	2: 관련도 0.8, [0.9 0.1] (0과 거의 같음), 증류소 B, single
+//	3: 관련도 0.2, [0 1] (0과 직교), 증류소 C, blend
+//	4: 관련도 0.0, [-1 0] (0과 반대), 증류소 없음, blend
+func testCandidates() []Candidate {
+	return []Candidate{
+		{ID: 10, Relevance: 1.0, Vectors: map[string][]float32{"flavor": {1, 0}}, Groups: map[string]string{"distillery_id": "A", "categoryGroup": "single"}},
+		{ID: 11, Relevance: 0.9, Vectors: map[string][]float32{"flavor": {1, 0}}, Groups: map[string]string{"distillery_id": "A", "categoryGroup": "single"}},
+		{ID: 12, Relevance: 0.8, Vectors: map[string][]float32{"flavor": {0.9, 0.1}}, Groups: map[string]string{"distillery_id": "B", "categoryGroup": "single"}},
+		{ID: 13, Relevance: 0.2, Vectors: map[string][]float32{"flavor": {0, 1}}, Groups: map[string]string{"distillery_id": "C", "categoryGroup": "blend"}},
+		{ID: 14, Relevance: 0.0, Vectors: map[string][]float32{"flavor": {-1, 0}}, Groups: map[string]string{"categoryGroup": "blend"}},
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSelectLambdaExtremes(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []int
+	}{
+		// 관련도만: 입력 순서 그대로
+		{"lambda 1", Options{MMR: true, Lambda: 1}, []int{0, 1, 2, 3, 4}},
+		// 다양성만: 첫 문서는 관련도, 이후는 고른 문서와 최대 유사도가 가장 낮은 후보
+		// 0 다음 반대 벡터 4, 직교 3, 거의 같은 2, 같은 1
+		{"lambda 0", Options{MMR: true, Lambda: 0}, []int{0, 4, 3, 2, 1}},
+		{"lambda 0, limit", Options{MMR: true, Lambda: 0, Limit: 2}, []int{0, 4}},
+		// MMR을 끄면 lambda와 상관없이 관련도 순서
+		{"MMR 끔", Options{Lambda: 0}, []int{0, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Select(testCandidates(), tt.opts); !equalInts(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 관련도가 모두 같으면 lambda 1에서 동점, 앞쪽 후보부터
+	flat := testCandidates()
+	for i := range flat {
+		flat[i].Relevance = 0.5
+	}
+	if got := Select(flat, Options{MMR: true, Lambda: 1}); !equalInts(got, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("동점 순서 %v", got)
+	}
+}
+
+func TestSelectGroupCaps(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []int
+	}{
+		{"제한 없음", Options{MaxPerGroup: map[string]int{"distillery_id": 0}}, []int{0, 1, 2, 3, 4}},
+		// 증류소 A 하나만, 증류소 필드가 없는 4는 제한하지 않는다
+		{"증류소당 1", Options{MaxPerGroup: map[string]int{"distillery_id": 1}}, []int{0, 2, 3, 4}},
+		// 두 필드 모두 지킨다: 2는 single 3개째, 4는 blend 2개째라 통과
+		{"증류소당 2, 분류당 2", Options{MaxPerGroup: map[string]int{"distillery_id": 2, "categoryGroup": 2}}, []int{0, 1, 3, 4}},
+		{"분류당 1", Options{MaxPerGroup: map[string]int{"categoryGroup": 1}}, []int{0, 3}},
+		// 제한을 넘는 후보를 건너뛰어 Limit보다 적을 수 있다
+		{"분류당 1, limit 3", Options{MaxPerGroup: map[string]int{"categoryGroup": 1}, Limit: 3}, []int{0, 3}},
+		// MMR과 함께: lambda 0이면 4, 3 순서지만 blend는 하나만
+		{"MMR lambda 0, 분류당 1", Options{MMR: true, Lambda: 0, MaxPerGroup: map[string]int{"categoryGroup": 1}}, []int{0, 4}},
+		// lambda 1이면 관련도 순서에서 제한만 적용
+		{"MMR lambda 1, 증류소당 1", Options{MMR: true, Lambda: 1, MaxPerGroup: map[string]int{"distillery_id": 1}}, []int{0, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Select(testCandidates(), tt.opts)
+			if !equalInts(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			counts := make(map[string]map[string]int)
+			for _, i := range got {
+				for field, value := range testCandidates()[i].Groups {
+					if counts[field] == nil {
+						counts[field] = make(map[string]int)
+					}
+					counts[field][value]++
+					if max := tt.opts.MaxPerGroup[field]; max > 0 && counts[field][value] > max {
+						t.Fatalf("%s=%s %d개, 제한 %d", field, value, counts[field][value], max)
+					}
+				}
+			}
+		})
+	}
+}
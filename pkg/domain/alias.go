@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// CollectionAlias 블루/그린 alias의 활성 버전 포인터
+// Previous는 직전 활성 버전이며, 롤백하면 Active와 맞바꾼다
+type CollectionAlias struct {
+	Alias     string    `gorm:"primaryKey;size:64" json:"alias"`
+	Active    string    `gorm:"size:64" json:"active"`
+	Previous  string    `gorm:"size:64" json:"previous"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (CollectionAlias) TableName() string {
+	return "embedding_collection_aliases"
+}
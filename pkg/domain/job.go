@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// IndexJob 상태
+const (
+	JobRunning     = "running"
+	JobCompleted   = "completed"
+	JobFailed      = "failed"
+	JobInterrupted = "interrupted"
+)
+
+// IndexJob 인덱싱 실행 기록 (재개용 체크포인트)
+// LastCommittedID 이하는 모두 처리가 끝난 상태이며, 재개하면 그 다음 ID부터 읽는다
+type IndexJob struct {
+	ID         string `gorm:"primaryKey;size:64" json:"id"`
+	Collection string `gorm:"size:64" json:"collection"`
+	Status     string `gorm:"size:16;index" json:"status"`
+
+	// 실행 범위 (index 명령 플래그)
+	FromID int64 `json:"from_id"`
+	ToID   int64 `json:"to_id"`
+	Limit  int   `json:"limit"`
+
+	LastCommittedID int64 `json:"last_committed_id"`
+	Processed       int   `json:"processed"`
+	Indexed         int   `json:"indexed"`
+	Failed          int   `json:"failed"`
+	Skipped         int   `json:"skipped"`
+	// Errors 최근 실패 내역 (JSON)
+	Errors    string `gorm:"type:text" json:"errors"`
+	LastError string `gorm:"type:text" json:"last_error"`
+
+	ModelRevision   string `gorm:"size:128" json:"model_revision"`
+	StrategyVersion string `gorm:"size:128" json:"strategy_version"`
+
+	StartedAt  time.Time  `json:"started_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+func (IndexJob) TableName() string {
+	return "embedding_index_jobs"
+}
+
+// SyncState 컬렉션별 증분 동기화 기준 시각 (이 시각까지 바뀐 행은 반영됨)
+type SyncState struct {
+	Collection string    `gorm:"primaryKey;size:64" json:"collection"`
+	Watermark  time.Time `json:"watermark"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (SyncState) TableName() string {
+	return "embedding_sync_states"
+}
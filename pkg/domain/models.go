@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Region struct {
 	ID          int64   `gorm:"primaryKey" json:"id"`
 	KorName     string  `json:"kor_name"`
@@ -40,6 +42,8 @@ type Alcohol struct {
 	Description   *string      `json:"description"`
 	RegionID      *int64       `json:"region_id"`
 	DistilleryID  *int64       `json:"distillery_id"`
+	CreateAt      *time.Time   `json:"create_at"`
+	LastModifyAt  *time.Time   `json:"last_modify_at"`
 	Region        *Region      `gorm:"foreignKey:RegionID" json:"region"`
 	Distillery    *Distillery  `gorm:"foreignKey:DistilleryID" json:"distillery"`
 	TastingTags   []TastingTag `gorm:"many2many:alcohol_tasting_tags" json:"tasting_tags"`
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// OutboxEvent 대상 엔티티
+const (
+	OutboxAlcohol    = "alcohol"
+	OutboxRegion     = "region"
+	OutboxDistillery = "distillery"
+	OutboxTastingTag = "tasting_tag"
+)
+
+// OutboxEvent 작업
+const (
+	OutboxUpsert = "upsert"
+	OutboxDelete = "delete"
+)
+
+// OutboxEvent 카탈로그 수정과 같은 트랜잭션에서 메인 앱이 쓰는 변경 이벤트 (embedding_outbox, 메인 앱 소유)
+// 워커는 이 테이블의 스키마를 바꾸지 않는다, 재시도/격리 정보는 OutboxState에서 채운다
+type OutboxEvent struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	EntityType string    `gorm:"size:32;not null" json:"entity_type"`
+	EntityID   int64     `gorm:"not null" json:"entity_id"`
+	Op         string    `gorm:"size:16;not null" json:"op"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Attempts 가져간 횟수 (lease마다 1 증가), AvailableAt 이전에는 다시 가져가지 않는다
+	Attempts    int        `gorm:"-:all" json:"attempts"`
+	AvailableAt *time.Time `gorm:"-:all" json:"available_at"`
+	// ParkedAt 재시도 한도를 넘겨 격리된 시각 (격리된 이벤트는 수동 처리)
+	ParkedAt  *time.Time `gorm:"-:all" json:"parked_at"`
+	LastError string     `gorm:"-:all" json:"last_error"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "embedding_outbox"
+}
+
+// OutboxState 워커 소유의 이벤트 처리 상태 (embedding_outbox_state, 한 번이라도 가져간 이벤트만 행이 있다)
+// AvailableAt은 처리 중에는 lease 만료 시각, 실패 후에는 재시도 시각
+type OutboxState struct {
+	EventID     int64      `gorm:"primaryKey;autoIncrement:false"`
+	Attempts    int        `gorm:"not null;default:0"`
+	AvailableAt *time.Time `gorm:"index"`
+	ParkedAt    *time.Time `gorm:"index"`
+	LastError   string     `gorm:"type:text"`
+}
+
+func (OutboxState) TableName() string {
+	return "embedding_outbox_state"
+}
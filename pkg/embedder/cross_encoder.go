@@ -0,0 +1,133 @@
+package embedder
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// Scorer (질의, 문서) 쌍의 관련도 점수
+type Scorer interface {
+	// Score passages 순서대로 0~1 점수
+	Score(ctx context.Context, query string, passages []string) ([]float32, error)
+}
+
+// ONNXCrossEncoder ONNX cross-encoder (input_ids, attention_mask → logits) 재순위 모델
+// bge-reranker 같은 XLM-RoBERTa 계열 시퀀스 분류 모델 (logits shape [batch, 1])
+type ONNXCrossEncoder struct {
+	tokenizer *Tokenizer
+	session   *ort.DynamicAdvancedSession
+	maxLength int
+}
+
+// NewONNXCrossEncoder 토크나이저 로드 + ONNX 세션 생성 (임베더와 같은 ONNX Runtime 환경 공유)
+func NewONNXCrossEncoder(cfg Config) (*ONNXCrossEncoder, error) {
+	tokenizer, err := LoadTokenizer(cfg.TokenizerPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := initEnvironment(cfg.LibraryPath); err != nil {
+		return nil, err
+	}
+
+	options, err := ort.NewSessionOptions()
+	if err != nil {
+		return nil, fmt.Errorf("세션 옵션 생성 실패: %w", err)
+	}
+	defer options.Destroy()
+	if cfg.Threads > 0 {
+		if err := options.SetIntraOpNumThreads(cfg.Threads); err != nil {
+			return nil, fmt.Errorf("스레드 수 설정 실패: %w", err)
+		}
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(
+		cfg.ModelPath,
+		[]string{"input_ids", "attention_mask"},
+		[]string{"logits"},
+		options,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("재순위 모델 세션 생성 실패: %w", err)
+	}
+
+	maxLength := cfg.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLength
+	}
+	return &ONNXCrossEncoder{tokenizer: tokenizer, session: session, maxLength: maxLength}, nil
+}
+
+// Close 세션 해제
+func (e *ONNXCrossEncoder) Close() error {
+	return e.session.Destroy()
+}
+
+// Score 한 배치로 (query, passage) 쌍을 추론해 logit에 sigmoid를 씌운 점수 반환
+// 배치 크기 조절은 호출하는 쪽(service.Reranker)에서 한다
+func (e *ONNXCrossEncoder) Score(ctx context.Context, query string, passages []string) ([]float32, error) {
+	if len(passages) == 0 {
+		return []float32{}, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	encoded := make([][]int64, len(passages))
+	seqLen := 0
+	for i, passage := range passages {
+		encoded[i] = e.tokenizer.EncodePair(query, passage, e.maxLength)
+		if len(encoded[i]) > seqLen {
+			seqLen = len(encoded[i])
+		}
+	}
+
+	batch := len(passages)
+	inputIDs := make([]int64, batch*seqLen)
+	attentionMask := make([]int64, batch*seqLen)
+	for i, ids := range encoded {
+		for j := 0; j < seqLen; j++ {
+			if j < len(ids) {
+				inputIDs[i*seqLen+j] = ids[j]
+				attentionMask[i*seqLen+j] = 1
+			} else {
+				inputIDs[i*seqLen+j] = e.tokenizer.PadID()
+			}
+		}
+	}
+
+	shape := ort.NewShape(int64(batch), int64(seqLen))
+	inputIDsTensor, err := ort.NewTensor(shape, inputIDs)
+	if err != nil {
+		return nil, fmt.Errorf("input_ids 텐서 생성 실패: %w", err)
+	}
+	defer inputIDsTensor.Destroy()
+	attentionMaskTensor, err := ort.NewTensor(shape, attentionMask)
+	if err != nil {
+		return nil, fmt.Errorf("attention_mask 텐서 생성 실패: %w", err)
+	}
+	defer attentionMaskTensor.Destroy()
+
+	outputs := []ort.Value{nil}
+	if err := e.session.Run([]ort.Value{inputIDsTensor, attentionMaskTensor}, outputs); err != nil {
+		return nil, fmt.Errorf("재순위 추론 실패: %w", err)
+	}
+	defer outputs[0].Destroy()
+
+	logits, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, fmt.Errorf("logits 타입 오류: %T", outputs[0])
+	}
+	data := logits.GetData()
+	if len(data) != batch {
+		return nil, fmt.Errorf("logits shape 오류: %v (라벨 1개 모델만 지원)", logits.GetShape())
+	}
+
+	scores := make([]float32, batch)
+	for i, logit := range data {
+		scores[i] = float32(1 / (1 + math.Exp(-float64(logit))))
+	}
+	return scores, nil
+}
@@ -0,0 +1,240 @@
+// Package embedder ONNX Runtime 기반 텍스트 임베딩 (dense + sparse)
+package embedder
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// Embedding 텍스트 하나의 임베딩 결과
+type Embedding struct {
+	// Dense CLS 풀링 + L2 정규화 벡터
+	Dense []float32
+	// Sparse 토큰 ID(문자열) → 가중치 (sparse 헤드가 있으면 학습된 가중치, 없으면 토큰 빈도)
+	Sparse map[string]float32
+}
+
+// Embedder 텍스트 배치 임베딩
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([]Embedding, error)
+}
+
+// Config ONNX 임베더 설정
+type Config struct {
+	ModelPath     string
+	TokenizerPath string
+	// LibraryPath ONNX Runtime 공유 라이브러리 경로
+	LibraryPath string
+	// MaxLength 토큰 최대 길이 (<s>, </s> 포함)
+	MaxLength int
+	// Threads 연산 스레드 수 (0이면 ONNX Runtime 기본값)
+	Threads int
+	// SparseHeadPath BGE-M3 sparse_linear.pt 경로 (비우면 토큰 빈도 sparse)
+	SparseHeadPath string
+}
+
+// DefaultMaxLength 기본 토큰 최대 길이
+const DefaultMaxLength = 512
+
+// ONNXEmbedder ONNX 모델 (input_ids, attention_mask → last_hidden_state) 임베더
+type ONNXEmbedder struct {
+	tokenizer  *Tokenizer
+	session    *ort.DynamicAdvancedSession
+	maxLength  int
+	sparseHead *SparseHead
+}
+
+var (
+	environmentOnce sync.Once
+	environmentErr  error
+)
+
+// initEnvironment ONNX Runtime 환경은 프로세스당 한 번만 초기화한다
+func initEnvironment(libraryPath string) error {
+	environmentOnce.Do(func() {
+		if ort.IsInitialized() {
+			return
+		}
+		ort.SetSharedLibraryPath(libraryPath)
+		if err := ort.InitializeEnvironment(); err != nil {
+			environmentErr = fmt.Errorf("ONNX Runtime 초기화 실패: %w", err)
+		}
+	})
+	return environmentErr
+}
+
+// NewONNXEmbedder 토크나이저 로드 + ONNX 세션 생성
+func NewONNXEmbedder(cfg Config) (*ONNXEmbedder, error) {
+	tokenizer, err := LoadTokenizer(cfg.TokenizerPath)
+	if err != nil {
+		return nil, err
+	}
+	var sparseHead *SparseHead
+	if cfg.SparseHeadPath != "" {
+		if sparseHead, err = LoadSparseHead(cfg.SparseHeadPath); err != nil {
+			return nil, err
+		}
+	}
+	if err := initEnvironment(cfg.LibraryPath); err != nil {
+		return nil, err
+	}
+
+	options, err := ort.NewSessionOptions()
+	if err != nil {
+		return nil, fmt.Errorf("세션 옵션 생성 실패: %w", err)
+	}
+	defer options.Destroy()
+	if cfg.Threads > 0 {
+		if err := options.SetIntraOpNumThreads(cfg.Threads); err != nil {
+			return nil, fmt.Errorf("스레드 수 설정 실패: %w", err)
+		}
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(
+		cfg.ModelPath,
+		[]string{"input_ids", "attention_mask"},
+		[]string{"last_hidden_state"},
+		options,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("세션 생성 실패: %w", err)
+	}
+
+	maxLength := cfg.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLength
+	}
+	return &ONNXEmbedder{tokenizer: tokenizer, session: session, maxLength: maxLength, sparseHead: sparseHead}, nil
+}
+
+// Close 세션 해제
+func (e *ONNXEmbedder) Close() error {
+	return e.session.Destroy()
+}
+
+// Embed 텍스트 배치 임베딩 (배치 내 최대 길이로 패딩)
+func (e *ONNXEmbedder) Embed(ctx context.Context, texts []string) ([]Embedding, error) {
+	if len(texts) == 0 {
+		return []Embedding{}, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	encoded := make([][]int64, len(texts))
+	seqLen := 0
+	for i, text := range texts {
+		encoded[i] = e.tokenizer.Encode(text, e.maxLength)
+		if len(encoded[i]) > seqLen {
+			seqLen = len(encoded[i])
+		}
+	}
+
+	batch := len(texts)
+	inputIDs := make([]int64, batch*seqLen)
+	attentionMask := make([]int64, batch*seqLen)
+	for i, ids := range encoded {
+		for j := 0; j < seqLen; j++ {
+			if j < len(ids) {
+				inputIDs[i*seqLen+j] = ids[j]
+				attentionMask[i*seqLen+j] = 1
+			} else {
+				inputIDs[i*seqLen+j] = e.tokenizer.PadID()
+			}
+		}
+	}
+
+	shape := ort.NewShape(int64(batch), int64(seqLen))
+	inputIDsTensor, err := ort.NewTensor(shape, inputIDs)
+	if err != nil {
+		return nil, fmt.Errorf("input_ids 텐서 생성 실패: %w", err)
+	}
+	defer inputIDsTensor.Destroy()
+	attentionMaskTensor, err := ort.NewTensor(shape, attentionMask)
+	if err != nil {
+		return nil, fmt.Errorf("attention_mask 텐서 생성 실패: %w", err)
+	}
+	defer attentionMaskTensor.Destroy()
+
+	// 출력은 ONNX Runtime이 할당한다
+	outputs := []ort.Value{nil}
+	if err := e.session.Run([]ort.Value{inputIDsTensor, attentionMaskTensor}, outputs); err != nil {
+		return nil, fmt.Errorf("추론 실패: %w", err)
+	}
+	defer outputs[0].Destroy()
+
+	hidden, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, fmt.Errorf("last_hidden_state 타입 오류: %T", outputs[0])
+	}
+	outShape := hidden.GetShape()
+	if len(outShape) != 3 {
+		return nil, fmt.Errorf("last_hidden_state shape 오류: %v", outShape)
+	}
+	dim := int(outShape[2])
+	data := hidden.GetData()
+	if e.sparseHead != nil && e.sparseHead.Dimension() != dim {
+		return nil, fmt.Errorf("sparse 헤드 차원 불일치: %d != %d", e.sparseHead.Dimension(), dim)
+	}
+
+	embeddings := make([]Embedding, batch)
+	for i := range texts {
+		// CLS 풀링: 각 시퀀스의 첫 토큰
+		offset := i * seqLen * dim
+		dense := make([]float32, dim)
+		copy(dense, data[offset:offset+dim])
+		var sparse map[string]float32
+		if e.sparseHead != nil {
+			sparse = e.sparseHead.Weights(data[offset:offset+len(encoded[i])*dim], encoded[i], e.tokenizer.IsSpecial)
+		} else {
+			sparse = e.sparseWeights(encoded[i])
+		}
+		embeddings[i] = Embedding{
+			Dense:  normalizeL2(dense),
+			Sparse: sparse,
+		}
+	}
+	return embeddings, nil
+}
+
+// sparseWeights 특수 토큰을 제외한 토큰 빈도 / 전체 토큰 수
+// (sparse 헤드가 없는 ONNX 모델용 어휘 가중치, BGE-M3 학습 가중치와 척도가 달라
+// Python이 채운 컬렉션에 섞어 쓰면 하이브리드 융합이 치우친다: 이 경우 재색인 필요)
+func (e *ONNXEmbedder) sparseWeights(ids []int64) map[string]float32 {
+	counts := make(map[int64]int)
+	total := 0
+	for _, id := range ids {
+		if e.tokenizer.IsSpecial(id) {
+			continue
+		}
+		counts[id]++
+		total++
+	}
+
+	sparse := make(map[string]float32, len(counts))
+	for id, count := range counts {
+		sparse[strconv.FormatInt(id, 10)] = float32(count) / float32(total)
+	}
+	return sparse
+}
+
+// normalizeL2 벡터 길이를 1로 정규화 (영벡터는 그대로)
+func normalizeL2(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
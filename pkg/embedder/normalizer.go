@@ -0,0 +1,262 @@
+package embedder
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizer tokenizer.json normalizer 단계 (HuggingFace tokenizers와 같은 순서로 적용)
+type normalizer func(string) string
+
+// normalizerConfig tokenizer.json normalizer 항목
+type normalizerConfig struct {
+	Type                string             `json:"type"`
+	Normalizers         []normalizerConfig `json:"normalizers"`
+	PrecompiledCharsmap string             `json:"precompiled_charsmap"`
+	Pattern             struct {
+		String *string `json:"String"`
+		Regex  *string `json:"Regex"`
+	} `json:"pattern"`
+	Content string `json:"content"`
+}
+
+// newNormalizer 설정 → 정규화 함수 (nil 설정은 그대로 통과)
+// XLM-RoBERTa 계열은 Sequence[Precompiled(nmt_nfkc 문자 맵), Replace(" {2,}" → " ")]
+func newNormalizer(cfg *normalizerConfig) (normalizer, error) {
+	if cfg == nil {
+		return func(s string) string { return s }, nil
+	}
+
+	switch cfg.Type {
+	case "Sequence":
+		steps := make([]normalizer, 0, len(cfg.Normalizers))
+		for i := range cfg.Normalizers {
+			step, err := newNormalizer(&cfg.Normalizers[i])
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		return func(s string) string {
+			for _, step := range steps {
+				s = step(s)
+			}
+			return s
+		}, nil
+	case "Precompiled":
+		charsmap, err := parseCharsmap(cfg.PrecompiledCharsmap)
+		if err != nil {
+			return nil, err
+		}
+		return charsmap.normalize, nil
+	case "Replace":
+		switch {
+		case cfg.Pattern.Regex != nil:
+			re, err := regexp.Compile(*cfg.Pattern.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("Replace 정규식 파싱 실패: %w", err)
+			}
+			return func(s string) string { return re.ReplaceAllLiteralString(s, cfg.Content) }, nil
+		case cfg.Pattern.String != nil:
+			pattern := *cfg.Pattern.String
+			return func(s string) string { return strings.ReplaceAll(s, pattern, cfg.Content) }, nil
+		default:
+			return nil, fmt.Errorf("Replace 패턴 없음")
+		}
+	case "NFKC":
+		return norm.NFKC.String, nil
+	case "NFC":
+		return norm.NFC.String, nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 normalizer: %q", cfg.Type)
+	}
+}
+
+// charsmap SentencePiece precompiled_charsmap (darts-clone 이중 배열 trie + 정규화 문자열)
+type charsmap struct {
+	trie       []uint32
+	normalized []byte
+}
+
+// parseCharsmap base64 → [trie 바이트 수(uint32 LE)][trie][NUL로 끝나는 정규화 문자열들]
+func parseCharsmap(encoded string) (*charsmap, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("precompiled_charsmap 디코딩 실패: %w", err)
+	}
+	if len(raw) < 4 {
+		return nil, fmt.Errorf("precompiled_charsmap 길이 오류: %d", len(raw))
+	}
+	size := int(binary.LittleEndian.Uint32(raw))
+	if size%4 != 0 || 4+size > len(raw) {
+		return nil, fmt.Errorf("precompiled_charsmap trie 크기 오류: %d", size)
+	}
+
+	trie := make([]uint32, size/4)
+	for i := range trie {
+		trie[i] = binary.LittleEndian.Uint32(raw[4+i*4:])
+	}
+	return &charsmap{trie: trie, normalized: raw[4+size:]}, nil
+}
+
+// normalize 그래핌 단위로 치환 (6바이트 미만 그래핌은 통째로, 아니면 글자마다 조회)
+func (c *charsmap) normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	graphemes := uniseg.NewGraphemes(s)
+	for graphemes.Next() {
+		grapheme := graphemes.Str()
+		if len(grapheme) < 6 {
+			if replaced, ok := c.transform(grapheme); ok {
+				b.WriteString(replaced)
+				continue
+			}
+		}
+		for _, r := range grapheme {
+			part := string(r)
+			if replaced, ok := c.transform(part); ok {
+				b.WriteString(replaced)
+			} else {
+				b.WriteString(part)
+			}
+		}
+	}
+	return b.String()
+}
+
+// transform chunk의 접두사 중 가장 짧은 trie 일치 항목의 정규화 문자열
+func (c *charsmap) transform(chunk string) (string, bool) {
+	index, ok := c.prefixMatch(chunk)
+	if !ok || index >= len(c.normalized) {
+		return "", false
+	}
+	end := index
+	for end < len(c.normalized) && c.normalized[end] != 0 {
+		end++
+	}
+	return string(c.normalized[index:end]), true
+}
+
+// prefixMatch darts-clone common prefix search의 첫 결과
+func (c *charsmap) prefixMatch(key string) (int, bool) {
+	pos := 0
+	if len(c.trie) == 0 {
+		return 0, false
+	}
+	pos ^= unitOffset(c.trie[0])
+	for i := 0; i < len(key); i++ {
+		label := key[i]
+		if label == 0 {
+			break
+		}
+		pos ^= int(label)
+		if pos >= len(c.trie) {
+			return 0, false
+		}
+		unit := c.trie[pos]
+		if unitLabel(unit) != uint32(label) {
+			return 0, false
+		}
+		pos ^= unitOffset(unit)
+		if unitHasLeaf(unit) {
+			if pos >= len(c.trie) {
+				return 0, false
+			}
+			return int(c.trie[pos] & (1<<31 - 1)), true
+		}
+	}
+	return 0, false
+}
+
+func unitOffset(unit uint32) int {
+	return int((unit >> 10) << ((unit & (1 << 9)) >> 6))
+}
+
+func unitLabel(unit uint32) uint32 {
+	return unit & (1<<31 | 0xFF)
+}
+
+func unitHasLeaf(unit uint32) bool {
+	return (unit>>8)&1 == 1
+}
+
+// preTokenizerConfig tokenizer.json pre_tokenizer 항목 (Metaspace만 지원)
+type preTokenizerConfig struct {
+	Type           string               `json:"type"`
+	PreTokenizers  []preTokenizerConfig `json:"pretokenizers"`
+	Replacement    string               `json:"replacement"`
+	AddPrefixSpace *bool                `json:"add_prefix_space"`
+	PrependScheme  string               `json:"prepend_scheme"`
+	Split          *bool                `json:"split"`
+}
+
+// metaspaceSplitter Metaspace 사전 토큰화: 공백을 ▁로 바꾸고 앞에 ▁를 붙인 뒤 ▁ 앞에서 나눈다
+type metaspaceSplitter struct {
+	replacement string
+	// prepend "always" (add_prefix_space: true), "first", "never"
+	prepend string
+	split   bool
+}
+
+// newMetaspaceSplitter 설정 → Metaspace (설정이 없으면 XLM-RoBERTa 기본값)
+func newMetaspaceSplitter(cfg *preTokenizerConfig) (*metaspaceSplitter, error) {
+	splitter := &metaspaceSplitter{replacement: metaspace, prepend: "always", split: true}
+	if cfg == nil {
+		return splitter, nil
+	}
+	if cfg.Type == "Sequence" && len(cfg.PreTokenizers) == 1 {
+		cfg = &cfg.PreTokenizers[0]
+	}
+	if cfg.Type != "Metaspace" {
+		return nil, fmt.Errorf("지원하지 않는 pre_tokenizer: %q (Metaspace만 지원)", cfg.Type)
+	}
+
+	if cfg.Replacement != "" {
+		splitter.replacement = cfg.Replacement
+	}
+	switch {
+	case cfg.PrependScheme != "":
+		splitter.prepend = cfg.PrependScheme
+	case cfg.AddPrefixSpace != nil && !*cfg.AddPrefixSpace:
+		splitter.prepend = "never"
+	}
+	if cfg.Split != nil {
+		splitter.split = *cfg.Split
+	}
+	return splitter, nil
+}
+
+// words 정규화된 텍스트 → Unigram에 넘길 조각 (빈 텍스트는 조각 없음)
+func (m *metaspaceSplitter) words(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, " ", m.replacement)
+	if m.prepend != "never" && !strings.HasPrefix(text, m.replacement) {
+		text = m.replacement + text
+	}
+	if !m.split {
+		return []string{text}
+	}
+
+	// MergedWithNext: 구분자는 뒤 조각의 앞에 붙는다
+	var words []string
+	start := 0
+	for i := 1; i <= len(text)-len(m.replacement); {
+		if strings.HasPrefix(text[i:], m.replacement) {
+			words = append(words, text[start:i])
+			start = i
+			i += len(m.replacement)
+			continue
+		}
+		i++
+	}
+	return append(words, text[start:])
+}
@@ -0,0 +1,102 @@
+package embedder
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+)
+
+// SparseHead BGE-M3 sparse 헤드 (sparse_linear: hidden → 1, 토큰 가중치 = relu(W·h + b))
+// Python BGEM3FlagModel이 Qdrant keywords에 넣은 학습된 가중치와 같은 척도를 만든다
+type SparseHead struct {
+	weight []float32
+	bias   float32
+}
+
+// LoadSparseHead torch.save로 저장된 sparse_linear.pt (zip 형식) 로드
+// 저장된 텐서는 weight [1, hidden], bias [1] 두 개뿐이므로 pickle을 해석하지 않고
+// data/ 아래 원시 storage를 크기로 구분한다 (float32 little-endian만 지원)
+func LoadSparseHead(filePath string) (*SparseHead, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("sparse 헤드 파일 열기 실패: %w", err)
+	}
+	defer archive.Close()
+
+	var weight, bias []float32
+	for _, f := range archive.File {
+		if path.Base(path.Dir(f.Name)) != "data" {
+			continue
+		}
+		values, err := readFloat32s(f)
+		if err != nil {
+			return nil, fmt.Errorf("sparse 헤드 %s 읽기 실패: %w", f.Name, err)
+		}
+		switch {
+		case len(values) == 1 && bias == nil:
+			bias = values
+		case len(values) > 1 && weight == nil:
+			weight = values
+		default:
+			return nil, fmt.Errorf("sparse 헤드 텐서 구성 오류: %s (%d개)", f.Name, len(values))
+		}
+	}
+	if weight == nil || bias == nil {
+		return nil, fmt.Errorf("sparse 헤드에 weight/bias 텐서가 없음: %s", filePath)
+	}
+	return &SparseHead{weight: weight, bias: bias[0]}, nil
+}
+
+func readFloat32s(f *zip.File) ([]float32, error) {
+	if f.UncompressedSize64%4 != 0 {
+		return nil, fmt.Errorf("float32 storage가 아님 (%d bytes)", f.UncompressedSize64)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float32, len(raw)/4)
+	for i := range values {
+		values[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+	}
+	return values, nil
+}
+
+// Dimension 입력 hidden 차원
+func (h *SparseHead) Dimension() int {
+	return len(h.weight)
+}
+
+// Weights 시퀀스 토큰별 relu(W·h + b)를 토큰 ID마다 최댓값으로 모은다 (0 이하는 제외)
+// hidden은 [seqLen, dim] 행 우선 배열, skip이 true인 토큰(특수 토큰)은 제외
+func (h *SparseHead) Weights(hidden []float32, ids []int64, skip func(int64) bool) map[string]float32 {
+	dim := len(h.weight)
+	sparse := make(map[string]float32)
+	for i, id := range ids {
+		if skip(id) {
+			continue
+		}
+		row := hidden[i*dim : (i+1)*dim]
+		score := h.bias
+		for k, w := range h.weight {
+			score += w * row[k]
+		}
+		if score <= 0 {
+			continue
+		}
+		key := strconv.FormatInt(id, 10)
+		if score > sparse[key] {
+			sparse[key] = score
+		}
+	}
+	return sparse
+}
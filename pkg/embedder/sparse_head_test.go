@@ -0,0 +1,89 @@
+package embedder
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSparseHead torch.save zip 구조 (archive/data.pkl, archive/data/0, archive/data/1)를 흉내 낸 파일
+func writeSparseHead(t *testing.T, weight []float32, bias float32) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sparse_linear.pt")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	entries := []struct {
+		name   string
+		values []float32
+	}{
+		{"archive/data/0", weight},
+		{"archive/data/1", []float32{bias}},
+	}
+	pkl, _ := w.Create("archive/data.pkl")
+	pkl.Write([]byte("pickle"))
+	for _, e := range entries {
+		out, err := w.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := make([]byte, 4*len(e.values))
+		for i, v := range e.values {
+			binary.LittleEndian.PutUint32(raw[i*4:], math.Float32bits(v))
+		}
+		out.Write(raw)
+	}
+	version, _ := w.Create("archive/version")
+	version.Write([]byte("3\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return path
+}
+
+func TestSparseHeadWeights(t *testing.T) {
+	head, err := LoadSparseHead(writeSparseHead(t, []float32{1, -1}, 0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Dimension() != 2 {
+		t.Fatalf("dimension = %d", head.Dimension())
+	}
+
+	// <s>(0) 10 11 10 </s>(2): 10은 두 번 나와 큰 값, 11은 relu로 빠진다
+	ids := []int64{0, 10, 11, 10, 2}
+	hidden := []float32{
+		9, 0, // <s>
+		1, 0, // 10: 1.5
+		0, 2, // 11: -1.5 → 제외
+		2, 0, // 10: 2.5
+		9, 0, // </s>
+	}
+	special := func(id int64) bool { return id == 0 || id == 2 }
+	got := head.Weights(hidden, ids, special)
+	if len(got) != 1 || got["10"] != 2.5 {
+		t.Fatalf("weights = %v", got)
+	}
+}
+
+func TestLoadSparseHeadErrors(t *testing.T) {
+	if _, err := LoadSparseHead(filepath.Join(t.TempDir(), "missing.pt")); err == nil {
+		t.Fatal("없는 파일 오류 기대")
+	}
+
+	path := filepath.Join(t.TempDir(), "empty.pt")
+	f, _ := os.Create(path)
+	w := zip.NewWriter(f)
+	w.Create("archive/data.pkl")
+	w.Close()
+	f.Close()
+	if _, err := LoadSparseHead(path); err == nil {
+		t.Fatal("텐서 없는 파일 오류 기대")
+	}
+}
@@ -0,0 +1,78 @@
+"""transformers 토크나이저 출력으로 tokenizer_fixtures.json 생성 (Go Tokenizer 패리티 테스트용)
+
+    pip install transformers
+    python3 pkg/embedder/testdata/tokenizer_fixtures.py [BAAI/bge-m3]
+
+Go 테스트는 같은 모델의 tokenizer.json을 TOKENIZER_PATH(기본: ~/.cache/embedding-worker/tokenizer.json)에서 읽는다.
+"""
+import json
+import os
+import sys
+
+from transformers import AutoTokenizer
+
+SINGLE = [
+    # 한국어
+    "스모키한 위스키",
+    "달콤한 바닐라 향과 꿀, 서양배의 풍미",
+    "셰리 캐스크에서 12년 숙성된 싱글몰트",
+    # 영어
+    "Glenfiddich 12 Year Old Single Malt Scotch Whisky",
+    "peaty, smoky and medicinal",
+    "Ex-Bourbon & Sherry Cask (46.3% ABV)",
+    # 혼합
+    "글렌피딕 Glenfiddich 12년 700ml",
+    "라프로익 10년 Laphroaig 스모키 smoky",
+    "아일라 Islay 피트 위스키 43도",
+    # 공백, 정규화
+    "  앞뒤 공백  ",
+    "여러   칸  공백",
+    "탭\t과\n줄바꿈",
+    "ＡＢＣ　전각 문자",
+    "café naïve",
+    "",
+]
+
+# 문장 쌍 longest_first 절단 (cross-encoder 입력): 짧은 쪽 보존, 둘 다 절반, 같은 길이, 홀수 예산
+QUERY = "스모키한 위스키"
+PASSAGE = "아일라 섬에서 만든 피트 향이 강한 싱글몰트 위스키로 바닐라와 꿀의 단맛이 뒤따른다"
+PAIRS = [
+    (QUERY, PASSAGE, 512),
+    (QUERY, PASSAGE, 12),
+    (PASSAGE, QUERY, 12),
+    (PASSAGE, PASSAGE, 16),
+    (PASSAGE, PASSAGE, 17),
+    (PASSAGE, PASSAGE + " 긴 여운", 15),
+    (PASSAGE + " 긴 여운", PASSAGE, 15),
+    ("Glenfiddich 12 Year Old", "Single Malt Scotch Whisky from Speyside", 9),
+    ("a b c d e", "f g h i j", 11),
+]
+
+
+def main():
+    model = sys.argv[1] if len(sys.argv) > 1 else "BAAI/bge-m3"
+    tokenizer = AutoTokenizer.from_pretrained(model)
+
+    fixture = {
+        "model": model,
+        "transformers": __import__("transformers").__version__,
+        "single": [{"text": text, "ids": tokenizer(text)["input_ids"]} for text in SINGLE],
+        "pair": [
+            {
+                "first": first,
+                "second": second,
+                "max_length": max_length,
+                "ids": tokenizer(first, second, truncation="longest_first", max_length=max_length)["input_ids"],
+            }
+            for first, second, max_length in PAIRS
+        ],
+    }
+
+    path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "tokenizer_fixtures.json")
+    with open(path, "w", encoding="utf-8") as f:
+        json.dump(fixture, f, ensure_ascii=False, indent=2)
+        f.write("\n")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,254 @@
+package embedder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"unicode/utf8"
+)
+
+// metaspace SentencePiece 공백 치환 문자
+const metaspace = "▁"
+
+// unkPenalty 사전에 없는 문자 점수 (최소 점수 - 10, HuggingFace Unigram과 동일)
+const unkPenalty = 10.0
+
+// Tokenizer XLM-RoBERTa 계열(BGE-m3, KURE) Unigram 토크나이저
+// tokenizer.json의 normalizer(Precompiled 문자 맵, Replace, NFKC) + Metaspace 사전 토큰화
+// + Unigram 모델 + <s> $A </s> 후처리만 지원한다
+type Tokenizer struct {
+	normalize normalizer
+	metaspace *metaspaceSplitter
+
+	pieces   map[string]int
+	scores   []float64
+	unkID    int
+	unkScore float64
+	maxRunes int
+
+	bosID int
+	eosID int
+	padID int
+}
+
+// tokenizerFile tokenizer.json 중 필요한 부분
+type tokenizerFile struct {
+	AddedTokens []struct {
+		ID      int    `json:"id"`
+		Content string `json:"content"`
+	} `json:"added_tokens"`
+	Normalizer   *normalizerConfig   `json:"normalizer"`
+	PreTokenizer *preTokenizerConfig `json:"pre_tokenizer"`
+	Model        struct {
+		Type  string               `json:"type"`
+		UnkID *int                 `json:"unk_id"`
+		Vocab [][2]json.RawMessage `json:"vocab"`
+	} `json:"model"`
+}
+
+// LoadTokenizer tokenizer.json 로드
+func LoadTokenizer(path string) (*Tokenizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("토크나이저 파일 읽기 실패: %w", err)
+	}
+
+	var file tokenizerFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("토크나이저 파싱 실패: %w", err)
+	}
+	if file.Model.Type != "Unigram" {
+		return nil, fmt.Errorf("지원하지 않는 토크나이저 모델: %q (Unigram만 지원)", file.Model.Type)
+	}
+
+	normalize, err := newNormalizer(file.Normalizer)
+	if err != nil {
+		return nil, fmt.Errorf("토크나이저 normalizer 생성 실패: %w", err)
+	}
+	splitter, err := newMetaspaceSplitter(file.PreTokenizer)
+	if err != nil {
+		return nil, fmt.Errorf("토크나이저 pre_tokenizer 생성 실패: %w", err)
+	}
+
+	t := &Tokenizer{
+		normalize: normalize,
+		metaspace: splitter,
+		pieces:    make(map[string]int, len(file.Model.Vocab)),
+		scores:    make([]float64, len(file.Model.Vocab)),
+		unkID:     -1,
+		bosID:     -1,
+		eosID:     -1,
+		padID:     -1,
+	}
+
+	minScore := math.Inf(1)
+	for id, entry := range file.Model.Vocab {
+		var piece string
+		var score float64
+		if err := json.Unmarshal(entry[0], &piece); err != nil {
+			return nil, fmt.Errorf("토크나이저 vocab %d 파싱 실패: %w", id, err)
+		}
+		if err := json.Unmarshal(entry[1], &score); err != nil {
+			return nil, fmt.Errorf("토크나이저 vocab %d 점수 파싱 실패: %w", id, err)
+		}
+		t.pieces[piece] = id
+		t.scores[id] = score
+		if score < minScore {
+			minScore = score
+		}
+		if n := utf8.RuneCountInString(piece); n > t.maxRunes {
+			t.maxRunes = n
+		}
+	}
+	t.unkScore = minScore - unkPenalty
+
+	if file.Model.UnkID != nil {
+		t.unkID = *file.Model.UnkID
+	}
+	for _, token := range file.AddedTokens {
+		switch token.Content {
+		case "<s>":
+			t.bosID = token.ID
+		case "</s>":
+			t.eosID = token.ID
+		case "<pad>":
+			t.padID = token.ID
+		case "<unk>":
+			if t.unkID < 0 {
+				t.unkID = token.ID
+			}
+		}
+	}
+	if t.bosID < 0 || t.eosID < 0 || t.padID < 0 || t.unkID < 0 {
+		return nil, fmt.Errorf("토크나이저에 특수 토큰(<s>, </s>, <pad>, <unk>)이 없음")
+	}
+
+	return t, nil
+}
+
+// PadID 패딩 토큰 ID
+func (t *Tokenizer) PadID() int64 {
+	return int64(t.padID)
+}
+
+// IsSpecial <s>, </s>, <pad>, <unk> 여부 (sparse 가중치에서 제외, BGE-M3 unused_tokens와 동일)
+func (t *Tokenizer) IsSpecial(id int64) bool {
+	return id == int64(t.bosID) || id == int64(t.eosID) || id == int64(t.padID) || id == int64(t.unkID)
+}
+
+// Encode 텍스트 → <s> 토큰... </s>, maxLength를 넘으면 본문을 자른다
+func (t *Tokenizer) Encode(text string, maxLength int) []int64 {
+	ids := append([]int64{int64(t.bosID)}, t.encodeWords(text)...)
+	if maxLength > 1 && len(ids) > maxLength-1 {
+		ids = ids[:maxLength-1]
+	}
+	return append(ids, int64(t.eosID))
+}
+
+// EncodePair 문장 쌍 → <s> A </s></s> B </s> (XLM-RoBERTa 쌍 템플릿, cross-encoder 입력)
+// maxLength를 넘으면 longestFirst 길이로 뒤를 자른다 (HuggingFace longest_first)
+func (t *Tokenizer) EncodePair(first, second string, maxLength int) []int64 {
+	a := t.encodeWords(first)
+	b := t.encodeWords(second)
+	if maxLength > 0 {
+		// 특수 토큰 4개: <s>, </s>, </s>, </s>
+		n1, n2 := longestFirst(len(a), len(b), max(maxLength-4, 0))
+		a, b = a[:n1], b[:n2]
+	}
+
+	ids := make([]int64, 0, len(a)+len(b)+4)
+	ids = append(ids, int64(t.bosID))
+	ids = append(ids, a...)
+	ids = append(ids, int64(t.eosID), int64(t.eosID))
+	ids = append(ids, b...)
+	return append(ids, int64(t.eosID))
+}
+
+// longestFirst HuggingFace tokenizers LongestFirst 절단 후 길이 (한 토큰씩 자르는 Python 구현과 다르다)
+// 짧은 쪽이 예산의 절반 안이면 그대로 두고 긴 쪽만 자르고, 아니면 둘 다 절반으로 자른다
+// 홀수 예산의 남는 한 토큰은 긴 쪽, 길이가 같으면 두 번째 문장이 가진다
+func longestFirst(n1, n2, budget int) (int, int) {
+	if n1+n2 <= budget {
+		return n1, n2
+	}
+	swap := n1 > n2
+	if swap {
+		n1, n2 = n2, n1
+	}
+	if n1 > budget {
+		n2 = n1
+	} else {
+		n2 = max(n1, budget-n1)
+	}
+	if n1+n2 > budget {
+		n1 = budget / 2
+		n2 = n1 + budget%2
+	}
+	if swap {
+		n1, n2 = n2, n1
+	}
+	return n1, n2
+}
+
+// encodeWords 특수 토큰 없이 본문만 토큰화 (normalizer → Metaspace → 조각별 Unigram)
+func (t *Tokenizer) encodeWords(text string) []int64 {
+	var ids []int64
+	for _, word := range t.metaspace.words(t.normalize(text)) {
+		ids = append(ids, t.encodeWord(word)...)
+	}
+	return ids
+}
+
+// encodeWord Viterbi로 점수 합이 최대인 분할 선택, 연속된 unk는 하나로 합친다
+func (t *Tokenizer) encodeWord(word string) []int64 {
+	runes := []rune(word)
+	n := len(runes)
+
+	type node struct {
+		score float64
+		start int
+		id    int
+	}
+	best := make([]node, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = node{score: math.Inf(-1), start: -1}
+	}
+
+	for start := 0; start < n; start++ {
+		if math.IsInf(best[start].score, -1) {
+			continue
+		}
+		matched := false
+		for end := start + 1; end <= n && end-start <= t.maxRunes; end++ {
+			id, ok := t.pieces[string(runes[start:end])]
+			if !ok {
+				continue
+			}
+			matched = matched || end == start+1
+			if score := best[start].score + t.scores[id]; score > best[end].score {
+				best[end] = node{score: score, start: start, id: id}
+			}
+		}
+		// 한 글자 조각도 없으면 unk로 한 글자 진행
+		if !matched {
+			if score := best[start].score + t.unkScore; score > best[start+1].score {
+				best[start+1] = node{score: score, start: start, id: t.unkID}
+			}
+		}
+	}
+
+	ids := make([]int64, 0, n)
+	for end := n; end > 0; end = best[end].start {
+		ids = append(ids, int64(best[end].id))
+	}
+	// 역순 → 정순, 연속 unk 병합
+	result := make([]int64, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		if ids[i] == int64(t.unkID) && len(result) > 0 && result[len(result)-1] == int64(t.unkID) {
+			continue
+		}
+		result = append(result, ids[i])
+	}
+	return result
+}
@@ -0,0 +1,344 @@
+package embedder
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// trieNode precompiled_charsmap 테스트용 trie
+type trieNode struct {
+	children map[byte]*trieNode
+	value    int
+	leaf     bool
+}
+
+// buildCharsmap 원문 → 정규화 문자열 맵을 SentencePiece precompiled_charsmap 형식(base64)으로 만든다
+// darts-clone 이중 배열을 단순 탐색으로 배치한다 (작은 테스트 맵 전용)
+func buildCharsmap(t *testing.T, entries map[string]string) string {
+	t.Helper()
+	root := &trieNode{children: map[byte]*trieNode{}}
+	var normalized []byte
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		node := root
+		for i := 0; i < len(key); i++ {
+			child, ok := node.children[key[i]]
+			if !ok {
+				child = &trieNode{children: map[byte]*trieNode{}}
+				node.children[key[i]] = child
+			}
+			node = child
+		}
+		node.leaf = true
+		node.value = len(normalized)
+		normalized = append(normalized, entries[key]...)
+		normalized = append(normalized, 0)
+	}
+
+	units := make([]uint32, 1)
+	used := map[int]bool{0: true}
+	var place func(node *trieNode, pos int)
+	place = func(node *trieNode, pos int) {
+		labels := make([]int, 0, len(node.children)+1)
+		if node.leaf {
+			labels = append(labels, 0)
+		}
+		for label := range node.children {
+			labels = append(labels, int(label))
+		}
+		sort.Ints(labels)
+
+		base := 1
+		for ; ; base++ {
+			free := true
+			for _, label := range labels {
+				if used[base^label] {
+					free = false
+					break
+				}
+			}
+			if free {
+				break
+			}
+		}
+		for _, label := range labels {
+			used[base^label] = true
+			for len(units) <= base^label {
+				units = append(units, 0)
+			}
+		}
+
+		units[pos] |= uint32(pos^base) << 10
+		if node.leaf {
+			units[base] = uint32(node.value) | 1<<31
+		}
+		for _, label := range labels {
+			if label == 0 {
+				continue
+			}
+			child := node.children[byte(label)]
+			unit := uint32(label)
+			if child.leaf {
+				unit |= 1 << 8
+			}
+			units[base^label] = unit
+			place(child, base^label)
+		}
+	}
+	place(root, 0)
+
+	raw := make([]byte, 4+4*len(units))
+	binary.LittleEndian.PutUint32(raw, uint32(4*len(units)))
+	for i, unit := range units {
+		binary.LittleEndian.PutUint32(raw[4+4*i:], unit)
+	}
+	return base64.StdEncoding.EncodeToString(append(raw, normalized...))
+}
+
+// writeTestTokenizer XLM-RoBERTa tokenizer.json과 같은 구조의 작은 Unigram 토크나이저
+func writeTestTokenizer(t *testing.T, pieces []string) string {
+	t.Helper()
+	vocab := [][]interface{}{{"<s>", 0.0}, {"<pad>", 0.0}, {"</s>", 0.0}, {"<unk>", 0.0}}
+	for _, piece := range pieces {
+		vocab = append(vocab, []interface{}{piece, -1.0})
+	}
+	file := map[string]interface{}{
+		"added_tokens": []map[string]interface{}{
+			{"id": 0, "content": "<s>"}, {"id": 1, "content": "<pad>"}, {"id": 2, "content": "</s>"}, {"id": 3, "content": "<unk>"},
+		},
+		"normalizer": map[string]interface{}{
+			"type": "Sequence",
+			"normalizers": []map[string]interface{}{
+				{"type": "Precompiled", "precompiled_charsmap": buildCharsmap(t, map[string]string{
+					"Ａ":       "A",
+					"\t":      " ",
+					"\u3000":  " ",
+					"e\u0301": "\u00e9",
+				})},
+				{"type": "Replace", "pattern": map[string]string{"Regex": " {2,}"}, "content": " "},
+			},
+		},
+		"pre_tokenizer": map[string]interface{}{
+			"type": "Metaspace", "replacement": "▁", "prepend_scheme": "always", "split": true,
+		},
+		"model": map[string]interface{}{"type": "Unigram", "unk_id": 3, "vocab": vocab},
+	}
+	raw, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tokenizer.json")
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func equalInt64s(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTokenizerPipeline(t *testing.T) {
+	// ID 4부터: ▁=4 ▁hello=5 ▁world=6 ▁A=7 ▁é=8 ▁위스키=9 ▁Glen=10 fiddich=11
+	tokenizer, err := LoadTokenizer(writeTestTokenizer(t, []string{"▁", "▁hello", "▁world", "▁A", "▁\u00e9", "▁위스키", "▁Glen", "fiddich"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []int64
+	}{
+		{"영어", "hello world", []int64{0, 5, 6, 2}},
+		{"한국어", "위스키", []int64{0, 9, 2}},
+		{"한영 혼합", "위스키 Glenfiddich", []int64{0, 9, 10, 11, 2}},
+		{"연속 공백은 하나로, 앞 공백은 접두 ▁에 흡수", "  hello   world", []int64{0, 5, 6, 2}},
+		{"끝 공백은 ▁ 토큰으로 남는다", "hello ", []int64{0, 5, 4, 2}},
+		{"문자 맵: 전각 문자와 탭", "Ａ\tworld", []int64{0, 7, 6, 2}},
+		{"문자 맵: 전각 공백", "hello\u3000world", []int64{0, 5, 6, 2}},
+		{"문자 맵: 결합 문자 그래핌", "e\u0301", []int64{0, 8, 2}},
+		{"사전에 없는 문자는 unk 하나로", "xyz", []int64{0, 4, 3, 2}},
+		{"빈 문자열", "", []int64{0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenizer.Encode(tt.text, 0); !equalInt64s(got, tt.want) {
+				t.Fatalf("Encode(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetaspaceSplitterConfig(t *testing.T) {
+	never := false
+	tests := []struct {
+		name string
+		cfg  *preTokenizerConfig
+		text string
+		want []string
+	}{
+		{"기본값", nil, "a b", []string{"▁a", "▁b"}},
+		{"add_prefix_space false", &preTokenizerConfig{Type: "Metaspace", AddPrefixSpace: &never}, "a b", []string{"a", "▁b"}},
+		{"split false", &preTokenizerConfig{Type: "Metaspace", Split: &never}, "a b", []string{"▁a▁b"}},
+		{"연속 구분자", nil, "a  b", []string{"▁a", "▁", "▁b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter, err := newMetaspaceSplitter(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := splitter.words(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("words(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("words(%q) = %q, want %q", tt.text, got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := newMetaspaceSplitter(&preTokenizerConfig{Type: "ByteLevel"}); err == nil {
+		t.Fatal("ByteLevel 오류 기대")
+	}
+}
+
+// TestEncodePairLongestFirst HuggingFace tokenizers LongestFirst와 같은 길이로 자르는지
+// 기대 길이는 transformers 빠른 토크나이저 tokenizer(a, b, truncation="longest_first", max_length=n) 결과
+// (예산 = max_length - 특수 토큰 4개)
+func TestEncodePairLongestFirst(t *testing.T) {
+	// ▁a=4 ... ▁z=29: 글자 하나가 토큰 하나
+	letters := "abcdefghijklmnopqrstuvwxyz"
+	pieces := make([]string, len(letters))
+	for i, c := range letters {
+		pieces[i] = "▁" + string(c)
+	}
+	tokenizer, err := LoadTokenizer(writeTestTokenizer(t, pieces))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// text n개 글자 문장 (from부터)과 그 토큰 ID
+	text := func(from, n int) (string, []int64) {
+		words := make([]string, n)
+		ids := make([]int64, n)
+		for i := 0; i < n; i++ {
+			words[i] = string(letters[from+i])
+			ids[i] = int64(4 + from + i)
+		}
+		return strings.Join(words, " "), ids
+	}
+
+	tests := []struct {
+		name          string
+		first, second int
+		maxLength     int
+		wantA, wantB  int
+	}{
+		{"자르지 않음", 3, 4, 20, 3, 4},
+		{"예산과 같음", 3, 4, 11, 3, 4},
+		{"maxLength 0은 자르지 않음", 6, 6, 0, 6, 6},
+		{"짧은 첫 문장은 그대로, 긴 두 번째만", 2, 10, 10, 2, 4},
+		{"짧은 두 번째 문장은 그대로, 긴 첫 문장만", 10, 2, 10, 4, 2},
+		{"짧은 쪽이 예산 절반과 같음", 3, 9, 10, 3, 3},
+		{"같은 길이, 짝수 예산", 5, 5, 10, 3, 3},
+		{"같은 길이, 홀수 예산은 두 번째가 하나 더", 5, 5, 11, 3, 4},
+		{"같은 길이, 예산 1", 3, 3, 5, 0, 1},
+		{"두 번째가 길고 홀수 예산", 4, 5, 11, 3, 4},
+		{"첫 문장이 길고 홀수 예산", 5, 4, 11, 4, 3},
+		{"둘 다 예산보다 김", 9, 12, 10, 3, 3},
+		{"둘 다 예산보다 김, 홀수 예산", 12, 9, 9, 3, 2},
+		{"예산 0", 8, 8, 4, 0, 0},
+		{"빈 두 번째 문장", 10, 0, 8, 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, a := text(0, tt.first)
+			second, b := text(tt.first, tt.second)
+			want := append([]int64{0}, a[:tt.wantA]...)
+			want = append(want, 2, 2)
+			want = append(want, b[:tt.wantB]...)
+			want = append(want, 2)
+			if got := tokenizer.EncodePair(first, second, tt.maxLength); !equalInt64s(got, want) {
+				t.Fatalf("EncodePair(%d, %d, %d) = %v, want %v", tt.first, tt.second, tt.maxLength, got, want)
+			}
+		})
+	}
+}
+
+// tokenizerFixture testdata/tokenizer_fixtures.json (transformers로 생성)
+type tokenizerFixture struct {
+	Model  string `json:"model"`
+	Single []struct {
+		Text string  `json:"text"`
+		IDs  []int64 `json:"ids"`
+	} `json:"single"`
+	// Pair tokenizer(first, second, truncation="longest_first", max_length=max_length)
+	Pair []struct {
+		First     string  `json:"first"`
+		Second    string  `json:"second"`
+		MaxLength int     `json:"max_length"`
+		IDs       []int64 `json:"ids"`
+	} `json:"pair"`
+}
+
+// 실제 BGE-M3 tokenizer.json과 transformers 결과 비교
+// fixture 생성: python3 pkg/embedder/testdata/tokenizer_fixtures.py (transformers, 네트워크 필요)
+// 토크나이저 경로: TOKENIZER_PATH 또는 'download'가 받은 ~/.cache/embedding-worker/tokenizer.json
+func TestTokenizerMatchesTransformers(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "tokenizer_fixtures.json"))
+	if os.IsNotExist(err) {
+		t.Skip("testdata/tokenizer_fixtures.json 없음: tokenizer_fixtures.py로 생성")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture tokenizerFixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		t.Fatal(err)
+	}
+
+	path := os.Getenv("TOKENIZER_PATH")
+	if path == "" {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, ".cache", "embedding-worker", "tokenizer.json")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("%s 토크나이저 없음 (%s): TOKENIZER_PATH 지정", fixture.Model, path)
+	}
+	tokenizer, err := LoadTokenizer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range fixture.Single {
+		if got := tokenizer.Encode(tc.Text, DefaultMaxLength); !equalInt64s(got, tc.IDs) {
+			t.Errorf("Encode(%q)\n got: %v\nwant: %v", tc.Text, got, tc.IDs)
+		}
+	}
+	for _, tc := range fixture.Pair {
+		if got := tokenizer.EncodePair(tc.First, tc.Second, tc.MaxLength); !equalInt64s(got, tc.IDs) {
+			t.Errorf("EncodePair(%q, %q, %d)\n got: %v\nwant: %v", tc.First, tc.Second, tc.MaxLength, got, tc.IDs)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package fusion 여러 검색 결과(leg)를 하나의 순위로 융합 (RRF, DBSF, min-max, z-score)
+// 벡터 저장소의 내장 융합에 기대지 않으므로 서로 다른 저장소/쿼리의 결과도 섞을 수 있다
+package fusion
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Method 융합 방식
+type Method string
+
+const (
+	// RRF Reciprocal Rank Fusion: Σ w / (k + rank)
+	RRF Method = "rrf"
+	// Linear min-max 정규화 후 가중 합
+	Linear Method = "linear"
+	// DBSF Distribution-Based Score Fusion: 평균 ± 3σ를 [0, 1]로 정규화 후 가중 합
+	DBSF Method = "dbsf"
+	// ZScore 표준점수 정규화 후 가중 합
+	ZScore Method = "zscore"
+)
+
+// DefaultRRFK RRF 상수 (Vespa reciprocal_rank, Qdrant rrf 기본값과 동일)
+const DefaultRRFK = 60
+
+// Parse 설정 문자열을 Method로 변환
+func Parse(value string) (Method, error) {
+	switch m := Method(value); m {
+	case RRF, Linear, DBSF, ZScore:
+		return m, nil
+	default:
+		return "", fmt.Errorf("지원하지 않는 융합 방식: %q", value)
+	}
+}
+
+// TieBreak 융합 점수가 같을 때 순서
+type TieBreak string
+
+const (
+	// TieBreakID ID 오름차순 (기본)
+	TieBreakID TieBreak = "id"
+	// TieBreakRank leg 중 가장 높은 순위, 그다음 더 많은 leg에 나온 문서, 그다음 ID
+	TieBreakRank TieBreak = "rank"
+)
+
+// Item leg 결과 단건
+type Item struct {
+	ID    int64
+	Score float64
+}
+
+// Options 융합 설정
+type Options struct {
+	Method Method
+	// Weights leg별 가중치 (없으면 1.0, 0이면 그 leg는 빼고 융합한다)
+	Weights map[string]float64
+	// K RRF 상수 (0이면 DefaultRRFK)
+	K        int
+	TieBreak TieBreak
+}
+
+// Result 융합 결과 단건
+type Result struct {
+	ID    int64
+	Score float64
+	// Scores leg별 원점수 (그 leg에 나온 경우만)
+	Scores map[string]float64
+	// Ranks leg별 순위 (0부터)
+	Ranks map[string]int
+}
+
+// Fuse leg별 결과를 융합해 점수 내림차순으로 반환
+// 각 leg는 점수 내림차순이어야 한다 (RRF는 leg 안의 순서를 순위로 본다)
+// leg에 없는 문서는 그 leg에서 0을 받는다
+// z-score는 평균이 0이라 그대로 더하면 평균 아래 문서가 안 나온 문서보다 낮아지므로, leg 최저점이 0이 되도록 옮겨 더한다
+func Fuse(legs map[string][]Item, opts Options) []Result {
+	k := opts.K
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+
+	// 부동소수 합 순서가 실행마다 같도록 leg 이름순으로 더한다
+	names := make([]string, 0, len(legs))
+	for name := range legs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fused := make(map[int64]*Result)
+	for _, name := range names {
+		items := legs[name]
+		weight, ok := opts.Weights[name]
+		if !ok {
+			weight = 1.0
+		}
+		// 가중치 0인 leg는 없는 것과 같다 (그 leg에만 나온 문서, 동점 순위에도 반영하지 않는다)
+		if weight == 0 {
+			continue
+		}
+
+		var normalized []float64
+		if opts.Method != RRF {
+			scores := make([]float64, len(items))
+			for i, item := range items {
+				scores[i] = item.Score
+			}
+			normalized = Normalize(opts.Method, scores)
+			if opts.Method == ZScore {
+				shiftToZero(normalized)
+			}
+		}
+
+		for rank, item := range items {
+			target, ok := fused[item.ID]
+			if !ok {
+				target = &Result{ID: item.ID, Scores: make(map[string]float64), Ranks: make(map[string]int)}
+				fused[item.ID] = target
+			}
+			// 같은 leg에 중복으로 나오면 첫 (가장 높은) 순위만 반영
+			if _, seen := target.Ranks[name]; seen {
+				continue
+			}
+			target.Scores[name] = item.Score
+			target.Ranks[name] = rank
+
+			if opts.Method == RRF {
+				target.Score += weight / float64(k+rank+1)
+			} else {
+				target.Score += weight * normalized[rank]
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(fused))
+	for _, r := range fused {
+		results = append(results, *r)
+	}
+	Sort(results, opts.TieBreak)
+	return results
+}
+
+// Normalize leg 점수 정규화 (RRF는 점수를 쓰지 않으므로 그대로 반환)
+//   - Linear: (s - min) / (max - min), 모두 같으면 1
+//   - DBSF: (s - (μ - 3σ)) / 6σ 를 [0, 1]로 자름, 모두 같으면 0.5
+//   - ZScore: (s - μ) / σ, 모두 같으면 0
+func Normalize(method Method, scores []float64) []float64 {
+	normalized := make([]float64, len(scores))
+	if len(scores) == 0 {
+		return normalized
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, s := range scores {
+		min = math.Min(min, s)
+		max = math.Max(max, s)
+	}
+	// 점수가 모두 같으면 σ를 계산하지 않는다 (평균 합산 오차로 σ가 0이 아니게 나올 수 있다)
+	constant := max == min
+
+	switch method {
+	case Linear:
+		for i, s := range scores {
+			if constant {
+				normalized[i] = 1
+			} else {
+				normalized[i] = math.Max(0, math.Min(1, (s-min)/(max-min)))
+			}
+		}
+	case DBSF:
+		mean, std := meanStd(scores)
+		for i, s := range scores {
+			if constant || std == 0 {
+				normalized[i] = 0.5
+				continue
+			}
+			lower := mean - 3*std
+			normalized[i] = math.Max(0, math.Min(1, (s-lower)/(6*std)))
+		}
+	case ZScore:
+		mean, std := meanStd(scores)
+		for i, s := range scores {
+			if !constant && std > 0 {
+				normalized[i] = (s - mean) / std
+			}
+		}
+	default:
+		copy(normalized, scores)
+	}
+	return normalized
+}
+
+// shiftToZero 최저점이 0이 되도록 모든 점수를 같은 만큼 옮긴다 (순서와 간격은 그대로)
+func shiftToZero(scores []float64) {
+	if len(scores) == 0 {
+		return
+	}
+	min := math.Inf(1)
+	for _, s := range scores {
+		min = math.Min(min, s)
+	}
+	for i := range scores {
+		scores[i] -= min
+	}
+}
+
+// meanStd 평균과 모표준편차
+func meanStd(scores []float64) (mean, std float64) {
+	for _, s := range scores {
+		mean += s
+	}
+	mean /= float64(len(scores))
+	for _, s := range scores {
+		std += (s - mean) * (s - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(scores)))
+}
+
+// Sort 점수 내림차순, 동점이면 tieBreak 순서
+func Sort(results []Result, tieBreak TieBreak) {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if tieBreak == TieBreakRank {
+			if ra, rb := bestRank(a), bestRank(b); ra != rb {
+				return ra < rb
+			}
+			if len(a.Ranks) != len(b.Ranks) {
+				return len(a.Ranks) > len(b.Ranks)
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+// bestRank leg 중 가장 높은 순위 (leg가 없으면 MaxInt)
+func bestRank(r Result) int {
+	best := math.MaxInt
+	for _, rank := range r.Ranks {
+		if rank < best {
+			best = rank
+		}
+	}
+	return best
+}
@@ -0,0 +1,264 @@
+package fusion
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"testing"
+)
+
+var methods = []Method{RRF, Linear, DBSF, ZScore}
+
+// randomLegs 점수 내림차순 leg n개 (ID가 leg 사이에 겹치고, 일부 점수는 같다)
+func randomLegs(rng *rand.Rand, n int) map[string][]Item {
+	legs := make(map[string][]Item, n)
+	for l := 0; l < n; l++ {
+		size := rng.IntN(8)
+		seen := make(map[int64]bool)
+		items := make([]Item, 0, size)
+		for len(items) < size {
+			id := int64(rng.IntN(12) + 1)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			// 0.25 단위로 반올림해 동점을 자주 만든다
+			items = append(items, Item{ID: id, Score: math.Round(rng.Float64()*8) / 4})
+		}
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Score > items[j].Score })
+		legs[fmt.Sprintf("leg%d", l)] = items
+	}
+	return legs
+}
+
+func randomWeights(rng *rand.Rand, legs map[string][]Item) map[string]float64 {
+	weights := make(map[string]float64, len(legs))
+	for name := range legs {
+		weights[name] = math.Round(rng.Float64()*4)/2 + 0.5
+	}
+	return weights
+}
+
+// sameRanking 순서와 점수가 같은지 (부동소수 합 순서 차이만 허용)
+func sameRanking(a, b []Result) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("결과 수 %d != %d", len(a), len(b))
+	}
+	for i := range a {
+		if math.Abs(a[i].Score-b[i].Score) > 1e-12 {
+			return fmt.Errorf("%d번째 점수 %v != %v", i, a[i].Score, b[i].Score)
+		}
+		// 점수가 사실상 같은 구간은 합 순서에 따라 뒤바뀔 수 있다
+		if a[i].ID != b[i].ID && !nearTie(a, i) {
+			return fmt.Errorf("%d번째 ID %d != %d", i, a[i].ID, b[i].ID)
+		}
+	}
+	return nil
+}
+
+func nearTie(results []Result, i int) bool {
+	return (i > 0 && math.Abs(results[i].Score-results[i-1].Score) <= 1e-12) ||
+		(i+1 < len(results) && math.Abs(results[i].Score-results[i+1].Score) <= 1e-12)
+}
+
+// leg 이름(합산 순서)을 바꿔도 같은 가중치면 같은 순위
+func TestFuseLegOrderInvariance(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for trial := 0; trial < 200; trial++ {
+		legs := randomLegs(rng, 1+rng.IntN(4))
+		weights := randomWeights(rng, legs)
+
+		// 이름을 뒤집어 정렬 순서(= 더하는 순서)를 바꾼다
+		renamed := make(map[string][]Item, len(legs))
+		renamedWeights := make(map[string]float64, len(legs))
+		perm := rng.Perm(len(legs))
+		i := 0
+		for name, items := range legs {
+			newName := fmt.Sprintf("x%d", perm[i])
+			renamed[newName] = items
+			renamedWeights[newName] = weights[name]
+			i++
+		}
+
+		for _, method := range methods {
+			for _, tieBreak := range []TieBreak{TieBreakID, TieBreakRank} {
+				opts := Options{Method: method, Weights: weights, TieBreak: tieBreak}
+				got := Fuse(renamed, Options{Method: method, Weights: renamedWeights, TieBreak: tieBreak})
+				if err := sameRanking(Fuse(legs, opts), got); err != nil {
+					t.Fatalf("trial %d %s/%s: %v", trial, method, tieBreak, err)
+				}
+			}
+		}
+	}
+}
+
+// 가중치 0인 leg를 더해도 결과는 그 leg가 없을 때와 같다
+func TestFuseZeroWeightLeg(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for trial := 0; trial < 200; trial++ {
+		legs := randomLegs(rng, 1+rng.IntN(3))
+		weights := randomWeights(rng, legs)
+
+		withZero := make(map[string][]Item, len(legs)+1)
+		for name, items := range legs {
+			withZero[name] = items
+		}
+		withZero["zero"] = randomLegs(rng, 1)["leg0"]
+		zeroWeights := map[string]float64{"zero": 0}
+		for name, weight := range weights {
+			zeroWeights[name] = weight
+		}
+
+		for _, method := range methods {
+			for _, tieBreak := range []TieBreak{TieBreakID, TieBreakRank} {
+				want := Fuse(legs, Options{Method: method, Weights: weights, TieBreak: tieBreak})
+				got := Fuse(withZero, Options{Method: method, Weights: zeroWeights, TieBreak: tieBreak})
+				if err := sameRanking(want, got); err != nil {
+					t.Fatalf("trial %d %s/%s: %v", trial, method, tieBreak, err)
+				}
+				for _, r := range got {
+					if _, ok := r.Ranks["zero"]; ok {
+						t.Fatalf("trial %d %s: 가중치 0 leg가 결과에 남음 %+v", trial, method, r)
+					}
+				}
+			}
+		}
+	}
+}
+
+// 같은 입력이면 맵 순회 순서와 상관없이 항상 같은 순서, 동점은 tie-break 규칙대로
+func TestFuseDeterministicTieBreak(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	for trial := 0; trial < 100; trial++ {
+		legs := randomLegs(rng, 1+rng.IntN(4))
+		weights := randomWeights(rng, legs)
+		for _, method := range methods {
+			for _, tieBreak := range []TieBreak{TieBreakID, TieBreakRank} {
+				opts := Options{Method: method, Weights: weights, TieBreak: tieBreak}
+				first := Fuse(legs, opts)
+				for run := 0; run < 5; run++ {
+					again := Fuse(legs, opts)
+					for i := range first {
+						if first[i].ID != again[i].ID || first[i].Score != again[i].Score {
+							t.Fatalf("trial %d %s/%s: 실행마다 다른 결과 %v / %v", trial, method, tieBreak, first, again)
+						}
+					}
+				}
+				for i := 1; i < len(first); i++ {
+					a, b := first[i-1], first[i]
+					if a.Score < b.Score {
+						t.Fatalf("trial %d %s: 점수 내림차순 아님 %v", trial, method, first)
+					}
+					if a.Score != b.Score {
+						continue
+					}
+					if tieBreak == TieBreakID && a.ID > b.ID {
+						t.Fatalf("trial %d %s: 동점 ID 순서 %d > %d", trial, method, a.ID, b.ID)
+					}
+					if tieBreak == TieBreakRank {
+						ra, rb := bestRank(a), bestRank(b)
+						if ra > rb || (ra == rb && len(a.Ranks) < len(b.Ranks)) ||
+							(ra == rb && len(a.Ranks) == len(b.Ranks) && a.ID > b.ID) {
+							t.Fatalf("trial %d %s: 동점 순위 순서 %+v, %+v", trial, method, a, b)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFuseTieBreakExamples(t *testing.T) {
+	// 두 문서 모두 RRF 1/(k+1): a는 leg a 1위, b는 leg b 1위
+	legs := map[string][]Item{
+		"a": {{ID: 9, Score: 1}, {ID: 3, Score: 0.5}},
+		"b": {{ID: 5, Score: 1}, {ID: 3, Score: 0.4}},
+	}
+	// 3: 두 leg 모두 2위 (점수 2/(k+2)), 5와 9: 한 leg 1위 (1/(k+1))
+	byID := Fuse(legs, Options{Method: RRF})
+	if byID[0].ID != 3 || byID[1].ID != 5 || byID[2].ID != 9 {
+		t.Fatalf("ID tie-break %v", byID)
+	}
+
+	legs = map[string][]Item{
+		"a": {{ID: 1, Score: 1}, {ID: 2, Score: 1}},
+		"b": {{ID: 2, Score: 1}, {ID: 1, Score: 1}},
+		"c": {{ID: 4, Score: 1}},
+	}
+	// Linear: 상수 leg는 모두 1 → 1, 2는 2점, 4는 1점
+	rank := Fuse(legs, Options{Method: Linear, TieBreak: TieBreakRank})
+	if rank[0].ID != 1 || rank[1].ID != 2 || rank[2].ID != 4 {
+		t.Fatalf("rank tie-break %v", rank)
+	}
+}
+
+// leg에 나온 문서는 꼴찌여도 그 leg에 안 나온 문서보다 낮은 점수를 받지 않는다
+func TestFuseMissingLegNotAboveLast(t *testing.T) {
+	// 1: a 꼴찌, b 1위 / 2: a에 없음, b 2위 (b 점수 차는 a 꼴찌의 손해보다 작다)
+	legs := map[string][]Item{
+		"a": {{ID: 3, Score: 0.9}, {ID: 4, Score: 0.8}, {ID: 5, Score: 0.7}, {ID: 1, Score: 0.1}},
+		"b": {{ID: 1, Score: 0.51}, {ID: 2, Score: 0.5}, {ID: 6, Score: 0.1}},
+	}
+	for _, method := range methods {
+		results := Fuse(legs, Options{Method: method})
+		scores := make(map[int64]float64, len(results))
+		for _, r := range results {
+			scores[r.ID] = r.Score
+		}
+		if scores[1] < scores[2] {
+			t.Fatalf("%s: a 꼴찌 문서 %v < a에 없는 문서 %v (%v)", method, scores[1], scores[2], results)
+		}
+	}
+}
+
+func TestNormalizeBounds(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	for trial := 0; trial < 500; trial++ {
+		scores := make([]float64, 1+rng.IntN(20))
+		scale := math.Pow(10, float64(rng.IntN(8)-4))
+		for i := range scores {
+			scores[i] = (rng.Float64()*2 - 1) * scale
+		}
+		for _, method := range []Method{Linear, DBSF} {
+			for i, v := range Normalize(method, scores) {
+				if math.IsNaN(v) || v < 0 || v > 1 {
+					t.Fatalf("%s(%v)[%d] = %v, [0, 1] 밖", method, scores, i, v)
+				}
+			}
+		}
+		for i, v := range Normalize(ZScore, scores) {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				t.Fatalf("zscore(%v)[%d] = %v", scores, i, v)
+			}
+		}
+	}
+}
+
+// 점수가 모두 같은 leg (합산 오차로 평균이 그 값과 조금 달라도) NaN 없이 정해진 값
+func TestNormalizeConstantScores(t *testing.T) {
+	want := map[Method]float64{Linear: 1, DBSF: 0.5, ZScore: 0}
+	for _, value := range []float64{0, 0.1, -3.7, 1e-300, 1e300} {
+		for _, n := range []int{1, 3, 7} {
+			scores := make([]float64, n)
+			for i := range scores {
+				scores[i] = value
+			}
+			for method, expected := range want {
+				for i, v := range Normalize(method, scores) {
+					if v != expected {
+						t.Fatalf("%s(%d × %v)[%d] = %v, want %v", method, n, value, i, v, expected)
+					}
+				}
+			}
+			// 융합 점수도 NaN이 아니다
+			for _, method := range methods {
+				for _, r := range Fuse(map[string][]Item{"a": {{ID: 1, Score: value}}, "b": {{ID: 2, Score: value}, {ID: 1, Score: value}}}, Options{Method: method}) {
+					if math.IsNaN(r.Score) {
+						t.Fatalf("%s 융합 NaN: %+v", method, r)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// maxAlcoholRange 한 번에 조회할 수 있는 최대 ID 범위
+const maxAlcoholRange = 1000
+
+// AlcoholHandler 술 조회 HTTP 핸들러
+type AlcoholHandler struct {
+	alcohols *repository.AlcoholRepository
+}
+
+// NewAlcoholHandler 생성자
+func NewAlcoholHandler(alcohols *repository.AlcoholRepository) *AlcoholHandler {
+	return &AlcoholHandler{
+		alcohols: alcohols,
+	}
+}
+
+// Register 라우터 등록
+func (h *AlcoholHandler) Register(e *echo.Echo) {
+	e.GET("/alcohols", h.ListByRange)
+}
+
+// ListByRange ID 범위로 술 조회 (?start_id=&end_id=)
+func (h *AlcoholHandler) ListByRange(c echo.Context) error {
+	startID, err := strconv.ParseInt(c.QueryParam("start_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "start_id must be an integer",
+		})
+	}
+	endID, err := strconv.ParseInt(c.QueryParam("end_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "end_id must be an integer",
+		})
+	}
+	if endID < startID || endID-startID >= maxAlcoholRange {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "end_id must be >= start_id and the range must be < " + strconv.Itoa(maxAlcoholRange),
+		})
+	}
+
+	alcohols, err := h.alcohols.FindByIDRange(c.Request().Context(), startID, endID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, alcohols)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// composeRequest POST /search/compose 본문
+type composeRequest struct {
+	Positive []composeInput `json:"positive"`
+	Negative []composeInput `json:"negative"`
+	// Type dense 필드 이름 또는 hybrid (비우면 hybrid)
+	Type string `json:"type"`
+	// Filter JSON 조건식 (repository.ParseFilter), 쿼리 파라미터 필터와 AND
+	Filter        json.RawMessage `json:"filter"`
+	IncludeInputs bool            `json:"include_inputs"`
+	Offset        int             `json:"offset"`
+	Limit         int             `json:"limit"`
+}
+
+// composeInput {"text": "..."} 또는 {"id": 12}, weight는 생략하면 1
+type composeInput struct {
+	Text   string   `json:"text"`
+	ID     int64    `json:"id"`
+	Weight *float64 `json:"weight"`
+}
+
+// Compose 긍정/부정 입력(텍스트 또는 술 ID) 벡터를 조합해 검색
+//
+//	POST /search/compose?region_id=3&mmr=true
+//	{"positive": [{"id": 12}], "negative": [{"text": "스모키", "weight": 0.5}], "type": "flavor"}
+//
+// 본문 filter와 쿼리 파라미터 필터(type 제외), 다양화 파라미터는 /search와 같다 (offset, limit은 본문 우선)
+func (h *SearchHandler) Compose(c echo.Context) error {
+	var req composeRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body: " + err.Error(),
+		})
+	}
+	query, err := parseComposeQuery(c, req, h.searcher.Diversity())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.searcher.Compose(c.Request().Context(), query)
+	if errors.Is(err, service.ErrAlcoholNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrUnknownVectorType) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":        err.Error(),
+			"vector_types": append(h.searcher.VectorTypes(), service.SimilarHybrid),
+		})
+	}
+	if errors.Is(err, service.ErrInvalidCompose) || errors.Is(err, service.ErrInvalidDiversity) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// parseComposeQuery 본문 + 쿼리 파라미터 필터/다양화 → 조합 검색 요청
+func parseComposeQuery(c echo.Context, req composeRequest, defaults service.Diversity) (service.ComposeQuery, error) {
+	query := service.ComposeQuery{
+		Positive:      toComposeInputs(req.Positive),
+		Negative:      toComposeInputs(req.Negative),
+		VectorType:    req.Type,
+		IncludeInputs: req.IncludeInputs,
+		Offset:        req.Offset,
+		Limit:         req.Limit,
+	}
+	if req.Offset < 0 {
+		return query, errors.New("offset must be a non-negative integer")
+	}
+	if req.Offset > service.MaxSearchOffset {
+		return query, errors.New("offset must be at most " + strconv.Itoa(service.MaxSearchOffset))
+	}
+	if req.Limit < 0 || req.Limit > service.MaxSearchLimit {
+		return query, errors.New("limit must be between 1 and " + strconv.Itoa(service.MaxSearchLimit))
+	}
+	// 본문에 없으면 /search처럼 offset, limit 쿼리 파라미터
+	offset, limit, err := parsePage(c)
+	if err != nil {
+		return query, err
+	}
+	if query.Offset == 0 {
+		query.Offset = offset
+	}
+	if query.Limit == 0 {
+		query.Limit = limit
+	}
+
+	filters, err := parseSearchFilters(c, "type")
+	if err != nil {
+		return query, err
+	}
+	if len(req.Filter) > 0 && string(req.Filter) != "null" {
+		f, err := repository.ParseFilter(req.Filter)
+		if err == nil {
+			err = f.Validate()
+		}
+		if err != nil {
+			return query, errors.New("invalid filter: " + err.Error())
+		}
+		filters = append(filters, f)
+	}
+	query.Filters = filters
+
+	if query.Diversity, err = parseDiversity(c, defaults); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func toComposeInputs(inputs []composeInput) []service.ComposeInput {
+	converted := make([]service.ComposeInput, 0, len(inputs))
+	for _, in := range inputs {
+		weight := 1.0
+		if in.Weight != nil {
+			weight = *in.Weight
+		}
+		converted = append(converted, service.ComposeInput{Text: in.Text, ID: in.ID, Weight: weight})
+	}
+	return converted
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// RerankHandler 재순위 HTTP 핸들러 (TEI, Cohere 요청 형식)
+type RerankHandler struct {
+	reranker *service.Reranker
+}
+
+// NewRerankHandler 생성자 (reranker가 nil이면 503)
+func NewRerankHandler(reranker *service.Reranker) *RerankHandler {
+	return &RerankHandler{
+		reranker: reranker,
+	}
+}
+
+// Register 라우터 등록
+func (h *RerankHandler) Register(e *echo.Echo) {
+	e.POST("/rerank", h.Rerank)
+}
+
+// rerankRequest TEI {query, texts, return_text} 또는 Cohere {query, documents, top_n, return_documents}
+type rerankRequest struct {
+	Query string `json:"query"`
+	// Texts TEI 형식 문서
+	Texts      []string `json:"texts"`
+	ReturnText bool     `json:"return_text"`
+	// Documents Cohere 형식 문서 (문자열 또는 {"text": ...})
+	Documents       []json.RawMessage `json:"documents"`
+	TopN            int               `json:"top_n"`
+	ReturnDocuments bool              `json:"return_documents"`
+}
+
+// teiRerankResult TEI 응답 단건
+type teiRerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+	Text  *string `json:"text,omitempty"`
+}
+
+// cohereRerankResult Cohere 응답 단건
+type cohereRerankResult struct {
+	Index          int             `json:"index"`
+	RelevanceScore float64         `json:"relevance_score"`
+	Document       *cohereDocument `json:"document,omitempty"`
+}
+
+type cohereDocument struct {
+	Text string `json:"text"`
+}
+
+// Rerank 질의와 문서 목록을 받아 관련도 내림차순으로
+//   - texts가 있으면 TEI 응답: [{index, score, text?}]
+//   - documents가 있으면 Cohere 응답: {results: [{index, relevance_score, document?}]}
+func (h *RerankHandler) Rerank(c echo.Context) error {
+	if h.reranker == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": service.ErrRerankerDisabled.Error(),
+		})
+	}
+
+	var req rerankRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body: " + err.Error(),
+		})
+	}
+	cohere := req.Documents != nil
+	texts := req.Texts
+	if cohere {
+		var err error
+		if texts, err = cohereTexts(req.Documents); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+	}
+	if err := validateRerankRequest(req, texts); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	results, err := h.reranker.Rerank(c.Request().Context(), strings.TrimSpace(req.Query), texts, req.TopN)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if cohere {
+		response := make([]cohereRerankResult, 0, len(results))
+		for _, r := range results {
+			result := cohereRerankResult{Index: r.Index, RelevanceScore: r.Score}
+			if req.ReturnDocuments {
+				result.Document = &cohereDocument{Text: texts[r.Index]}
+			}
+			response = append(response, result)
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"results": response,
+		})
+	}
+
+	response := make([]teiRerankResult, 0, len(results))
+	for _, r := range results {
+		result := teiRerankResult{Index: r.Index, Score: r.Score}
+		if req.ReturnText {
+			result.Text = &texts[r.Index]
+		}
+		response = append(response, result)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// validateRerankRequest 질의, 문서 수, top_n 확인
+func validateRerankRequest(req rerankRequest, texts []string) error {
+	if strings.TrimSpace(req.Query) == "" {
+		return service.ErrEmptyRerankQuery
+	}
+	if req.Texts != nil && req.Documents != nil {
+		return errors.New("use either texts or documents, not both")
+	}
+	if len(texts) == 0 {
+		return errors.New("texts or documents is required")
+	}
+	if len(texts) > service.MaxRerankDocuments {
+		return fmt.Errorf("at most %d documents are allowed", service.MaxRerankDocuments)
+	}
+	if req.TopN < 0 {
+		return errors.New("top_n must be a non-negative integer")
+	}
+	return nil
+}
+
+// cohereTexts Cohere documents (문자열 또는 {"text": ...}) → 본문
+func cohereTexts(documents []json.RawMessage) ([]string, error) {
+	texts := make([]string, len(documents))
+	for i, raw := range documents {
+		if err := json.Unmarshal(raw, &texts[i]); err == nil {
+			continue
+		}
+		var doc cohereDocument
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("documents[%d] must be a string or an object with text", i)
+		}
+		texts[i] = doc.Text
+	}
+	return texts, nil
+}
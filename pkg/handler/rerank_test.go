@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// fakeScorer 문서 길이가 짧을수록 높은 점수
+type fakeScorer struct{}
+
+func (fakeScorer) Score(ctx context.Context, query string, passages []string) ([]float32, error) {
+	scores := make([]float32, len(passages))
+	for i, p := range passages {
+		scores[i] = 1 / float32(len(p))
+	}
+	return scores, nil
+}
+
+func postRerank(t *testing.T, reranker *service.Reranker, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	NewRerankHandler(reranker).Register(e)
+	req := httptest.NewRequest(http.MethodPost, "/rerank", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRerankHandlerTEI(t *testing.T) {
+	reranker := service.NewReranker(fakeScorer{}, 2)
+
+	rec := postRerank(t, reranker, `{"query":"스모키","texts":["ccc","a","bb"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var results []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("TEI 응답은 배열: %s", rec.Body.String())
+	}
+	want := []float64{1, 2, 0}
+	if len(results) != len(want) || results[0]["score"] != 1.0 || results[1]["score"] != 0.5 {
+		t.Fatalf("results %v", results)
+	}
+	for i := range want {
+		if results[i]["index"] != want[i] || results[i]["text"] != nil {
+			t.Fatalf("results[%d] = %v", i, results[i])
+		}
+	}
+
+	rec = postRerank(t, reranker, `{"query":"스모키","texts":["ccc","a","bb"],"return_text":true}`)
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatal(err)
+	}
+	if results[0]["text"] != "a" || results[2]["text"] != "ccc" {
+		t.Fatalf("return_text %v", results)
+	}
+}
+
+func TestRerankHandlerCohere(t *testing.T) {
+	reranker := service.NewReranker(fakeScorer{}, 2)
+
+	// 문자열과 {"text"} 객체를 섞어 받는다
+	rec := postRerank(t, reranker, `{"query":"스모키","documents":["ccc",{"text":"a"},"bb"],"top_n":2,"return_documents":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+			Document       *struct {
+				Text string `json:"text"`
+			} `json:"document"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Cohere 응답은 객체: %s", rec.Body.String())
+	}
+	indexes := make([]int, len(body.Results))
+	for i, r := range body.Results {
+		indexes[i] = r.Index
+		if r.Document == nil {
+			t.Fatalf("document 누락: %s", rec.Body.String())
+		}
+	}
+	if !reflect.DeepEqual(indexes, []int{1, 2}) || body.Results[0].Document.Text != "a" || body.Results[0].RelevanceScore != 1 {
+		t.Fatalf("results %s", rec.Body.String())
+	}
+
+	rec = postRerank(t, reranker, `{"query":"스모키","documents":["ccc","a"]}`)
+	if strings.Contains(rec.Body.String(), "document") {
+		t.Fatalf("return_documents 없이 문서 포함: %s", rec.Body.String())
+	}
+}
+
+func TestRerankHandlerBadRequest(t *testing.T) {
+	reranker := service.NewReranker(fakeScorer{}, 2)
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"JSON 아님", `{`, "invalid request body"},
+		{"질의 없음", `{"query":" ","texts":["a"]}`, "query is required"},
+		{"문서 없음", `{"query":"q"}`, "texts or documents is required"},
+		{"빈 문서 목록", `{"query":"q","texts":[]}`, "texts or documents is required"},
+		{"texts와 documents 함께", `{"query":"q","texts":["a"],"documents":["b"]}`, "use either texts or documents, not both"},
+		{"document 형식 오류", `{"query":"q","documents":[1]}`, "documents[0] must be a string or an object with text"},
+		{"top_n 음수", `{"query":"q","texts":["a"],"top_n":-1}`, "top_n must be a non-negative integer"},
+		{"문서 수 초과", `{"query":"q","texts":[` + strings.Repeat(`"a",`, service.MaxRerankDocuments) + `"a"]}`, "at most"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postRerank(t, reranker, tt.body)
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			if !strings.HasPrefix(body["error"], tt.want) {
+				t.Fatalf("error %q, want %q", body["error"], tt.want)
+			}
+		})
+	}
+
+	// 재순위 모델이 없으면 503
+	if rec := postRerank(t, nil, `{"query":"q","texts":["a"]}`); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("모델 없음 status %d", rec.Code)
+	}
+}
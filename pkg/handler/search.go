@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// SearchHandler 검색 HTTP 핸들러
+type SearchHandler struct {
+	searcher *service.Searcher
+}
+
+// NewSearchHandler 생성자
+func NewSearchHandler(searcher *service.Searcher) *SearchHandler {
+	return &SearchHandler{
+		searcher: searcher,
+	}
+}
+
+// Register 라우터 등록
+func (h *SearchHandler) Register(e *echo.Echo) {
+	e.GET("/search", h.Hybrid)
+	e.GET("/search/:type", h.Vector)
+	e.GET("/alcohols/:id/similar", h.Similar)
+	e.POST("/search/compose", h.Compose)
+}
+
+// Hybrid 하이브리드 키워드 검색 (?keyword=&offset=&limit=&rerank=&mmr=&lambda=&max_per_distillery=&type=&region_id=&abv_lt=&filter=...)
+func (h *SearchHandler) Hybrid(c echo.Context) error {
+	query, err := parseSearchQuery(c, h.searcher.Diversity())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.searcher.Hybrid(c.Request().Context(), query)
+	if errors.Is(err, service.ErrEmptyKeyword) || errors.Is(err, service.ErrRerankerDisabled) || errors.Is(err, service.ErrInvalidDiversity) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// Vector 한 벡터 타입만 검색 (/search/flavor?keyword=&offset=&limit=, 필터는 /search와 같다)
+func (h *SearchHandler) Vector(c echo.Context) error {
+	query, err := parseSearchQuery(c, h.searcher.Diversity())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.searcher.Vector(c.Request().Context(), c.Param("type"), query)
+	if errors.Is(err, service.ErrUnknownVectorType) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":        err.Error(),
+			"vector_types": h.searcher.VectorTypes(),
+		})
+	}
+	if errors.Is(err, service.ErrEmptyKeyword) || errors.Is(err, service.ErrInvalidDiversity) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// 필터 쿼리 파라미터 → 페이로드 필드
+var (
+	// searchTextFilters 문자열 일치 (tasting_tag는 배열 포함 여부)
+	searchTextFilters = map[string]string{
+		"type":           "type",
+		"category_group": "categoryGroup",
+		"tasting_tag":    "tastingTags",
+	}
+	// searchIDFilters 정수 일치
+	searchIDFilters = map[string]string{
+		"region_id":     "region_id",
+		"distillery_id": "distillery_id",
+	}
+	// searchRangeFilters {param}_min, _max (양끝 포함), _gt, _lt (양끝 제외) 범위
+	// 저장된 범위 {field}_min ~ {field}_max와 겹치면 통과한다 (검색어 "40도"와 같은 의미, 40~43% 술은 abv_max=41에도 걸린다)
+	searchRangeFilters = map[string]string{
+		"abv": "abv",
+		"age": "age",
+	}
+)
+
+// Similar 저장된 벡터로 비슷한 술 (/alcohols/12/similar?type=flavor|identity|origin|spec|hybrid&exclude_same_distillery=true)
+// type은 벡터 타입이므로 술 종류 필터는 filter={"eq":{"type":...}}로 건다, 나머지 필터와 다양화 파라미터는 /search와 같다
+func (h *SearchHandler) Similar(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "id must be an integer",
+		})
+	}
+	query, err := parseSimilarQuery(c, h.searcher.Diversity())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.searcher.Similar(c.Request().Context(), id, query)
+	if errors.Is(err, service.ErrAlcoholNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrUnknownVectorType) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":        err.Error(),
+			"vector_types": append(h.searcher.VectorTypes(), service.SimilarHybrid),
+		})
+	}
+	if errors.Is(err, service.ErrInvalidDiversity) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// parseSimilarQuery type, exclude_same_distillery, offset, limit, 필터, 다양화 파라미터
+func parseSimilarQuery(c echo.Context, defaults service.Diversity) (service.SimilarQuery, error) {
+	query := service.SimilarQuery{VectorType: c.QueryParam("type")}
+	filters, err := parseSearchFilters(c, "type")
+	if err != nil {
+		return query, err
+	}
+	query.Filters = filters
+	if query.Offset, query.Limit, err = parsePage(c); err != nil {
+		return query, err
+	}
+	if raw := c.QueryParam("exclude_same_distillery"); raw != "" {
+		exclude, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errors.New("exclude_same_distillery must be true or false")
+		}
+		query.ExcludeSameDistillery = exclude
+	}
+	if query.Diversity, err = parseDiversity(c, defaults); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+// parseSearchQuery keyword, offset, limit, parse, rerank, 필터 쿼리 파라미터 (keyword 외에는 생략 가능)
+// parse=false면 검색어에서 조건을 뽑지 않고 그대로 임베딩한다
+// rerank=true/false는 재순위 단계 기본값(SEARCH_RERANK)을 요청마다 바꾼다
+// mmr, lambda, max_per_distillery, max_per_category_group은 다양화 기본값(SEARCH_MMR...)을 덮어쓴다
+func parseSearchQuery(c echo.Context, defaults service.Diversity) (service.SearchQuery, error) {
+	query := service.SearchQuery{Keyword: c.QueryParam("keyword")}
+	filters, err := parseSearchFilters(c)
+	if err != nil {
+		return query, err
+	}
+	query.Filters = filters
+	if query.Offset, query.Limit, err = parsePage(c); err != nil {
+		return query, err
+	}
+	if raw := c.QueryParam("parse"); raw != "" {
+		parse, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errors.New("parse must be true or false")
+		}
+		query.Literal = !parse
+	}
+	if raw := c.QueryParam("rerank"); raw != "" {
+		rerank, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errors.New("rerank must be true or false")
+		}
+		query.Rerank = &rerank
+	}
+	diversity, err := parseDiversity(c, defaults)
+	if err != nil {
+		return query, err
+	}
+	query.Diversity = diversity
+	return query, nil
+}
+
+// parsePage offset, limit (없으면 0, 서비스 기본값을 쓴다)
+func parsePage(c echo.Context) (offset, limit int, err error) {
+	if raw := c.QueryParam("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		if offset > service.MaxSearchOffset {
+			return 0, 0, errors.New("offset must be at most " + strconv.Itoa(service.MaxSearchOffset))
+		}
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > service.MaxSearchLimit {
+			return 0, 0, errors.New("limit must be between 1 and " + strconv.Itoa(service.MaxSearchLimit))
+		}
+	}
+	return offset, limit, nil
+}
+
+// searchDiversityCaps 그룹 제한 쿼리 파라미터 → 페이로드 필드
+var searchDiversityCaps = map[string]string{
+	"max_per_distillery":     "distillery_id",
+	"max_per_category_group": "categoryGroup",
+}
+
+// parseDiversity mmr, lambda, max_per_* 파라미터를 기본값 위에 덮어쓴다 (하나도 없으면 nil: 기본값 그대로)
+func parseDiversity(c echo.Context, defaults service.Diversity) (*service.Diversity, error) {
+	changed := false
+	if raw := c.QueryParam("mmr"); raw != "" {
+		mmr, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("mmr must be true or false")
+		}
+		defaults.MMR, changed = mmr, true
+	}
+	if raw := c.QueryParam("lambda"); raw != "" {
+		lambda, err := strconv.ParseFloat(raw, 64)
+		if err != nil || lambda < 0 || lambda > 1 {
+			return nil, errors.New("lambda must be a number between 0 and 1")
+		}
+		defaults.Lambda, changed = lambda, true
+	}
+	for _, param := range sortedKeys(searchDiversityCaps) {
+		raw := c.QueryParam(param)
+		if raw == "" {
+			continue
+		}
+		max, err := strconv.Atoi(raw)
+		if err != nil || max < 0 {
+			return nil, errors.New(param + " must be a non-negative integer")
+		}
+		defaults.MaxPerGroup[searchDiversityCaps[param]], changed = max, true
+	}
+	if !changed {
+		return nil, nil
+	}
+	return &defaults, nil
+}
+
+// parseSearchFilters 필터 쿼리 파라미터 → 필터 (파라미터 이름순이라 같은 요청은 같은 쿼리)
+//   - type=a,b → in, type=!a → not eq, type=!a,b → not in
+//   - abv_min/abv_max (양끝 포함), abv_gt/abv_lt (양끝 제외)
+//   - filter={JSON 조건식} (repository.ParseFilter), 나머지 파라미터와 AND
+//
+// reserved 파라미터는 다른 뜻으로 쓰는 엔드포인트용으로 필터에서 뺀다 (/alcohols/:id/similar의 type)
+func parseSearchFilters(c echo.Context, reserved ...string) ([]repository.Filter, error) {
+	filters := make([]repository.Filter, 0)
+	for _, param := range sortedKeys(searchTextFilters) {
+		if slices.Contains(reserved, param) {
+			continue
+		}
+		if raw := c.QueryParam(param); raw != "" {
+			f, err := parseValueFilter(searchTextFilters[param], raw, func(v string) (interface{}, error) { return v, nil })
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, f)
+		}
+	}
+	for _, param := range sortedKeys(searchIDFilters) {
+		raw := c.QueryParam(param)
+		if raw == "" {
+			continue
+		}
+		f, err := parseValueFilter(searchIDFilters[param], raw, func(v string) (interface{}, error) {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, errors.New(param + " must be an integer")
+			}
+			return id, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	for _, param := range sortedKeys(searchRangeFilters) {
+		f, ok, err := parseRangeFilter(c, param, searchRangeFilters[param])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filters = append(filters, repository.OverlapFilters(f)...)
+		}
+	}
+	if raw := c.QueryParam("filter"); raw != "" {
+		f, err := repository.ParseFilter([]byte(raw))
+		if err != nil {
+			return nil, errors.New("invalid filter: " + err.Error())
+		}
+		filters = append(filters, f)
+	}
+	for _, f := range filters {
+		if err := f.Validate(); err != nil {
+			return nil, errors.New("invalid filter: " + err.Error())
+		}
+	}
+	return filters, nil
+}
+
+// parseValueFilter "a" → eq, "a,b" → in, 앞에 !가 붙으면 부정
+func parseValueFilter(field, raw string, parse func(string) (interface{}, error)) (repository.Filter, error) {
+	negate := strings.HasPrefix(raw, "!")
+	parts := strings.Split(strings.TrimPrefix(raw, "!"), ",")
+	values := make([]interface{}, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		value, err := parse(part)
+		if err != nil {
+			return repository.Filter{}, err
+		}
+		values = append(values, value)
+	}
+
+	var f repository.Filter
+	switch len(values) {
+	case 0:
+		return repository.Filter{}, errors.New(field + " filter value is empty")
+	case 1:
+		f = repository.Eq(field, values[0])
+	default:
+		f = repository.In(field, values...)
+	}
+	if negate {
+		f = repository.Not(f)
+	}
+	return f, nil
+}
+
+// parseRangeFilter {param}_min, _max, _gt, _lt 파라미터 → 범위 (하나도 없으면 ok=false)
+func parseRangeFilter(c echo.Context, param, field string) (repository.Filter, bool, error) {
+	f := repository.Filter{Op: repository.FilterRange, Field: field}
+	for _, bound := range []struct {
+		suffix    string
+		upper     bool
+		exclusive bool
+	}{{"_min", false, false}, {"_gt", false, true}, {"_max", true, false}, {"_lt", true, true}} {
+		value, err := parseBound(c, param+bound.suffix)
+		if err != nil {
+			return f, false, err
+		}
+		if value == nil {
+			continue
+		}
+		if bound.upper {
+			if f.Max != nil {
+				return f, false, errors.New(param + "_max and " + param + "_lt cannot be combined")
+			}
+			f.Max, f.ExclusiveMax = value, bound.exclusive
+		} else {
+			if f.Min != nil {
+				return f, false, errors.New(param + "_min and " + param + "_gt cannot be combined")
+			}
+			f.Min, f.ExclusiveMin = value, bound.exclusive
+		}
+	}
+	if f.Min == nil && f.Max == nil {
+		return f, false, nil
+	}
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		return f, false, errors.New(param + " lower bound must be <= upper bound")
+	}
+	return f, true, nil
+}
+
+// parseBound 범위 경계 파라미터 (없으면 nil)
+func parseBound(c echo.Context, param string) (*float64, error) {
+	raw := c.QueryParam(param)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, errors.New(param + " must be a number")
+	}
+	return &value, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
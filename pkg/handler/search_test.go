@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+	"github.com/Whale0928/embedding-worker/pkg/service"
+)
+
+// fakeEmbedder 검색어와 상관없이 같은 벡터
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) Embed(ctx context.Context, texts []string) ([]embedder.Embedding, error) {
+	embeddings := make([]embedder.Embedding, len(texts))
+	for i := range texts {
+		embeddings[i] = embedder.Embedding{Dense: []float32{1, 0, 0, 0}, Sparse: map[string]float32{}}
+	}
+	return embeddings, nil
+}
+
+// newTestServer 술 1~4 (flavor 벡터가 검색 벡터와 가까운 순서), 필드마다 가중치 1, 1·2는 증류소 7, 3·4는 증류소 8
+// 3은 40~46% 범위로 저장된 술
+func newTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+	s := repository.Schema{Name: "whisky", Vectors: []repository.VectorField{{Name: schema.VectorFlavor, Dimension: 4}}}
+	store := repository.NewMemoryStore()
+	docs := []repository.Document{
+		{ID: 1, Vectors: map[string][]float32{schema.VectorFlavor: {1, 0, 0, 0}}, Fields: map[string]interface{}{"type": "Single Malt", "abv": 40.0, "abv_min": 40.0, "abv_max": 40.0, "region_id": int64(10), "distillery_id": int64(7), "tastingTags": []interface{}{"스모키"}}},
+		{ID: 2, Vectors: map[string][]float32{schema.VectorFlavor: {1, 0.2, 0, 0}}, Fields: map[string]interface{}{"type": "Single Malt", "abv": 46.0, "abv_min": 46.0, "abv_max": 46.0, "region_id": int64(20), "distillery_id": int64(7), "tastingTags": []interface{}{"바닐라"}}},
+		{ID: 3, Vectors: map[string][]float32{schema.VectorFlavor: {1, 0.5, 0, 0}}, Fields: map[string]interface{}{"type": "Blend", "abv": 43.0, "abv_min": 40.0, "abv_max": 46.0, "region_id": int64(10), "distillery_id": int64(8), "tastingTags": []interface{}{"스모키", "바닐라"}}},
+		{ID: 4, Vectors: map[string][]float32{schema.VectorFlavor: {1, 1, 0, 0}}, Fields: map[string]interface{}{"type": "Blend", "abv": 50.0, "abv_min": 50.0, "abv_max": 50.0, "region_id": int64(20), "distillery_id": int64(8)}},
+	}
+	if err := store.Upsert(context.Background(), s, docs); err != nil {
+		t.Fatal(err)
+	}
+	collections, err := repository.NewCollections(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	searcher := service.NewSearcher(fakeEmbedder{}, store, collections, service.SearchOptions{Collection: s.Name, Fusion: repository.FusionRRF})
+
+	e := echo.New()
+	NewSearchHandler(searcher).Register(e)
+	return e
+}
+
+func get(t *testing.T, e *echo.Echo, path string, params url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path+"?"+params.Encode(), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func resultIDs(t *testing.T, rec *httptest.ResponseRecorder) []int64 {
+	t.Helper()
+	var result service.SearchResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("응답 파싱 실패: %v (%s)", err, rec.Body.String())
+	}
+	ids := make([]int64, len(result.Results))
+	for i, hit := range result.Results {
+		ids[i] = hit.ID
+	}
+	if result.Count != len(ids) {
+		t.Fatalf("count %d, results %d", result.Count, len(ids))
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearchHandlerHybridFilters(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		params url.Values
+		want   []int64
+	}{
+		{"필터 없음", url.Values{}, []int64{1, 2, 3, 4}},
+		{"type eq", url.Values{"type": {"Blend"}}, []int64{3, 4}},
+		{"type not", url.Values{"type": {"!Blend"}}, []int64{1, 2}},
+		{"region_id in", url.Values{"region_id": {"10,20"}}, []int64{1, 2, 3, 4}},
+		{"region_id not in", url.Values{"region_id": {"!20"}}, []int64{1, 3}},
+		{"tasting_tag 포함", url.Values{"tasting_tag": {"스모키"}}, []int64{1, 3}},
+		{"abv 양끝 포함", url.Values{"abv_min": {"43"}, "abv_max": {"46"}}, []int64{2, 3}},
+		{"abv 양끝 제외", url.Values{"abv_gt": {"43"}, "abv_lt": {"50"}}, []int64{2, 3}},
+		{"abv 범위 술과 겹침", url.Values{"abv_min": {"41"}, "abv_max": {"41"}}, []int64{3}},
+		{"filter JSON과 AND", url.Values{"type": {"Single Malt"}, "filter": {`{"range":{"abv":{"gte":45}}}`}}, []int64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Set("keyword", "스모키")
+			rec := get(t, e, "/search", tt.params)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			if got := resultIDs(t, rec); !equalIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchHandlerHybridPagination(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		offset, limit string
+		want          []int64
+	}{
+		{"", "", []int64{1, 2, 3, 4}},
+		{"", "2", []int64{1, 2}},
+		{"2", "2", []int64{3, 4}},
+		{"3", "5", []int64{4}},
+		{"4", "", []int64{}},
+	}
+	for _, tt := range tests {
+		params := url.Values{"keyword": {"스모키"}}
+		if tt.offset != "" {
+			params.Set("offset", tt.offset)
+		}
+		if tt.limit != "" {
+			params.Set("limit", tt.limit)
+		}
+		rec := get(t, e, "/search", params)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%v: status %d: %s", params, rec.Code, rec.Body.String())
+		}
+		if got := resultIDs(t, rec); !equalIDs(got, tt.want) {
+			t.Fatalf("%v: got %v, want %v", params, got, tt.want)
+		}
+	}
+}
+
+func TestSearchHandlerVector(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		params url.Values
+		want   []int64
+	}{
+		{"필드 이름", "/search/flavor", url.Values{}, []int64{1, 2, 3, 4}},
+		{"대소문자 무시", "/search/Flavor", url.Values{}, []int64{1, 2, 3, 4}},
+		{"필터", "/search/flavor", url.Values{"type": {"Blend"}, "abv_max": {"45"}}, []int64{3}},
+		{"페이지", "/search/flavor", url.Values{"offset": {"1"}, "limit": {"2"}}, []int64{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Set("keyword", "스모키")
+			rec := get(t, e, tt.path, tt.params)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			if got := resultIDs(t, rec); !equalIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(rec.Body.String(), `"vector_type":"flavor"`) {
+				t.Fatalf("vector_type 누락: %s", rec.Body.String())
+			}
+		})
+	}
+
+	// 없는 타입은 400과 함께 쓸 수 있는 타입을 알려준다
+	rec := get(t, e, "/search/taste", url.Values{"keyword": {"스모키"}})
+	var body struct {
+		Error       string   `json:"error"`
+		VectorTypes []string `json:"vector_types"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusBadRequest {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(body.Error, "taste") || len(body.VectorTypes) != 1 || body.VectorTypes[0] != schema.VectorFlavor {
+		t.Fatalf("body %+v", body)
+	}
+	if rec := get(t, e, "/search/flavor", url.Values{}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("검색어 없음 status %d", rec.Code)
+	}
+}
+
+func TestSearchHandlerHybridBadRequest(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		params url.Values
+		want   string
+	}{
+		{"검색어 없음", url.Values{}, "keyword is required"},
+		{"offset 음수", url.Values{"keyword": {"a"}, "offset": {"-1"}}, "offset must be a non-negative integer"},
+		{"limit 0", url.Values{"keyword": {"a"}, "limit": {"0"}}, "limit must be between 1 and 100"},
+		{"limit 초과", url.Values{"keyword": {"a"}, "limit": {"101"}}, "limit must be between 1 and 100"},
+		{"offset 초과", url.Values{"keyword": {"a"}, "offset": {"100000000"}}, "offset must be at most 1000"},
+		{"region_id 정수 아님", url.Values{"keyword": {"a"}, "region_id": {"x"}}, "region_id must be an integer"},
+		{"abv 숫자 아님", url.Values{"keyword": {"a"}, "abv_min": {"x"}}, "abv_min must be a number"},
+		{"abv 경계 중복", url.Values{"keyword": {"a"}, "abv_min": {"40"}, "abv_gt": {"41"}}, "abv_min and abv_gt cannot be combined"},
+		{"abv 경계 역전", url.Values{"keyword": {"a"}, "abv_min": {"50"}, "abv_max": {"40"}}, "abv lower bound must be <= upper bound"},
+		{"빈 필터 값", url.Values{"keyword": {"a"}, "type": {"!"}}, "type filter value is empty"},
+		{"parse 값 오류", url.Values{"keyword": {"a"}, "parse": {"maybe"}}, "parse must be true or false"},
+		{"lambda 범위", url.Values{"keyword": {"a"}, "lambda": {"2"}}, "lambda must be a number between 0 and 1"},
+		{"재순위 모델 없음", url.Values{"keyword": {"a"}, "rerank": {"true"}}, "reranker is not configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, e, "/search", tt.params)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("JSON 아님: %s", rec.Body.String())
+			}
+			if len(body) != 1 || body["error"] != tt.want {
+				t.Fatalf("body %v, want {error: %q}", body, tt.want)
+			}
+		})
+	}
+
+	// 잘못된 filter JSON은 파서 메시지를 덧붙인다
+	rec := get(t, e, "/search", url.Values{"keyword": {"a"}, "filter": {"{"}})
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusBadRequest || !strings.HasPrefix(body["error"], "invalid filter: ") {
+		t.Fatalf("filter 오류 %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSearchHandlerSimilar(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		params url.Values
+		want   []int64
+	}{
+		{"자기 자신 제외", "/alcohols/1/similar", url.Values{"type": {"flavor"}}, []int64{2, 3, 4}},
+		{"hybrid 기본", "/alcohols/3/similar", url.Values{}, []int64{2, 4, 1}},
+		{"같은 증류소 제외", "/alcohols/1/similar", url.Values{"exclude_same_distillery": {"true"}}, []int64{3, 4}},
+		{"필터와 페이지", "/alcohols/1/similar", url.Values{"region_id": {"20"}, "limit": {"1"}}, []int64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, e, tt.path, tt.params)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			if got := resultIDs(t, rec); !equalIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	failures := []struct {
+		name   string
+		path   string
+		params url.Values
+		status int
+	}{
+		{"없는 술", "/alcohols/99/similar", url.Values{}, http.StatusNotFound},
+		{"id 정수 아님", "/alcohols/x/similar", url.Values{}, http.StatusBadRequest},
+		{"없는 벡터 타입", "/alcohols/1/similar", url.Values{"type": {"taste"}}, http.StatusBadRequest},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, e, tt.path, tt.params)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == nil {
+				t.Fatalf("오류 응답 %s", rec.Body.String())
+			}
+		})
+	}
+}
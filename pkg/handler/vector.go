@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -8,40 +9,59 @@ import (
 	"github.com/Whale0928/embedding-worker/pkg/repository"
 )
 
+// listKeysLimit ListKeys 최대 반환 개수
+const listKeysLimit = 100
+
 // VectorHandler 벡터 관련 HTTP 핸들러
 type VectorHandler struct {
-	vespa *repository.VespaClient
+	store       repository.VectorStore
+	collections *repository.Collections
 }
 
 // NewVectorHandler 생성자
-func NewVectorHandler(vespa *repository.VespaClient) *VectorHandler {
+func NewVectorHandler(store repository.VectorStore, collections *repository.Collections) *VectorHandler {
 	return &VectorHandler{
-		vespa: vespa,
+		store:       store,
+		collections: collections,
 	}
 }
 
 // Register 라우터 등록
 func (h *VectorHandler) Register(e *echo.Echo) {
-	e.GET("/vector", h.ListKeys)
+	e.GET("/vector/:collection", h.ListKeys)
 }
 
 // ListKeys 저장된 문서 key 목록 조회
 func (h *VectorHandler) ListKeys(c echo.Context) error {
-	schema := repository.Schema{Namespace: "sample", DocType: "sample_vector"}
-	result, err := h.vespa.ListDocuments(schema, 100)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
+	schema, ok := h.collections.Get(c.Param("collection"))
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "unknown collection: " + c.Param("collection"),
 		})
 	}
 
-	keys := make([]string, 0)
-	for _, doc := range result.Documents {
+	keys := make([]int64, 0)
+	err := h.store.Visit(c.Request().Context(), schema, func(doc *repository.Document) error {
 		keys = append(keys, doc.ID)
+		if len(keys) >= listKeysLimit {
+			return repository.ErrStopVisit
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"keys":  keys,
-		"count": len(keys),
+		"collection": schema.Name,
+		"keys":       keys,
+		"count":      len(keys),
 	})
 }
@@ -0,0 +1,46 @@
+package hnsw
+
+import "container/heap"
+
+// candidate 탐색 후보 (내부 노드 번호 + 질의와의 거리)
+type candidate struct {
+	node     uint32
+	distance float32
+}
+
+// minHeap 거리 오름차순 (가까운 후보부터 확장)
+type minHeap []candidate
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].distance < h[j].distance }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// maxHeap 거리 내림차순 (결과 집합에서 가장 먼 후보를 제거)
+type maxHeap []candidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].distance > h[j].distance }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// sorted 결과 집합을 거리 오름차순 슬라이스로 변환 (힙은 비워진다)
+func (h *maxHeap) sorted() []candidate {
+	result := make([]candidate, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(candidate)
+	}
+	return result
+}
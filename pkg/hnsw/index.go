@@ -0,0 +1,492 @@
+// Package hnsw 프로세스 내 HNSW(Hierarchical Navigable Small World) 근사 최근접 이웃 인덱스
+package hnsw
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Config 인덱스 설정
+type Config struct {
+	Dimension int
+	Metric    Metric
+	// M 레벨별 최대 이웃 수 (레벨 0은 2M)
+	M int
+	// EfConstruction 삽입 시 탐색 후보 수
+	EfConstruction int
+	// EfSearch 검색 시 탐색 후보 수 (k보다 작으면 k 사용)
+	EfSearch int
+}
+
+// DefaultConfig Vespa 스키마 HNSW 설정과 동일한 기본값
+func DefaultConfig(dimension int, metric Metric) Config {
+	return Config{
+		Dimension:      dimension,
+		Metric:         metric,
+		M:              16,
+		EfConstruction: 200,
+		EfSearch:       100,
+	}
+}
+
+// tombstone 정리 기준: 갱신마다 새 노드를 넣고 이전 노드는 tombstone으로 남기므로,
+// 전체 노드 중 tombstone 비율이 CompactRatio를 넘으면 살아 있는 노드로 그래프를 다시 만든다
+const (
+	CompactRatio = 0.3
+	// compactMinTombstones 작은 인덱스는 tombstone이 이 수를 넘을 때까지 정리하지 않는다
+	compactMinTombstones = 64
+)
+
+// Result 검색 결과
+type Result struct {
+	ID       int64
+	Distance float32
+	Score    float64
+}
+
+// node 그래프 노드
+type node struct {
+	id     int64
+	vector []float32
+	level  int
+
+	mu      sync.RWMutex
+	friends [][]uint32
+
+	// deleted tombstone: 탐색 경로로는 사용하되 결과에서 제외
+	deleted atomic.Bool
+}
+
+// Index 동시 삽입/검색을 지원하는 HNSW 인덱스
+// 전역 락은 노드 목록과 진입점을, 노드별 락은 이웃 목록을 보호한다
+// 삽입은 노드 등록과 이웃 연결 사이에 전역 락을 놓으므로, 재구성은 compactMu로 진행 중인 삽입이 끝나길 기다린다
+type Index struct {
+	cfg Config
+	mL  float64
+
+	compactMu sync.RWMutex
+
+	mu       sync.RWMutex
+	nodes    []*node
+	ids      map[int64]uint32
+	entry    int64
+	maxLevel int
+	live     int
+}
+
+// New 빈 인덱스 생성
+func New(cfg Config) (*Index, error) {
+	if cfg.Dimension <= 0 {
+		return nil, fmt.Errorf("hnsw: dimension must be positive")
+	}
+	if _, err := ParseMetric(string(cfg.Metric)); err != nil {
+		return nil, err
+	}
+	if cfg.M < 2 {
+		return nil, fmt.Errorf("hnsw: M must be >= 2")
+	}
+	if cfg.EfConstruction < cfg.M {
+		cfg.EfConstruction = cfg.M
+	}
+	return &Index{
+		cfg:   cfg,
+		mL:    1 / math.Log(float64(cfg.M)),
+		ids:   make(map[int64]uint32),
+		entry: -1,
+	}, nil
+}
+
+// Config 인덱스 설정
+func (idx *Index) Config() Config {
+	return idx.cfg
+}
+
+// Len 삭제되지 않은 벡터 수
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.live
+}
+
+// Tombstones 그래프에 남아 있는 삭제/교체된 노드 수
+func (idx *Index) Tombstones() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.nodes) - idx.live
+}
+
+// Insert 벡터 추가, 같은 ID가 있으면 기존 노드를 tombstone 처리하고 새로 삽입
+// tombstone이 많이 쌓이면 삽입 후 그래프를 재구성한다
+func (idx *Index) Insert(id int64, vector []float32) error {
+	if err := idx.insertNode(id, vector); err != nil {
+		return err
+	}
+	idx.compactIfNeeded()
+	return nil
+}
+
+// insertNode 그래프에 노드 하나 삽입
+func (idx *Index) insertNode(id int64, vector []float32) error {
+	if len(vector) != idx.cfg.Dimension {
+		return fmt.Errorf("hnsw: dimension mismatch (want %d, got %d)", idx.cfg.Dimension, len(vector))
+	}
+	idx.compactMu.RLock()
+	defer idx.compactMu.RUnlock()
+
+	n := &node{
+		id:     id,
+		vector: idx.cfg.Metric.prepare(vector),
+		level:  idx.randomLevel(),
+	}
+	n.friends = make([][]uint32, n.level+1)
+
+	// 1. 노드 등록
+	idx.mu.Lock()
+	if old, ok := idx.ids[id]; ok {
+		if !idx.nodes[old].deleted.Swap(true) {
+			idx.live--
+		}
+	}
+	internal := uint32(len(idx.nodes))
+	idx.nodes = append(idx.nodes, n)
+	idx.ids[id] = internal
+	idx.live++
+	if idx.entry < 0 {
+		idx.entry = int64(internal)
+		idx.maxLevel = n.level
+		idx.mu.Unlock()
+		return nil
+	}
+	entry, maxLevel := uint32(idx.entry), idx.maxLevel
+	idx.mu.Unlock()
+
+	// 2. 상위 레벨부터 탐색하며 레벨별 이웃 연결
+	idx.mu.RLock()
+	entryPoints := []candidate{{node: entry, distance: idx.distance(n.vector, entry)}}
+	for level := maxLevel; level > n.level; level-- {
+		entryPoints = idx.searchLayer(n.vector, entryPoints, 1, level, nil)
+	}
+	for level := min(n.level, maxLevel); level >= 0; level-- {
+		candidates := idx.searchLayer(n.vector, entryPoints, idx.cfg.EfConstruction, level, nil)
+		neighbors := idx.selectNeighbors(candidates, idx.maxConnections(level))
+
+		friends := make([]uint32, 0, len(neighbors))
+		for _, c := range neighbors {
+			if c.node != internal {
+				friends = append(friends, c.node)
+			}
+		}
+		n.mu.Lock()
+		n.friends[level] = friends
+		n.mu.Unlock()
+
+		for _, friend := range friends {
+			idx.connect(friend, internal, level)
+		}
+		entryPoints = candidates
+	}
+	idx.mu.RUnlock()
+
+	// 3. 최상위 레벨이 높아지면 진입점 교체
+	if n.level > maxLevel {
+		idx.mu.Lock()
+		if n.level > idx.maxLevel {
+			idx.entry = int64(internal)
+			idx.maxLevel = n.level
+		}
+		idx.mu.Unlock()
+	}
+	return nil
+}
+
+// Delete tombstone 처리 (그래프 연결은 유지), 없는 ID면 false
+func (idx *Index) Delete(id int64) bool {
+	idx.mu.Lock()
+	internal, ok := idx.ids[id]
+	if !ok {
+		idx.mu.Unlock()
+		return false
+	}
+	delete(idx.ids, id)
+	if !idx.nodes[internal].deleted.Swap(true) {
+		idx.live--
+	}
+	idx.mu.Unlock()
+
+	idx.compactIfNeeded()
+	return true
+}
+
+// Compact tombstone을 버리고 살아 있는 노드만으로 그래프를 다시 만든다 (재구성 중 삽입/검색은 대기한다)
+func (idx *Index) Compact() {
+	idx.compactMu.Lock()
+	defer idx.compactMu.Unlock()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.rebuild()
+}
+
+// compactIfNeeded tombstone 비율이 CompactRatio를 넘으면 재구성
+func (idx *Index) compactIfNeeded() {
+	idx.mu.RLock()
+	needed := idx.shouldCompact()
+	idx.mu.RUnlock()
+	if !needed {
+		return
+	}
+
+	idx.compactMu.Lock()
+	defer idx.compactMu.Unlock()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	// 락을 기다리는 동안 다른 고루틴이 이미 정리했을 수 있다
+	if idx.shouldCompact() {
+		idx.rebuild()
+	}
+}
+
+func (idx *Index) shouldCompact() bool {
+	tombstones := len(idx.nodes) - idx.live
+	return tombstones >= compactMinTombstones && float64(tombstones) > CompactRatio*float64(len(idx.nodes))
+}
+
+// rebuild 살아 있는 노드를 원래 순서대로 새 그래프에 삽입 후 교체 (idx.mu 쓰기 락을 잡은 상태에서 호출)
+func (idx *Index) rebuild() {
+	fresh := &Index{
+		cfg:   idx.cfg,
+		mL:    idx.mL,
+		ids:   make(map[int64]uint32, idx.live),
+		entry: -1,
+	}
+	for _, n := range idx.nodes {
+		if n.deleted.Load() {
+			continue
+		}
+		// 저장된 벡터는 이미 차원이 맞으므로 실패하지 않는다
+		_ = fresh.insertNode(n.id, n.vector)
+	}
+	idx.nodes = fresh.nodes
+	idx.ids = fresh.ids
+	idx.entry = fresh.entry
+	idx.maxLevel = fresh.maxLevel
+	idx.live = fresh.live
+}
+
+// Vector 저장된 벡터 조회 (Cosine은 정규화된 벡터)
+func (idx *Index) Vector(id int64) ([]float32, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	internal, ok := idx.ids[id]
+	if !ok {
+		return nil, false
+	}
+	vector := make([]float32, len(idx.nodes[internal].vector))
+	copy(vector, idx.nodes[internal].vector)
+	return vector, true
+}
+
+// Search k개 근사 최근접 이웃 검색
+// filter가 false인 노드는 그래프 탐색에는 사용하되 결과에서 제외한다
+// 조건이 까다로워 그래프 탐색으로 k개를 채우지 못하면 조건을 만족하는 노드 전체를 정확히 비교한다
+func (idx *Index) Search(query []float32, k int, filter func(id int64) bool) ([]Result, error) {
+	if len(query) != idx.cfg.Dimension {
+		return nil, fmt.Errorf("hnsw: dimension mismatch (want %d, got %d)", idx.cfg.Dimension, len(query))
+	}
+	if k <= 0 {
+		return []Result{}, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if idx.entry < 0 {
+		return []Result{}, nil
+	}
+
+	q := idx.cfg.Metric.prepare(query)
+	entry := uint32(idx.entry)
+	entryPoints := []candidate{{node: entry, distance: idx.distance(q, entry)}}
+	for level := idx.maxLevel; level > 0; level-- {
+		entryPoints = idx.searchLayer(q, entryPoints, 1, level, nil)
+	}
+
+	accept := func(internal uint32) bool {
+		n := idx.nodes[internal]
+		if n.deleted.Load() {
+			return false
+		}
+		return filter == nil || filter(n.id)
+	}
+	candidates := idx.searchLayer(q, entryPoints, max(idx.cfg.EfSearch, k), 0, accept)
+	if filter != nil && len(candidates) < k {
+		candidates = idx.exactSearch(q, accept)
+	}
+
+	results := make([]Result, 0, min(k, len(candidates)))
+	for _, c := range candidates {
+		if len(results) == k {
+			break
+		}
+		results = append(results, Result{
+			ID:       idx.nodes[c.node].id,
+			Distance: c.distance,
+			Score:    idx.cfg.Metric.Score(c.distance),
+		})
+	}
+	return results, nil
+}
+
+// exactSearch accept된 노드 전체와 거리 계산 (거리 오름차순)
+func (idx *Index) exactSearch(q []float32, accept func(uint32) bool) []candidate {
+	candidates := make([]candidate, 0)
+	for internal := range idx.nodes {
+		if accept(uint32(internal)) {
+			candidates = append(candidates, candidate{node: uint32(internal), distance: idx.distance(q, uint32(internal))})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	return candidates
+}
+
+// searchLayer 한 레벨에서 ef개 후보를 찾는 best-first 탐색 (거리 오름차순 반환)
+// accept가 주어지면 결과에는 accept된 노드만 담고, 결과가 ef개 찰 때까지 탐색을 계속한다
+func (idx *Index) searchLayer(q []float32, entryPoints []candidate, ef, level int, accept func(uint32) bool) []candidate {
+	visited := make(map[uint32]struct{}, ef*4)
+	candidates := &minHeap{}
+	results := &maxHeap{}
+
+	for _, ep := range entryPoints {
+		visited[ep.node] = struct{}{}
+		heap.Push(candidates, ep)
+		if accept == nil || accept(ep.node) {
+			heap.Push(results, ep)
+		}
+	}
+	for results.Len() > ef {
+		heap.Pop(results)
+	}
+
+	for candidates.Len() > 0 {
+		current := heap.Pop(candidates).(candidate)
+		if results.Len() >= ef && current.distance > (*results)[0].distance {
+			break
+		}
+
+		for _, friend := range idx.friendsAt(current.node, level) {
+			if _, seen := visited[friend]; seen {
+				continue
+			}
+			visited[friend] = struct{}{}
+
+			d := idx.distance(q, friend)
+			if results.Len() < ef || d < (*results)[0].distance {
+				heap.Push(candidates, candidate{node: friend, distance: d})
+				if accept == nil || accept(friend) {
+					heap.Push(results, candidate{node: friend, distance: d})
+					if results.Len() > ef {
+						heap.Pop(results)
+					}
+				}
+			}
+		}
+	}
+	return results.sorted()
+}
+
+// selectNeighbors 이웃 선택 휴리스틱: 이미 선택된 이웃보다 질의에 더 가까운 후보만 채택하고,
+// 자리가 남으면 버린 후보로 채운다 (candidates는 거리 오름차순)
+func (idx *Index) selectNeighbors(candidates []candidate, m int) []candidate {
+	if len(candidates) <= m {
+		return candidates
+	}
+
+	selected := make([]candidate, 0, m)
+	pruned := make([]candidate, 0)
+	for _, c := range candidates {
+		if len(selected) == m {
+			break
+		}
+		good := true
+		for _, s := range selected {
+			if idx.distance(idx.nodes[c.node].vector, s.node) < c.distance {
+				good = false
+				break
+			}
+		}
+		if good {
+			selected = append(selected, c)
+		} else {
+			pruned = append(pruned, c)
+		}
+	}
+	for _, c := range pruned {
+		if len(selected) == m {
+			break
+		}
+		selected = append(selected, c)
+	}
+	return selected
+}
+
+// connect target 노드의 이웃 목록에 새 노드 추가, 최대 연결 수 초과 시 가지치기
+func (idx *Index) connect(target, newNode uint32, level int) {
+	t := idx.nodes[target]
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if level >= len(t.friends) {
+		return
+	}
+	friends := append(t.friends[level], newNode)
+	limit := idx.maxConnections(level)
+	if len(friends) > limit {
+		candidates := make([]candidate, 0, len(friends))
+		for _, f := range friends {
+			candidates = append(candidates, candidate{node: f, distance: idx.distance(t.vector, f)})
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+		kept := idx.selectNeighbors(candidates, limit)
+		friends = make([]uint32, 0, len(kept))
+		for _, c := range kept {
+			friends = append(friends, c.node)
+		}
+	}
+	t.friends[level] = friends
+}
+
+// friendsAt 노드의 레벨별 이웃 목록 복사본
+func (idx *Index) friendsAt(internal uint32, level int) []uint32 {
+	n := idx.nodes[internal]
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if level >= len(n.friends) {
+		return nil
+	}
+	friends := make([]uint32, len(n.friends[level]))
+	copy(friends, n.friends[level])
+	return friends
+}
+
+func (idx *Index) distance(q []float32, internal uint32) float32 {
+	return idx.cfg.Metric.distance(q, idx.nodes[internal].vector)
+}
+
+func (idx *Index) maxConnections(level int) int {
+	if level == 0 {
+		return idx.cfg.M * 2
+	}
+	return idx.cfg.M
+}
+
+// randomLevel 지수 분포로 노드 레벨 결정
+func (idx *Index) randomLevel() int {
+	return int(math.Floor(-math.Log(1-rand.Float64()) * idx.mL))
+}
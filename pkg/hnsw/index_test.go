@@ -0,0 +1,168 @@
+package hnsw
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+func randomVector(r *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = r.Float32()*2 - 1
+	}
+	return v
+}
+
+func newTestIndex(t *testing.T, dim int) *Index {
+	t.Helper()
+	idx, err := New(DefaultConfig(dim, Cosine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idx
+}
+
+func TestUpdatesCompactTombstones(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	idx := newTestIndex(t, 8)
+
+	const docs = 200
+	for round := 0; round < 10; round++ {
+		for id := int64(0); id < docs; id++ {
+			if err := idx.Insert(id, randomVector(r, 8)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if idx.Len() != docs {
+		t.Fatalf("Len = %d, want %d", idx.Len(), docs)
+	}
+	// 갱신 1,800번이 모두 남으면 노드가 2,000개, 정리되면 tombstone 비율이 기준 이하
+	idx.mu.RLock()
+	nodes := len(idx.nodes)
+	idx.mu.RUnlock()
+	if tombstones := idx.Tombstones(); float64(tombstones) > CompactRatio*float64(nodes) && tombstones >= compactMinTombstones {
+		t.Fatalf("tombstone %d / 노드 %d: 정리되지 않음", tombstones, nodes)
+	}
+	if nodes >= 2*docs {
+		t.Fatalf("노드 %d개: 갱신마다 계속 늘어남", nodes)
+	}
+
+	// 재구성 후에도 모든 문서를 자기 벡터로 찾는다
+	for id := int64(0); id < docs; id++ {
+		vector, ok := idx.Vector(id)
+		if !ok {
+			t.Fatalf("문서 %d 벡터 없음", id)
+		}
+		results, err := idx.Search(vector, 1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].ID != id {
+			t.Fatalf("문서 %d 검색 결과 %v", id, results)
+		}
+	}
+}
+
+func TestDeleteCompacts(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	idx := newTestIndex(t, 4)
+	for id := int64(0); id < 300; id++ {
+		if err := idx.Insert(id, randomVector(r, 4)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id := int64(0); id < 250; id++ {
+		idx.Delete(id)
+	}
+	if idx.Len() != 50 {
+		t.Fatalf("Len = %d", idx.Len())
+	}
+	if idx.Tombstones() >= compactMinTombstones {
+		t.Fatalf("tombstone %d개 남음", idx.Tombstones())
+	}
+	results, err := idx.Search(randomVector(r, 4), 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 50 {
+		t.Fatalf("결과 %d개, want 50", len(results))
+	}
+	for _, res := range results {
+		if res.ID < 250 {
+			t.Fatalf("삭제된 문서 %d 반환", res.ID)
+		}
+	}
+}
+
+func TestCompactSnapshotRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	idx := newTestIndex(t, 4)
+	for id := int64(0); id < 20; id++ {
+		idx.Insert(id, randomVector(r, 4))
+		idx.Insert(id, randomVector(r, 4))
+	}
+	idx.Compact()
+	if idx.Tombstones() != 0 || idx.Len() != 20 {
+		t.Fatalf("Compact 후 tombstone %d, Len %d", idx.Tombstones(), idx.Len())
+	}
+
+	var buf bytes.Buffer
+	if err := idx.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != 20 || loaded.Tombstones() != 0 {
+		t.Fatalf("복원 Len %d, tombstone %d", loaded.Len(), loaded.Tombstones())
+	}
+}
+
+// go test -race로 재구성과 동시 삽입/삭제/검색을 확인한다
+func TestConcurrentUpdatesDuringCompaction(t *testing.T) {
+	idx := newTestIndex(t, 8)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(seed uint64) {
+			defer wg.Done()
+			r := rand.New(rand.NewPCG(seed, seed))
+			for i := 0; i < 500; i++ {
+				id := int64(r.IntN(100))
+				switch r.IntN(4) {
+				case 0:
+					idx.Delete(id)
+				case 1:
+					if _, err := idx.Search(randomVector(r, 8), 5, nil); err != nil {
+						t.Error(err)
+					}
+				default:
+					if err := idx.Insert(id, randomVector(r, 8)); err != nil {
+						t.Error(err)
+					}
+				}
+			}
+		}(uint64(w + 10))
+	}
+	wg.Wait()
+
+	// 모든 살아 있는 문서가 자기 벡터로 검색된다
+	idx.mu.RLock()
+	ids := make([]int64, 0, len(idx.ids))
+	for id := range idx.ids {
+		ids = append(ids, id)
+	}
+	idx.mu.RUnlock()
+	for _, id := range ids {
+		vector, _ := idx.Vector(id)
+		results, err := idx.Search(vector, 1, func(candidate int64) bool { return candidate == id })
+		if err != nil || len(results) != 1 || results[0].ID != id {
+			t.Fatalf("문서 %d 검색 실패: %v %v", id, results, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package hnsw
+
+import (
+	"fmt"
+	"math"
+)
+
+// Metric 벡터 거리 함수
+type Metric string
+
+const (
+	// Cosine 코사인 거리 (1 - cos), 저장 시 벡터를 정규화한다
+	Cosine Metric = "cosine"
+	// Dot 내적 거리 (-dot)
+	Dot Metric = "dot"
+	// L2 유클리드 거리 제곱
+	L2 Metric = "l2"
+)
+
+// ParseMetric 문자열을 Metric으로 변환
+func ParseMetric(value string) (Metric, error) {
+	switch m := Metric(value); m {
+	case Cosine, Dot, L2:
+		return m, nil
+	default:
+		return "", fmt.Errorf("지원하지 않는 거리 함수: %q", value)
+	}
+}
+
+// distance 거리 계산 (작을수록 가깝다)
+func (m Metric) distance(a, b []float32) float32 {
+	switch m {
+	case L2:
+		var sum float32
+		for i := range a {
+			d := a[i] - b[i]
+			sum += d * d
+		}
+		return sum
+	case Dot:
+		return -dot(a, b)
+	default:
+		// 정규화된 벡터의 코사인 거리
+		return 1 - dot(a, b)
+	}
+}
+
+// Score 거리를 유사도 점수로 변환 (클수록 가깝다)
+// Cosine: 코사인 유사도, Dot: 내적, L2: 1/(1+거리)
+func (m Metric) Score(distance float32) float64 {
+	switch m {
+	case L2:
+		return 1 / (1 + math.Sqrt(float64(distance)))
+	case Dot:
+		return float64(-distance)
+	default:
+		return float64(1 - distance)
+	}
+}
+
+// prepare 저장/검색용 벡터 복사 (Cosine은 정규화)
+func (m Metric) prepare(vector []float32) []float32 {
+	prepared := make([]float32, len(vector))
+	copy(prepared, vector)
+	if m != Cosine {
+		return prepared
+	}
+
+	norm := float32(math.Sqrt(float64(dot(prepared, prepared))))
+	if norm == 0 {
+		return prepared
+	}
+	for i := range prepared {
+		prepared[i] /= norm
+	}
+	return prepared
+}
+
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
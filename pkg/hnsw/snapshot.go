@@ -0,0 +1,169 @@
+package hnsw
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// snapshotVersion 스냅샷 포맷 버전
+const snapshotVersion = 1
+
+type snapshot struct {
+	Version  int
+	Config   Config
+	Entry    int64
+	MaxLevel int
+	Nodes    []snapshotNode
+}
+
+type snapshotNode struct {
+	ID      int64
+	Vector  []float32
+	Level   int
+	Friends [][]uint32
+	Deleted bool
+}
+
+// Save 인덱스 전체를 gob으로 기록 (저장 중 삽입은 대기한다)
+func (idx *Index) Save(w io.Writer) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	snap := snapshot{
+		Version:  snapshotVersion,
+		Config:   idx.cfg,
+		Entry:    idx.entry,
+		MaxLevel: idx.maxLevel,
+		Nodes:    make([]snapshotNode, len(idx.nodes)),
+	}
+	for i, n := range idx.nodes {
+		n.mu.RLock()
+		snap.Nodes[i] = snapshotNode{
+			ID:      n.id,
+			Vector:  n.vector,
+			Level:   n.level,
+			Friends: n.friends,
+			Deleted: n.deleted.Load(),
+		}
+		n.mu.RUnlock()
+	}
+
+	if err := gob.NewEncoder(w).Encode(&snap); err != nil {
+		return fmt.Errorf("hnsw: 스냅샷 인코딩 실패: %w", err)
+	}
+	return nil
+}
+
+// Load 스냅샷에서 인덱스 복원
+func Load(r io.Reader) (*Index, error) {
+	var snap snapshot
+	if err := gob.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("hnsw: 스냅샷 디코딩 실패: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("hnsw: 지원하지 않는 스냅샷 버전 %d", snap.Version)
+	}
+	if err := snap.validate(); err != nil {
+		return nil, err
+	}
+
+	idx, err := New(snap.Config)
+	if err != nil {
+		return nil, err
+	}
+	idx.entry = snap.Entry
+	idx.maxLevel = snap.MaxLevel
+	idx.nodes = make([]*node, len(snap.Nodes))
+	for i, sn := range snap.Nodes {
+		n := &node{id: sn.ID, vector: sn.Vector, level: sn.Level, friends: sn.Friends}
+		if len(n.friends) < n.level+1 {
+			n.friends = append(n.friends, make([][]uint32, n.level+1-len(n.friends))...)
+		}
+		n.deleted.Store(sn.Deleted)
+		idx.nodes[i] = n
+		if !sn.Deleted {
+			idx.ids[sn.ID] = uint32(i)
+			idx.live++
+		}
+	}
+	return idx, nil
+}
+
+// validate 그래프 참조와 벡터 차원 검사 (손상된 스냅샷이 검색 중 panic을 내지 않도록)
+func (snap *snapshot) validate() error {
+	count := int64(len(snap.Nodes))
+	if count == 0 {
+		if snap.Entry != -1 {
+			return fmt.Errorf("hnsw: 손상된 스냅샷: 빈 그래프의 진입점 %d", snap.Entry)
+		}
+		return nil
+	}
+	if snap.Entry < 0 || snap.Entry >= count {
+		return fmt.Errorf("hnsw: 손상된 스냅샷: 진입점 %d (노드 %d개)", snap.Entry, count)
+	}
+	if level := snap.Nodes[snap.Entry].Level; snap.MaxLevel != level {
+		return fmt.Errorf("hnsw: 손상된 스냅샷: 최대 레벨 %d, 진입점 레벨 %d", snap.MaxLevel, level)
+	}
+	for i, sn := range snap.Nodes {
+		if len(sn.Vector) != snap.Config.Dimension {
+			return fmt.Errorf("hnsw: 손상된 스냅샷: 노드 %d 차원 %d (want %d)", i, len(sn.Vector), snap.Config.Dimension)
+		}
+		if sn.Level < 0 || len(sn.Friends) > sn.Level+1 {
+			return fmt.Errorf("hnsw: 손상된 스냅샷: 노드 %d 레벨 %d, 이웃 레벨 %d개", i, sn.Level, len(sn.Friends))
+		}
+		for level, friends := range sn.Friends {
+			for _, f := range friends {
+				// 이웃도 그 레벨에 있어야 탐색 중 friends[level] 접근이 안전하다
+				if int64(f) >= count || snap.Nodes[f].Level < level {
+					return fmt.Errorf("hnsw: 손상된 스냅샷: 노드 %d 레벨 %d의 이웃 %d", i, level, f)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SaveFile 임시 파일에 기록 후 rename (원자적 교체)
+func (idx *Index) SaveFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("hnsw: 디렉토리 생성 실패: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("hnsw: 스냅샷 파일 생성 실패: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	if err := idx.Save(w); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("hnsw: 스냅샷 기록 실패: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("hnsw: 스냅샷 파일 닫기 실패: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// LoadFile 스냅샷 파일에서 인덱스 복원
+func LoadFile(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return Load(bufio.NewReader(f))
+}
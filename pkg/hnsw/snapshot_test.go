@@ -0,0 +1,76 @@
+package hnsw
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestLoadRejectsCorruptSnapshot(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	idx := newTestIndex(t, 4)
+	for id := int64(0); id < 30; id++ {
+		idx.Insert(id, randomVector(r, 4))
+	}
+	var saved bytes.Buffer
+	if err := idx.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+
+	// decode 매번 새로 디코딩한 스냅샷 (테스트끼리 슬라이스를 공유하지 않도록)
+	decode := func(t *testing.T) snapshot {
+		t.Helper()
+		var snap snapshot
+		if err := gob.NewDecoder(bytes.NewReader(saved.Bytes())).Decode(&snap); err != nil {
+			t.Fatal(err)
+		}
+		return snap
+	}
+	// linked 레벨 0 이웃이 있는 노드
+	linked := func(snap *snapshot) *snapshotNode {
+		for i := range snap.Nodes {
+			if len(snap.Nodes[i].Friends[0]) > 0 {
+				return &snap.Nodes[i]
+			}
+		}
+		t.Fatal("이웃 있는 노드 없음")
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(snap *snapshot)
+		want    string
+	}{
+		{"진입점 범위 밖", func(snap *snapshot) { snap.Entry = int64(len(snap.Nodes)) }, "진입점"},
+		{"빈 그래프 진입점", func(snap *snapshot) { snap.Nodes = nil; snap.Entry = 0 }, "진입점"},
+		{"최대 레벨 불일치", func(snap *snapshot) { snap.MaxLevel = snap.Nodes[snap.Entry].Level + 1 }, "최대 레벨"},
+		{"이웃 범위 밖", func(snap *snapshot) { n := linked(snap); n.Friends[0][0] = uint32(len(snap.Nodes)) }, "이웃"},
+		{"벡터 차원", func(snap *snapshot) { snap.Nodes[3].Vector = snap.Nodes[3].Vector[:3] }, "차원"},
+		{"레벨보다 많은 이웃 목록", func(snap *snapshot) {
+			n := &snap.Nodes[0]
+			n.Friends = append(n.Friends, make([][]uint32, n.Level+1)...)
+		}, "레벨"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := decode(t)
+			tt.corrupt(&snap)
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(&snap); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(&buf); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// 손상되지 않은 스냅샷은 그대로 복원
+	loaded, err := Load(bytes.NewReader(saved.Bytes()))
+	if err != nil || loaded.Len() != 30 {
+		t.Fatalf("복원 err %v", err)
+	}
+}
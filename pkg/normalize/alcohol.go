@@ -0,0 +1,70 @@
+package normalize
+
+import (
+	"errors"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// Specs Alcohol의 정규화된 스펙 (값이 없거나 해석 불가면 nil)
+type Specs struct {
+	ABV    *Range
+	Age    *Range
+	Volume *Range
+	// NAS 숙성 연수 미표기
+	NAS bool
+	// Issues 해석할 수 없었던 값
+	Issues []*UnparseableError
+}
+
+// Alcohol ABV, Age, Volume 정규화
+func Alcohol(a *domain.Alcohol) Specs {
+	var specs Specs
+	specs.ABV = specs.parse(a.ABV, ParseABV)
+	specs.Age = specs.parse(a.Age, ParseAge)
+	specs.Volume = specs.parse(a.Volume, ParseVolume)
+	return specs
+}
+
+func (specs *Specs) parse(raw *string, parse func(string) (Range, error)) *Range {
+	if raw == nil {
+		return nil
+	}
+	r, err := parse(*raw)
+	if err != nil {
+		var unparseable *UnparseableError
+		switch {
+		case errors.As(err, &unparseable):
+			specs.Issues = append(specs.Issues, unparseable)
+		case errors.Is(err, ErrNoAgeStatement):
+			specs.NAS = true
+		}
+		return nil
+	}
+	return &r
+}
+
+// MetadataKeys Metadata가 만들 수 있는 전체 키 (값이 없으면 키가 빠진다)
+var MetadataKeys = []string{"abv", "abv_min", "abv_max", "age", "age_min", "age_max", "nas", "volume_ml"}
+
+// Metadata 필터 메타데이터 필드 (abv/age는 중간값, 범위는 *_min/*_max)
+func (specs Specs) Metadata() map[string]interface{} {
+	metadata := make(map[string]interface{})
+	if specs.ABV != nil {
+		metadata["abv"] = specs.ABV.Mid
+		metadata["abv_min"] = specs.ABV.Min
+		metadata["abv_max"] = specs.ABV.Max
+	}
+	if specs.Age != nil {
+		metadata["age"] = int(specs.Age.Mid)
+		metadata["age_min"] = int(specs.Age.Min)
+		metadata["age_max"] = int(specs.Age.Max)
+	}
+	if specs.NAS {
+		metadata["nas"] = true
+	}
+	if specs.Volume != nil {
+		metadata["volume_ml"] = specs.Volume.Mid
+	}
+	return metadata
+}
@@ -0,0 +1,139 @@
+// Package normalize 도수/숙성 연수/용량 문자열을 단위가 있는 숫자 범위로 정규화
+package normalize
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 단위
+const (
+	UnitPercent = "%"
+	UnitYear    = "year"
+	UnitML      = "ml"
+)
+
+var (
+	// ErrEmpty 값 없음 (nil 또는 빈 문자열)
+	ErrEmpty = errors.New("empty value")
+	// ErrNoAgeStatement 숙성 연수 미표기 (NAS)
+	ErrNoAgeStatement = errors.New("no age statement")
+)
+
+// UnparseableError 해석할 수 없는 값
+type UnparseableError struct {
+	Field string `json:"field"`
+	Raw   string `json:"raw"`
+}
+
+func (e *UnparseableError) Error() string {
+	return fmt.Sprintf("%s: 해석할 수 없는 값 %q", e.Field, e.Raw)
+}
+
+// Range 정규화된 숫자 범위 (단일 값이면 Min == Max == Mid)
+type Range struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mid  float64 `json:"mid"`
+	Unit string  `json:"unit"`
+	Raw  string  `json:"raw"`
+}
+
+// Contains 값이 범위 안에 있는지 (양끝 포함)
+func (r Range) Contains(value float64) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+// numberRange "40", "35~40", "35 - 40", "35–40"
+var numberRange = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(?:[~\-–]\s*(\d+(?:\.\d+)?))?$`)
+
+// parseRange 단위를 제거한 문자열을 범위로 변환 (min > max면 뒤집는다)
+func parseRange(field, raw, body, unit string, scale float64) (Range, error) {
+	matches := numberRange.FindStringSubmatch(strings.TrimSpace(body))
+	if matches == nil {
+		return Range{}, &UnparseableError{Field: field, Raw: raw}
+	}
+
+	low, _ := strconv.ParseFloat(matches[1], 64)
+	high := low
+	if matches[2] != "" {
+		high, _ = strconv.ParseFloat(matches[2], 64)
+	}
+	if low > high {
+		low, high = high, low
+	}
+	low, high = low*scale, high*scale
+
+	return Range{Min: low, Max: high, Mid: (low + high) / 2, Unit: unit, Raw: raw}, nil
+}
+
+// trimSuffixes 대소문자 무시하고 더 이상 맞는 접미사가 없을 때까지 제거 ("43%ABV" → "43", 제거했으면 true)
+func trimSuffixes(s string, suffixes ...string) (string, bool) {
+	trimmed := false
+	for {
+		lower := strings.ToLower(s)
+		matched := false
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(lower, suffix) {
+				s = strings.TrimSpace(s[:len(s)-len(suffix)])
+				trimmed, matched = true, true
+				break
+			}
+		}
+		if !matched {
+			return s, trimmed
+		}
+	}
+}
+
+// ParseABV 도수: "40", "40%", "40도", "40.5 %vol", "43%ABV", "35~40" → % 범위
+func ParseABV(raw string) (Range, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return Range{}, ErrEmpty
+	}
+	s, _ = trimSuffixes(s, "abv", "vol", "%", "도")
+	r, err := parseRange("abv", raw, s, UnitPercent, 1)
+	if err != nil {
+		return Range{}, err
+	}
+	if r.Max > 100 {
+		return Range{}, &UnparseableError{Field: "abv", Raw: raw}
+	}
+	return r, nil
+}
+
+// ParseAge 숙성 연수: "12", "12년", "12yo", "12 years", "12-15년" → year 범위, "NAS"는 ErrNoAgeStatement
+func ParseAge(raw string) (Range, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return Range{}, ErrEmpty
+	}
+	switch strings.ToLower(s) {
+	case "nas", "n/a", "na", "-", "no age statement", "무연산":
+		return Range{}, ErrNoAgeStatement
+	}
+	s, _ = trimSuffixes(s, "years old", "year old", "years", "year", "yrs", "yr", "y.o.", "yo", "y", "년산", "년")
+	return parseRange("age", raw, s, UnitYear, 1)
+}
+
+// ParseVolume 용량: "700", "700ml", "70cl", "1L", "1.75 l" → ml 범위 (단위 없으면 ml)
+func ParseVolume(raw string) (Range, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return Range{}, ErrEmpty
+	}
+
+	scale := 1.0
+	if body, ok := trimSuffixes(s, "ml", "㎖"); ok {
+		s = body
+	} else if body, ok := trimSuffixes(s, "cl"); ok {
+		s, scale = body, 10
+	} else if body, ok := trimSuffixes(s, "l", "ℓ", "리터"); ok {
+		s, scale = body, 1000
+	}
+	return parseRange("volume", raw, s, UnitML, scale)
+}
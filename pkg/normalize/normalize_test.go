@@ -0,0 +1,197 @@
+package normalize
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+type rangeCase struct {
+	raw      string
+	min, max float64
+}
+
+func checkRanges(t *testing.T, parse func(string) (Range, error), unit string, tests []rangeCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			r, err := parse(tt.raw)
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if r.Min != tt.min || r.Max != tt.max || r.Mid != (tt.min+tt.max)/2 {
+				t.Errorf("got %v..%v (mid %v), want %v..%v", r.Min, r.Max, r.Mid, tt.min, tt.max)
+			}
+			if r.Unit != unit || r.Raw != tt.raw {
+				t.Errorf("unit %q raw %q", r.Unit, r.Raw)
+			}
+		})
+	}
+}
+
+func TestParseABV(t *testing.T) {
+	checkRanges(t, ParseABV, UnitPercent, []rangeCase{
+		{"40", 40, 40},
+		{"40%", 40, 40},
+		{"40도", 40, 40},
+		{"40.5 %vol", 40.5, 40.5},
+		{"40.5% vol", 40.5, 40.5},
+		{"46 vol", 46, 46},
+		{"43%ABV", 43, 43},
+		{"43 % abv", 43, 43},
+		{"43ABV", 43, 43},
+		{"35~40", 35, 40},
+		{"35 - 40%", 35, 40},
+		{"40–35도", 35, 40},
+	})
+}
+
+func TestParseAge(t *testing.T) {
+	checkRanges(t, ParseAge, UnitYear, []rangeCase{
+		{"12", 12, 12},
+		{"12년", 12, 12},
+		{"12년산", 12, 12},
+		{"12yo", 12, 12},
+		{"12 y.o.", 12, 12},
+		{"12y", 12, 12},
+		{"12 yr", 12, 12},
+		{"12 yrs", 12, 12},
+		{"12 year", 12, 12},
+		{"12 Years", 12, 12},
+		{"18 years old", 18, 18},
+		{"1 year old", 1, 1},
+		{"12-15년", 12, 15},
+		{"12~15 years", 12, 15},
+	})
+}
+
+func TestParseVolume(t *testing.T) {
+	checkRanges(t, ParseVolume, UnitML, []rangeCase{
+		{"700", 700, 700},
+		{"700ml", 700, 700},
+		{"700 ML", 700, 700},
+		{"500㎖", 500, 500},
+		{"70cl", 700, 700},
+		{"1L", 1000, 1000},
+		{"1.75 l", 1750, 1750},
+		{"1ℓ", 1000, 1000},
+		{"1리터", 1000, 1000},
+		{"50~70cl", 500, 700},
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (Range, error)
+		raw   string
+		want  error
+	}{
+		{"빈 도수", ParseABV, "  ", ErrEmpty},
+		{"빈 숙성", ParseAge, "", ErrEmpty},
+		{"빈 용량", ParseVolume, "", ErrEmpty},
+		{"NAS", ParseAge, "NAS", ErrNoAgeStatement},
+		{"nas 소문자", ParseAge, "nas", ErrNoAgeStatement},
+		{"No Age Statement", ParseAge, "No Age Statement", ErrNoAgeStatement},
+		{"무연산", ParseAge, "무연산", ErrNoAgeStatement},
+		{"대시", ParseAge, "-", ErrNoAgeStatement},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.parse(tt.raw); !errors.Is(err, tt.want) {
+				t.Errorf("err %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnparseable(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (Range, error)
+		field string
+		raw   string
+	}{
+		{"100% 초과", ParseABV, "abv", "150%"},
+		{"범위 끝이 100% 초과", ParseABV, "abv", "40~120"},
+		{"글자", ParseABV, "abv", "cask strength"},
+		{"숙성 글자", ParseAge, "age", "old"},
+		{"용량 단위 모름", ParseVolume, "volume", "1 bottle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parse(tt.raw)
+			var unparseable *UnparseableError
+			if !errors.As(err, &unparseable) {
+				t.Fatalf("err %v, want UnparseableError", err)
+			}
+			if unparseable.Field != tt.field || unparseable.Raw != tt.raw {
+				t.Errorf("got %+v", unparseable)
+			}
+		})
+	}
+}
+
+func TestAlcoholMetadata(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		alcohol domain.Alcohol
+		want    map[string]interface{}
+		issues  int
+	}{
+		{
+			name:    "단일 값",
+			alcohol: domain.Alcohol{ABV: str("43%ABV"), Age: str("12년"), Volume: str("70cl")},
+			want: map[string]interface{}{
+				"abv": 43.0, "abv_min": 43.0, "abv_max": 43.0,
+				"age": 12, "age_min": 12, "age_max": 12,
+				"volume_ml": 700.0,
+			},
+		},
+		{
+			name:    "범위는 중간값과 양끝",
+			alcohol: domain.Alcohol{ABV: str("40~43"), Age: str("12-15년")},
+			want: map[string]interface{}{
+				"abv": 41.5, "abv_min": 40.0, "abv_max": 43.0,
+				"age": 13, "age_min": 12, "age_max": 15,
+			},
+		},
+		{
+			name:    "NAS",
+			alcohol: domain.Alcohol{ABV: str("46"), Age: str("NAS")},
+			want:    map[string]interface{}{"abv": 46.0, "abv_min": 46.0, "abv_max": 46.0, "nas": true},
+		},
+		{
+			name:    "해석 불가는 키가 빠지고 Issues에 남는다",
+			alcohol: domain.Alcohol{ABV: str("150%"), Volume: str("1 bottle")},
+			want:    map[string]interface{}{},
+			issues:  2,
+		},
+		{
+			name:    "값 없음",
+			alcohol: domain.Alcohol{},
+			want:    map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs := Alcohol(&tt.alcohol)
+			got := specs.Metadata()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			if len(specs.Issues) != tt.issues {
+				t.Errorf("issues %v, want %d", specs.Issues, tt.issues)
+			}
+			for key := range got {
+				if !slices.Contains(MetadataKeys, key) {
+					t.Errorf("%q not in MetadataKeys", key)
+				}
+			}
+		})
+	}
+}
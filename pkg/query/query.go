@@ -0,0 +1,318 @@
+// Package query 자유 텍스트 검색어에서 스펙 조건(도수/숙성 연수/용량), 캐스크, 지역/증류소를 뽑아낸다
+// 뽑아낸 조건은 필터/부스트로, 나머지 텍스트는 임베딩으로 넘긴다
+package query
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/Whale0928/embedding-worker/pkg/normalize"
+)
+
+// Bound 검색어에서 뽑은 숫자 조건 (nil이면 경계 없음)
+type Bound struct {
+	Min          *float64 `json:"min,omitempty"`
+	Max          *float64 `json:"max,omitempty"`
+	ExclusiveMin bool     `json:"exclusive_min,omitempty"`
+	ExclusiveMax bool     `json:"exclusive_max,omitempty"`
+	Unit         string   `json:"unit"`
+	// Raw 검색어에서 인식한 부분 ("40도 이상")
+	Raw string `json:"raw"`
+}
+
+// Match 사전에서 찾은 지역/증류소
+type Match struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Raw 검색어에 나온 표기
+	Raw string `json:"raw"`
+}
+
+// Parsed 검색어 해석 결과
+type Parsed struct {
+	Original string `json:"original"`
+	// Text 인식한 조건을 뺀 나머지 (임베딩 입력), 남는 게 없으면 Original
+	Text   string `json:"text"`
+	ABV    *Bound `json:"abv,omitempty"`
+	Age    *Bound `json:"age,omitempty"`
+	Volume *Bound `json:"volume,omitempty"`
+	// Casks 캐스크 키워드 (텍스트에 남겨 두고 flavor 부스트에 쓴다)
+	Casks        []string `json:"casks,omitempty"`
+	Regions      []Match  `json:"regions,omitempty"`
+	Distilleries []Match  `json:"distilleries,omitempty"`
+}
+
+// Empty 인식한 조건이 없는지
+func (p *Parsed) Empty() bool {
+	return p.ABV == nil && p.Age == nil && p.Volume == nil &&
+		len(p.Casks) == 0 && len(p.Regions) == 0 && len(p.Distilleries) == 0
+}
+
+// Entry 사전 항목 (한글/영문 이름)
+type Entry struct {
+	ID    int64
+	Names []string
+}
+
+// Dictionary DB에서 읽은 지역/증류소 이름 사전
+type Dictionary struct {
+	Regions      []Entry
+	Distilleries []Entry
+}
+
+// 숫자 또는 숫자 범위 ("40", "40.5", "35~40")
+const numberPattern = `\d+(?:\.\d+)?(?:\s*[~\-–]\s*\d+(?:\.\d+)?)?`
+
+// 스펙 패턴: 앞쪽 영어 비교어, 숫자+단위, 뒤쪽 한국어 비교어
+var (
+	abvPattern    = regexp.MustCompile(`(?i)(?:\b(under|below|over|above|less than|more than)\s+)?(` + numberPattern + `)\s*(?:도|%|％)(?:\s*(?:vol|abv)\b)?(?:\s*(이상|이하|미만|초과))?`)
+	agePattern    = regexp.MustCompile(`(?i)(?:\b(under|below|over|above|less than|more than)\s+)?(` + numberPattern + `)\s*(?:년산|년|yo\b|y\.o\.|years?\s+old\b|years?\b|yrs?\b)(?:\s*(이상|이하|미만|초과))?`)
+	volumePattern = regexp.MustCompile(`(?i)(` + numberPattern + `)\s*(?:ml|㎖|cl|l\b|ℓ|리터)`)
+)
+
+// maxAge 숙성 연수로 볼 최대값 ("2012년" 같은 연도는 무시)
+const maxAge = 100
+
+// caskKeywords 캐스크 종류 → 검색어 표기
+var caskKeywords = map[string][]string{
+	"sherry":   {"셰리", "쉐리", "sherry", "올로로소", "oloroso", "px", "페드로 히메네스", "pedro ximenez"},
+	"bourbon":  {"버번", "bourbon"},
+	"port":     {"포트", "port"},
+	"wine":     {"와인", "wine"},
+	"rum":      {"럼캐스크", "럼 캐스크", "rum"},
+	"madeira":  {"마데이라", "madeira"},
+	"mizunara": {"미즈나라", "mizunara"},
+	"cognac":   {"코냑", "cognac"},
+}
+
+// term 사전 이름 하나
+type term struct {
+	name  string
+	kind  string
+	id    int64
+	label string
+}
+
+const (
+	kindRegion     = "region"
+	kindDistillery = "distillery"
+	kindCask       = "cask"
+)
+
+// Parser 검색어 해석기 (사전은 실행 중 교체 가능)
+type Parser struct {
+	terms atomic.Pointer[[]term]
+}
+
+// NewParser 생성자
+func NewParser(dict *Dictionary) *Parser {
+	p := &Parser{}
+	p.SetDictionary(dict)
+	return p
+}
+
+// SetDictionary 지역/증류소 사전 교체 (이후 Parse부터 반영)
+// 짧은 이름(한글 2자, 영문 3자 미만)은 다른 단어와 겹치기 쉬워 제외한다
+func (p *Parser) SetDictionary(dict *Dictionary) {
+	terms := make([]term, 0)
+	add := func(kind string, entries []Entry) {
+		for _, e := range entries {
+			label := ""
+			for _, name := range e.Names {
+				name = strings.TrimSpace(name)
+				if !usableName(name) {
+					continue
+				}
+				if label == "" {
+					label = name
+				}
+				terms = append(terms, term{name: lowerASCII(name), kind: kind, id: e.ID, label: label})
+			}
+		}
+	}
+	if dict != nil {
+		add(kindRegion, dict.Regions)
+		add(kindDistillery, dict.Distilleries)
+	}
+	for cask, names := range caskKeywords {
+		for _, name := range names {
+			terms = append(terms, term{name: name, kind: kindCask, label: cask})
+		}
+	}
+	// 긴 이름부터 찾아야 "글렌피딕"보다 "글렌피딕 증류소"가, 부분 이름보다 전체 이름이 먼저 잡힌다
+	sort.SliceStable(terms, func(i, j int) bool {
+		if len(terms[i].name) != len(terms[j].name) {
+			return len(terms[i].name) > len(terms[j].name)
+		}
+		return terms[i].name < terms[j].name
+	})
+	p.terms.Store(&terms)
+}
+
+func usableName(name string) bool {
+	if isASCII(name) {
+		return len(name) >= 3
+	}
+	return utf8.RuneCountInString(name) >= 2
+}
+
+// Parse 검색어 해석
+func (p *Parser) Parse(text string) *Parsed {
+	parsed := &Parsed{Original: text}
+	// 인식한 부분은 공백으로 지워 다음 패턴이 다시 잡지 않게 한다 (바이트 위치 유지)
+	rest := []byte(text)
+
+	parsed.ABV = matchBound(abvPattern, rest, normalize.ParseABV, func(r normalize.Range) bool { return r.Max <= 100 })
+	parsed.Age = matchBound(agePattern, rest, normalize.ParseAge, func(r normalize.Range) bool { return r.Max <= maxAge })
+	parsed.Volume = matchVolume(rest)
+
+	lower := lowerASCII(string(rest))
+	seen := make(map[string]bool)
+	for _, t := range *p.terms.Load() {
+		for from := 0; ; {
+			i := strings.Index(lower[from:], t.name)
+			if i < 0 {
+				break
+			}
+			start, end := from+i, from+i+len(t.name)
+			from = end
+			if isASCII(t.name) && !wordBoundary(lower, start, end) {
+				continue
+			}
+			if blank(rest[start:end]) {
+				continue
+			}
+
+			key := t.kind + "\x00" + t.label
+			switch t.kind {
+			case kindCask:
+				// 캐스크는 의미 검색에도 필요하므로 텍스트에 남긴다
+				if !seen[key] {
+					parsed.Casks = append(parsed.Casks, t.label)
+				}
+				lower = lower[:start] + strings.Repeat(" ", end-start) + lower[end:]
+			case kindRegion, kindDistillery:
+				match := Match{ID: t.id, Name: t.label, Raw: text[start:end]}
+				if !seen[key] {
+					if t.kind == kindRegion {
+						parsed.Regions = append(parsed.Regions, match)
+					} else {
+						parsed.Distilleries = append(parsed.Distilleries, match)
+					}
+				}
+				erase(rest, start, end)
+				lower = lower[:start] + strings.Repeat(" ", end-start) + lower[end:]
+			}
+			seen[key] = true
+		}
+	}
+	sort.Strings(parsed.Casks)
+
+	parsed.Text = strings.Join(strings.Fields(string(rest)), " ")
+	if parsed.Text == "" {
+		parsed.Text = strings.TrimSpace(text)
+	}
+	return parsed
+}
+
+// matchBound 첫 번째로 인식한 숫자 조건 (valid가 false면 무시)
+func matchBound(pattern *regexp.Regexp, rest []byte, parse func(string) (normalize.Range, error), valid func(normalize.Range) bool) *Bound {
+	for _, m := range pattern.FindAllSubmatchIndex(rest, -1) {
+		raw := string(rest[m[0]:m[1]])
+		number := string(rest[m[4]:m[5]])
+		r, err := parse(number)
+		if err != nil || !valid(r) {
+			continue
+		}
+		comparison := ""
+		if m[2] >= 0 {
+			comparison = strings.ToLower(string(rest[m[2]:m[3]]))
+		} else if m[6] >= 0 {
+			comparison = string(rest[m[6]:m[7]])
+		}
+		bound := toBound(r, comparison)
+		bound.Raw = strings.TrimSpace(raw)
+		erase(rest, m[0], m[1])
+		return bound
+	}
+	return nil
+}
+
+// matchVolume 첫 번째 용량 ("700ml", "1L")
+func matchVolume(rest []byte) *Bound {
+	for _, m := range volumePattern.FindAllSubmatchIndex(rest, -1) {
+		raw := string(rest[m[0]:m[1]])
+		r, err := normalize.ParseVolume(strings.Join(strings.Fields(raw), ""))
+		if err != nil {
+			continue
+		}
+		bound := toBound(r, "")
+		bound.Raw = strings.TrimSpace(raw)
+		erase(rest, m[0], m[1])
+		return bound
+	}
+	return nil
+}
+
+// toBound 정규화된 범위 + 비교어 → 조건
+// 이상/over는 하한, 이하/under는 상한 (미만/초과/under/below/over/above는 경계 제외)
+func toBound(r normalize.Range, comparison string) *Bound {
+	min, max := r.Min, r.Max
+	bound := &Bound{Unit: r.Unit}
+	switch comparison {
+	case "이상":
+		bound.Min = &min
+	case "초과", "over", "above", "more than":
+		bound.Min, bound.ExclusiveMin = &max, true
+	case "이하":
+		bound.Max = &max
+	case "미만", "under", "below", "less than":
+		bound.Max, bound.ExclusiveMax = &min, true
+	default:
+		bound.Min, bound.Max = &min, &max
+	}
+	return bound
+}
+
+// erase 인식한 부분을 공백으로 (바이트 길이 유지)
+func erase(b []byte, start, end int) {
+	for i := start; i < end; i++ {
+		b[i] = ' '
+	}
+}
+
+func blank(b []byte) bool {
+	return len(strings.TrimSpace(string(b))) == 0
+}
+
+// lowerASCII ASCII만 소문자로 (바이트 위치가 바뀌지 않는다)
+func lowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// wordBoundary 영문 이름 앞뒤가 영숫자가 아닌지 ("port"가 "support"에 잡히지 않게)
+func wordBoundary(s string, start, end int) bool {
+	return (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end]))
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
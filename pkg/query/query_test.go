@@ -0,0 +1,210 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// boundString 비교하기 쉬운 표기: "40", "35..40", ">=40", ">40", "<=12", "<12" (+ 단위)
+func boundString(b *Bound) string {
+	if b == nil {
+		return ""
+	}
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	var s string
+	switch {
+	case b.Min != nil && b.Max != nil && *b.Min == *b.Max:
+		s = f(*b.Min)
+	case b.Min != nil && b.Max != nil:
+		s = f(*b.Min) + ".." + f(*b.Max)
+	case b.Min != nil && b.ExclusiveMin:
+		s = ">" + f(*b.Min)
+	case b.Min != nil:
+		s = ">=" + f(*b.Min)
+	case b.ExclusiveMax:
+		s = "<" + f(*b.Max)
+	default:
+		s = "<=" + f(*b.Max)
+	}
+	return s + " " + b.Unit
+}
+
+func TestParseSpecs(t *testing.T) {
+	p := NewParser(nil)
+	tests := []struct {
+		name   string
+		text   string
+		abv    string
+		age    string
+		volume string
+		rest   string
+	}{
+		{"도 이상", "40도 이상 위스키", ">=40 %", "", "", "위스키"},
+		{"% 미만", "스모키 45% 미만", "<45 %", "", "", "스모키"},
+		{"영어 under", "peated under 45%", "<45 %", "", "", "peated"},
+		{"영어 over + abv", "over 50 % abv peated", ">50 %", "", "", "peated"},
+		{"초과", "50도 초과 캐스크 스트렝스", ">50 %", "", "", "캐스크 스트렝스"},
+		{"이하", "43도 이하", "<=43 %", "", "", "43도 이하"},
+		{"소수점", "46.5도 싱글몰트", "46.5 %", "", "", "싱글몰트"},
+		{"범위", "35~40도 블렌디드", "35..40 %", "", "", "블렌디드"},
+		{"100% 넘는 도수는 무시", "150% 과일향", "", "", "", "150% 과일향"},
+		{"년산", "12년산 셰리", "", "12 year", "", "셰리"},
+		{"years old", "18 years old speyside", "", "18 year", "", "speyside"},
+		{"yo", "Glenlivet 12yo", "", "12 year", "", "Glenlivet"},
+		{"년 이상", "15년 이상 싱글몰트", "", ">=15 year", "", "싱글몰트"},
+		{"more than", "more than 10 years smoky", "", ">10 year", "", "smoky"},
+		{"숙성 범위", "12-15년 버번", "", "12..15 year", "", "버번"},
+		{"연도는 숙성 연수가 아님", "2012년 빈티지", "", "", "", "2012년 빈티지"},
+		{"ml", "700ml 스모키", "", "", "700 ml", "스모키"},
+		{"cl", "70cl 스모키", "", "", "700 ml", "스모키"},
+		{"L", "1L 스모키", "", "", "1000 ml", "스모키"},
+		{"공백 있는 l", "peat 1.75 l", "", "", "1750 ml", "peat"},
+		{"㎖", "500㎖ 하이볼", "", "", "500 ml", "하이볼"},
+		{"도수, 숙성, 용량 함께", "12년 40도 이상 700ml 셰리", ">=40 %", "12 year", "700 ml", "셰리"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := p.Parse(tt.text)
+			if got := boundString(parsed.ABV); got != tt.abv {
+				t.Errorf("ABV %q, want %q", got, tt.abv)
+			}
+			if got := boundString(parsed.Age); got != tt.age {
+				t.Errorf("Age %q, want %q", got, tt.age)
+			}
+			if got := boundString(parsed.Volume); got != tt.volume {
+				t.Errorf("Volume %q, want %q", got, tt.volume)
+			}
+			if parsed.Text != tt.rest {
+				t.Errorf("Text %q, want %q", parsed.Text, tt.rest)
+			}
+			if parsed.Original != tt.text {
+				t.Errorf("Original %q", parsed.Original)
+			}
+		})
+	}
+}
+
+func TestParseCasks(t *testing.T) {
+	p := NewParser(nil)
+	tests := []struct {
+		name  string
+		text  string
+		casks []string
+	}{
+		{"한국어 셰리", "셰리 캐스크 위스키", []string{"sherry"}},
+		{"다른 표기 쉐리", "쉐리 피니시", []string{"sherry"}},
+		{"영어 대소문자 무시", "Sherry Cask", []string{"sherry"}},
+		{"PX는 셰리", "PX 피니시", []string{"sherry"}},
+		{"여러 단어 이름", "pedro ximenez finish", []string{"sherry"}},
+		{"같은 캐스크는 한 번", "버번 오크 bourbon barrel", []string{"bourbon"}},
+		{"여러 캐스크는 정렬", "셰리 버번 더블 캐스크", []string{"bourbon", "sherry"}},
+		{"한국어 럼 캐스크", "럼 캐스크 피니시", []string{"rum"}},
+		{"미즈나라", "미즈나라 오크", []string{"mizunara"}},
+		{"영어는 단어 경계", "support passport", nil},
+		{"영어 단어 경계 (문장부호)", "port, wine", []string{"port", "wine"}},
+		{"캐스크 없음", "스모키 피트", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := p.Parse(tt.text)
+			if strings.Join(parsed.Casks, ",") != strings.Join(tt.casks, ",") {
+				t.Fatalf("Casks %v, want %v", parsed.Casks, tt.casks)
+			}
+			// 캐스크는 의미 검색에도 쓰도록 텍스트에 남긴다
+			if parsed.Text != strings.Join(strings.Fields(tt.text), " ") {
+				t.Fatalf("Text %q", parsed.Text)
+			}
+		})
+	}
+}
+
+func TestParseDictionary(t *testing.T) {
+	p := NewParser(&Dictionary{
+		Regions: []Entry{
+			{ID: 1, Names: []string{"하이랜드", "Highland"}},
+			{ID: 2, Names: []string{"아일라", "Islay"}},
+			// 짧은 이름은 다른 단어와 겹치기 쉬워 제외
+			{ID: 3, Names: []string{"섬", "Ay"}},
+		},
+		Distilleries: []Entry{
+			{ID: 10, Names: []string{"하이랜드 파크", "Highland Park"}},
+			{ID: 11, Names: []string{"글렌피딕", "Glenfiddich"}},
+			{ID: 12, Names: []string{"", "Ardbeg"}},
+		},
+	})
+
+	type match struct {
+		id  int64
+		raw string
+	}
+	tests := []struct {
+		name         string
+		text         string
+		regions      []match
+		distilleries []match
+		rest         string
+	}{
+		{"지역", "하이랜드 싱글몰트", []match{{1, "하이랜드"}}, nil, "싱글몰트"},
+		{"영문 지역 대소문자 무시", "ISLAY peat", []match{{2, "ISLAY"}}, nil, "peat"},
+		{"긴 증류소 이름이 지역보다 먼저", "하이랜드 파크 18년 바닐라", nil, []match{{10, "하이랜드 파크"}}, "바닐라"},
+		{"영문 겹치는 이름", "Highland Park honey", nil, []match{{10, "Highland Park"}}, "honey"},
+		{"증류소와 다른 위치의 지역", "highland park vs highland malts", []match{{1, "highland"}}, []match{{10, "highland park"}}, "vs malts"},
+		{"같은 항목의 한영 표기는 한 번", "아일라 Islay 스모키", []match{{2, "아일라"}}, nil, "스모키"},
+		{"지역과 증류소", "아일라 글렌피딕", []match{{2, "아일라"}}, []match{{11, "글렌피딕"}}, "아일라 글렌피딕"},
+		{"한글 이름 없는 증류소의 표시 이름은 영문", "ardbeg 10", nil, []match{{12, "ardbeg"}}, "10"},
+		{"영문 단어 경계", "highlands", nil, nil, "highlands"},
+		{"짧은 이름 제외", "섬 ay", nil, nil, "섬 ay"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := p.Parse(tt.text)
+			check := func(kind string, got []Match, want []match) {
+				if len(got) != len(want) {
+					t.Fatalf("%s %+v, want %+v", kind, got, want)
+				}
+				for i := range want {
+					if got[i].ID != want[i].id || got[i].Raw != want[i].raw {
+						t.Fatalf("%s %+v, want %+v", kind, got, want)
+					}
+				}
+			}
+			check("Regions", parsed.Regions, tt.regions)
+			check("Distilleries", parsed.Distilleries, tt.distilleries)
+			if parsed.Text != tt.rest {
+				t.Fatalf("Text %q, want %q", parsed.Text, tt.rest)
+			}
+		})
+	}
+
+	if parsed := p.Parse("ardbeg"); len(parsed.Distilleries) != 1 || parsed.Distilleries[0].Name != "Ardbeg" {
+		t.Fatalf("표시 이름 %+v", parsed.Distilleries)
+	}
+}
+
+// 인식한 조건을 빼면 남는 게 없는 검색어는 원문을 그대로 임베딩한다
+func TestParseTermsOnlyFallsBackToOriginal(t *testing.T) {
+	p := NewParser(&Dictionary{
+		Regions:      []Entry{{ID: 1, Names: []string{"스페이사이드", "Speyside"}}},
+		Distilleries: []Entry{{ID: 10, Names: []string{"글렌피딕", "Glenfiddich"}}},
+	})
+	tests := []string{
+		"40도 이상",
+		"  12년  ",
+		"Speyside 700ml",
+		"글렌피딕 12년 40%",
+	}
+	for _, text := range tests {
+		parsed := p.Parse(text)
+		if parsed.Empty() {
+			t.Fatalf("%q: 조건 미인식", text)
+		}
+		if parsed.Text != strings.TrimSpace(text) {
+			t.Fatalf("%q: Text %q, want 원문", text, parsed.Text)
+		}
+	}
+
+	if parsed := p.Parse("스모키한 위스키"); !parsed.Empty() || parsed.Text != "스모키한 위스키" {
+		t.Fatalf("조건 없는 검색어 %+v", parsed)
+	}
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// AlcoholRepository 술 조회 (Region, Distillery, TastingTags 즉시 로딩)
+type AlcoholRepository struct {
+	db *gorm.DB
+	// untracked last_modify_at 컬럼이 없다고 경고한 테이블 (테이블마다 한 번만 경고)
+	untracked sync.Map
+}
+
+func NewAlcoholRepository(db *gorm.DB) *AlcoholRepository {
+	return &AlcoholRepository{db: db}
+}
+
+// withRelations 모든 연관관계 Preload
+func (r *AlcoholRepository) withRelations(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Preload("Region").
+		Preload("Distillery").
+		Preload("TastingTags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tasting_tags.id")
+		})
+}
+
+// FindByIDRange startID ~ endID (양끝 포함) 범위 조회, ID 오름차순
+func (r *AlcoholRepository) FindByIDRange(ctx context.Context, startID, endID int64) ([]domain.Alcohol, error) {
+	var alcohols []domain.Alcohol
+	err := r.withRelations(ctx).
+		Where("id BETWEEN ? AND ?", startID, endID).
+		Order("id").
+		Find(&alcohols).Error
+	if err != nil {
+		return nil, fmt.Errorf("술 범위 조회 실패 (%d~%d): %w", startID, endID, err)
+	}
+	return alcohols, nil
+}
+
+// Iterate keyset 페이지 조회: afterID보다 큰 ID를 오름차순으로 limit개
+// 다음 페이지는 마지막 원소의 ID를 afterID로 넘긴다
+func (r *AlcoholRepository) Iterate(ctx context.Context, afterID int64, limit int) ([]domain.Alcohol, error) {
+	var alcohols []domain.Alcohol
+	err := r.withRelations(ctx).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&alcohols).Error
+	if err != nil {
+		return nil, fmt.Errorf("술 페이지 조회 실패 (after=%d): %w", afterID, err)
+	}
+	return alcohols, nil
+}
+
+// FindByIDs ID 목록 조회, ID 오름차순 (없는 ID는 결과에서 빠진다)
+func (r *AlcoholRepository) FindByIDs(ctx context.Context, ids []int64) ([]domain.Alcohol, error) {
+	if len(ids) == 0 {
+		return []domain.Alcohol{}, nil
+	}
+
+	var alcohols []domain.Alcohol
+	err := r.withRelations(ctx).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&alcohols).Error
+	if err != nil {
+		return nil, fmt.Errorf("술 ID 목록 조회 실패: %w", err)
+	}
+	return alcohols, nil
+}
+
+// Count 전체 술 개수
+func (r *AlcoholRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Alcohol{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("술 개수 조회 실패: %w", err)
+	}
+	return count, nil
+}
+
+// MaxModifiedAt alcohols와 연관 테이블(regions, distilleries, tasting_tags)의 최신 last_modify_at
+// 값이 하나도 없으면 zero time (last_modify_at 컬럼이 없는 연관 테이블은 건너뛴다)
+func (r *AlcoholRepository) MaxModifiedAt(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	for _, leg := range r.changeLegs(ctx) {
+		var values []time.Time
+		err := r.db.WithContext(ctx).Table(leg.table).
+			Where("last_modify_at IS NOT NULL").
+			Order("last_modify_at DESC").
+			Limit(1).
+			Pluck("last_modify_at", &values).Error
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s 최신 수정 시각 조회 실패: %w", leg.table, err)
+		}
+		if len(values) > 0 && values[0].After(latest) {
+			latest = values[0]
+		}
+	}
+	return latest, nil
+}
+
+// changeLeg 변경 감지 대상 테이블과, 그 테이블이 바뀌었을 때 영향받는 술 ID를 고르는 쿼리
+type changeLeg struct {
+	table string
+	query string
+}
+
+// changeTrackedLegs 임베딩 텍스트에 영향을 주는 테이블 (alcohols 외에는 last_modify_at이 스키마에 없을 수 있다)
+var changeTrackedLegs = []changeLeg{
+	{"alcohols", "SELECT id FROM alcohols WHERE last_modify_at >= ? AND last_modify_at <= ?"},
+	{"regions", "SELECT a.id FROM alcohols a JOIN regions r ON r.id = a.region_id\nWHERE r.last_modify_at >= ? AND r.last_modify_at <= ?"},
+	{"distilleries", "SELECT a.id FROM alcohols a JOIN distilleries d ON d.id = a.distillery_id\nWHERE d.last_modify_at >= ? AND d.last_modify_at <= ?"},
+	{"tasting_tags", "SELECT att.alcohol_id FROM alcohol_tasting_tags att JOIN tasting_tags t ON t.id = att.tasting_tag_id\nWHERE t.last_modify_at >= ? AND t.last_modify_at <= ?"},
+}
+
+// changeLegs last_modify_at 컬럼이 있는 테이블만 (alcohols는 항상 포함해 컬럼이 없으면 조회 오류로 드러낸다)
+// 컬럼이 없는 연관 테이블은 경고를 남기고 건너뛴다: 그 테이블만 바뀐 술은 증분 동기화가 잡지 못한다
+func (r *AlcoholRepository) changeLegs(ctx context.Context) []changeLeg {
+	migrator := r.db.WithContext(ctx).Migrator()
+	legs := make([]changeLeg, 0, len(changeTrackedLegs))
+	for _, leg := range changeTrackedLegs {
+		if leg.table != "alcohols" && !migrator.HasColumn(leg.table, "last_modify_at") {
+			if _, warned := r.untracked.LoadOrStore(leg.table, true); !warned {
+				fmt.Printf("[WARN] %s에 last_modify_at 컬럼이 없어 변경 감지에서 제외\n", leg.table)
+			}
+			continue
+		}
+		legs = append(legs, leg)
+	}
+	return legs
+}
+
+// FindChangedIDs from <= last_modify_at <= until 구간에 바뀐 술 ID (오름차순)
+// 술 자체뿐 아니라 연결된 region, distillery, tasting tag가 바뀐 술도 포함한다
+// (alcohol_tasting_tags 연결만 바뀐 경우나 last_modify_at 컬럼이 없는 연관 테이블은 잡지 못한다)
+// 늦게 커밋된 행을 놓치지 않도록 호출자는 from을 마지막 기준 시각보다 앞당겨 겹치게 조회한다
+func (r *AlcoholRepository) FindChangedIDs(ctx context.Context, from, until time.Time) ([]int64, error) {
+	legs := r.changeLegs(ctx)
+	queries := make([]string, len(legs))
+	args := make([]interface{}, 0, 2*len(legs))
+	for i, leg := range legs {
+		queries[i] = leg.query
+		args = append(args, from, until)
+	}
+
+	var ids []int64
+	err := r.db.WithContext(ctx).Raw(strings.Join(queries, "\nUNION\n")+"\nORDER BY 1", args...).Scan(&ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("변경된 술 조회 실패 (%s~%s): %w", from.Format(time.RFC3339), until.Format(time.RFC3339), err)
+	}
+	return ids, nil
+}
+
+// FindIDsByRelation region, distillery, tasting tag에 연결된 술 ID (오름차순)
+func (r *AlcoholRepository) FindIDsByRelation(ctx context.Context, entityType string, id int64) ([]int64, error) {
+	var table, column, key string
+	switch entityType {
+	case domain.OutboxRegion:
+		table, column, key = "alcohols", "id", "region_id"
+	case domain.OutboxDistillery:
+		table, column, key = "alcohols", "id", "distillery_id"
+	case domain.OutboxTastingTag:
+		table, column, key = "alcohol_tasting_tags", "alcohol_id", "tasting_tag_id"
+	default:
+		return nil, fmt.Errorf("연관 엔티티가 아님: %s", entityType)
+	}
+
+	var ids []int64
+	err := r.db.WithContext(ctx).Table(table).
+		Where(key+" = ?", id).
+		Order(column).
+		Pluck(column, &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("%s %d에 연결된 술 조회 실패: %w", entityType, id, err)
+	}
+	return ids, nil
+}
+
+// ExistingIDs ids 중 DB에 남아 있는 ID (오름차순)
+func (r *AlcoholRepository) ExistingIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	existing := make([]int64, 0, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	err := r.db.WithContext(ctx).Model(&domain.Alcohol{}).
+		Where("id IN ?", ids).
+		Order("id").
+		Pluck("id", &existing).Error
+	if err != nil {
+		return nil, fmt.Errorf("술 존재 여부 조회 실패: %w", err)
+	}
+	return existing, nil
+}
+
+// Regions 전체 지역 (ID 오름차순), 검색어 해석 사전용
+func (r *AlcoholRepository) Regions(ctx context.Context) ([]domain.Region, error) {
+	var regions []domain.Region
+	if err := r.db.WithContext(ctx).Order("id").Find(&regions).Error; err != nil {
+		return nil, fmt.Errorf("지역 목록 조회 실패: %w", err)
+	}
+	return regions, nil
+}
+
+// Distilleries 전체 증류소 (ID 오름차순), 검색어 해석 사전용
+func (r *AlcoholRepository) Distilleries(ctx context.Context) ([]domain.Distillery, error) {
+	var distilleries []domain.Distillery
+	if err := r.db.WithContext(ctx).Order("id").Find(&distilleries).Error; err != nil {
+		return nil, fmt.Errorf("증류소 목록 조회 실패: %w", err)
+	}
+	return distilleries, nil
+}
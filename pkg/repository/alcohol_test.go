@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// newTestDB 테스트마다 독립된 in-memory SQLite (운영 MySQL 스키마를 AutoMigrate로 흉내 낸다)
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&domain.Region{}, &domain.Distillery{}, &domain.TastingTag{}, &domain.Alcohol{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// seedAlcohols ID 1~5 술: 홀수는 지역/증류소 있음, 태그는 역순으로 연결해 정렬을 확인한다
+func seedAlcohols(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	modified := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	region := domain.Region{ID: 10, KorName: "스페이사이드", EngName: "Speyside"}
+	distillery := domain.Distillery{ID: 20, KorName: "글렌피딕", EngName: "Glenfiddich"}
+	tags := []domain.TastingTag{
+		{ID: 31, KorName: "바닐라", EngName: "Vanilla"},
+		{ID: 32, KorName: "꿀", EngName: "Honey"},
+		{ID: 33, KorName: "스모키", EngName: "Smoky"},
+	}
+	for _, v := range []interface{}{&region, &distillery, &tags} {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for id := int64(1); id <= 5; id++ {
+		alcohol := domain.Alcohol{ID: id, KorName: fmt.Sprintf("술 %d", id), EngName: fmt.Sprintf("Alcohol %d", id), Type: "Single Malt", LastModifyAt: &modified}
+		if id%2 == 1 {
+			alcohol.RegionID = ptr(region.ID)
+			alcohol.DistilleryID = ptr(distillery.ID)
+		}
+		if err := db.Omit("TastingTags").Create(&alcohol).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := []struct{ alcohol, tag int64 }{{1, 33}, {1, 31}, {1, 32}, {2, 32}}
+	for _, l := range links {
+		if err := db.Exec("INSERT INTO alcohol_tasting_tags (alcohol_id, tasting_tag_id) VALUES (?, ?)", l.alcohol, l.tag).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func alcoholIDs(alcohols []domain.Alcohol) []int64 {
+	ids := make([]int64, len(alcohols))
+	for i, a := range alcohols {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAlcoholRepositoryPreloadsRelations(t *testing.T) {
+	db := newTestDB(t)
+	seedAlcohols(t, db)
+	repo := NewAlcoholRepository(db)
+
+	alcohols, err := repo.FindByIDs(context.Background(), []int64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alcohols) != 2 {
+		t.Fatalf("결과 %d개", len(alcohols))
+	}
+
+	first := alcohols[0]
+	if first.Region == nil || first.Region.EngName != "Speyside" {
+		t.Fatalf("Region 미로딩: %+v", first.Region)
+	}
+	if first.Distillery == nil || first.Distillery.EngName != "Glenfiddich" {
+		t.Fatalf("Distillery 미로딩: %+v", first.Distillery)
+	}
+	// 태그는 연결 순서가 아니라 태그 ID 오름차순
+	var tagIDs []int64
+	for _, tag := range first.TastingTags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if !equalIDs(tagIDs, []int64{31, 32, 33}) {
+		t.Fatalf("TastingTags = %v", tagIDs)
+	}
+
+	second := alcohols[1]
+	if second.Region != nil || second.Distillery != nil {
+		t.Fatalf("연관 없는 술에 Region/Distillery: %+v %+v", second.Region, second.Distillery)
+	}
+	if len(second.TastingTags) != 1 || second.TastingTags[0].KorName != "꿀" {
+		t.Fatalf("TastingTags = %+v", second.TastingTags)
+	}
+}
+
+func TestAlcoholRepositoryIterate(t *testing.T) {
+	db := newTestDB(t)
+	seedAlcohols(t, db)
+	repo := NewAlcoholRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		afterID int64
+		limit   int
+		want    []int64
+	}{
+		{"첫 페이지", 0, 2, []int64{1, 2}},
+		{"afterID는 제외", 2, 2, []int64{3, 4}},
+		{"마지막 짧은 페이지", 4, 2, []int64{5}},
+		{"마지막 ID 이후는 빈 페이지", 5, 2, []int64{}},
+		{"중간 ID가 없어도 다음 ID부터", -100, 1, []int64{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alcohols, err := repo.Iterate(ctx, tt.afterID, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := alcoholIDs(alcohols); !equalIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 마지막 원소 ID를 넘기며 끝까지 돌면 모든 술을 한 번씩 본다
+	var seen []int64
+	afterID := int64(0)
+	for {
+		page, err := repo.Iterate(ctx, afterID, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		seen = append(seen, alcoholIDs(page)...)
+		afterID = page[len(page)-1].ID
+	}
+	if !equalIDs(seen, []int64{1, 2, 3, 4, 5}) {
+		t.Fatalf("순회 결과 %v", seen)
+	}
+}
+
+func TestAlcoholRepositoryFindByIDs(t *testing.T) {
+	db := newTestDB(t)
+	seedAlcohols(t, db)
+	repo := NewAlcoholRepository(db)
+
+	tests := []struct {
+		name string
+		ids  []int64
+		want []int64
+	}{
+		{"ID 오름차순으로 정렬", []int64{5, 1, 3}, []int64{1, 3, 5}},
+		{"없는 ID는 빠진다", []int64{2, 99, 100}, []int64{2}},
+		{"모두 없는 ID", []int64{99}, []int64{}},
+		{"빈 목록", nil, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alcohols, err := repo.FindByIDs(context.Background(), tt.ids)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := alcoholIDs(alcohols); !equalIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlcoholRepositoryFindByIDRange(t *testing.T) {
+	db := newTestDB(t)
+	seedAlcohols(t, db)
+	repo := NewAlcoholRepository(db)
+
+	tests := []struct {
+		name       string
+		start, end int64
+		want       []int64
+	}{
+		{"양끝 포함", 2, 4, []int64{2, 3, 4}},
+		{"한 건", 3, 3, []int64{3}},
+		{"범위가 데이터를 넘는다", 4, 100, []int64{4, 5}},
+		{"빈 범위", 6, 10, []int64{}},
+		{"시작이 끝보다 크다", 4, 2, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alcohols, err := repo.FindByIDRange(context.Background(), tt.start, tt.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := alcoholIDs(alcohols); !equalIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	alcohols, err := repo.FindByIDRange(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alcohols[0].Region == nil || len(alcohols[0].TastingTags) != 3 {
+		t.Fatalf("범위 조회 연관 미로딩: %+v", alcohols[0])
+	}
+}
+
+func TestAlcoholRepositoryCount(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAlcoholRepository(db)
+	ctx := context.Background()
+
+	count, err := repo.Count(ctx)
+	if err != nil || count != 0 {
+		t.Fatalf("빈 테이블 Count = %d, %v", count, err)
+	}
+
+	seedAlcohols(t, db)
+	count, err = repo.Count(ctx)
+	if err != nil || count != 5 {
+		t.Fatalf("Count = %d, %v", count, err)
+	}
+}
+
+// addLastModifyAt 운영 스키마에 따라 연관 테이블에도 last_modify_at이 있는 경우를 흉내 낸다
+func addLastModifyAt(t *testing.T, db *gorm.DB, table string, id int64, at time.Time) {
+	t.Helper()
+	if err := db.Exec("ALTER TABLE " + table + " ADD COLUMN last_modify_at datetime").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("UPDATE "+table+" SET last_modify_at = ? WHERE id = ?", at, id).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAlcoholRepositoryFindChangedIDs(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAlcoholRepository(db)
+	ctx := context.Background()
+
+	old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	from := old.Add(24 * time.Hour)
+	recent := from.Add(time.Hour)
+
+	rows := []interface{}{
+		&domain.Region{ID: 10, KorName: "아일라"},
+		&domain.Distillery{ID: 20, KorName: "라프로익"},
+		&domain.TastingTag{ID: 30, KorName: "스모키"},
+		&domain.Alcohol{ID: 1, KorName: "경계값", LastModifyAt: &from},
+		&domain.Alcohol{ID: 2, KorName: "오래된 술", LastModifyAt: &old},
+		&domain.Alcohol{ID: 3, KorName: "지역 변경", RegionID: ptr(int64(10)), LastModifyAt: &old},
+		&domain.Alcohol{ID: 4, KorName: "태그 변경", LastModifyAt: &old},
+		&domain.Alcohol{ID: 5, KorName: "구간 이후", LastModifyAt: ptr(recent.Add(time.Second))},
+		&domain.Alcohol{ID: 6, KorName: "증류소 연결", DistilleryID: ptr(int64(20)), LastModifyAt: &old},
+	}
+	for _, row := range rows {
+		if err := db.Omit("TastingTags").Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Exec("INSERT INTO alcohol_tasting_tags (alcohol_id, tasting_tag_id) VALUES (4, 30)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 연관 테이블에 last_modify_at이 하나도 없으면 술 자체의 변경만 본다
+	ids, err := repo.FindChangedIDs(ctx, from, recent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalIDs(ids, []int64{1}) {
+		t.Fatalf("연관 컬럼 없음 ids = %v", ids)
+	}
+	latest, err := repo.MaxModifiedAt(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !latest.Equal(recent.Add(time.Second)) {
+		t.Fatalf("연관 컬럼 없음 MaxModifiedAt = %s", latest)
+	}
+
+	// regions, tasting_tags에만 컬럼이 생기면 그 두 leg가 붙고 distilleries는 계속 건너뛴다
+	addLastModifyAt(t, db, "regions", 10, recent)
+	addLastModifyAt(t, db, "tasting_tags", 30, recent.Add(2*time.Second))
+
+	ids, err = repo.FindChangedIDs(ctx, from, recent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// from과 같은 시각은 포함 (겹침 재조회 경계), until 이후는 제외
+	if !equalIDs(ids, []int64{1, 3}) {
+		t.Fatalf("ids = %v", ids)
+	}
+	ids, err = repo.FindChangedIDs(ctx, from, recent.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalIDs(ids, []int64{1, 3, 4, 5}) {
+		t.Fatalf("태그 포함 ids = %v", ids)
+	}
+
+	latest, err = repo.MaxModifiedAt(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !latest.Equal(recent.Add(2 * time.Second)) {
+		t.Fatalf("MaxModifiedAt = %s", latest)
+	}
+}
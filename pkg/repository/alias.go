@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+var (
+	// ErrAliasNotFound 저장된 alias 포인터 없음 (설정의 첫 버전이 활성)
+	ErrAliasNotFound = errors.New("collection alias not found")
+	// ErrAliasConflict 읽은 뒤 다른 전환이 포인터를 바꿈 (다시 읽고 재시도)
+	ErrAliasConflict = errors.New("collection alias changed concurrently")
+)
+
+// AliasStore 컬렉션 alias 포인터 저장소 (MySQL 또는 로컬 파일)
+// 포인터는 한 행(파일)으로 저장되므로 전환은 원자적이다
+type AliasStore interface {
+	// Aliases 저장된 전체 포인터
+	Aliases(ctx context.Context) ([]domain.CollectionAlias, error)
+	// Alias 포인터 조회, 없으면 ErrAliasNotFound
+	Alias(ctx context.Context, alias string) (*domain.CollectionAlias, error)
+	// SaveAlias 저장된 활성 버전이 expected일 때만 포인터를 바꾼다 (expected가 ""이면 포인터가 없을 때만 만든다)
+	// 그 사이 다른 전환이 있었으면 ErrAliasConflict
+	SaveAlias(ctx context.Context, alias *domain.CollectionAlias, expected string) error
+}
+
+// ApplyAliases 저장된 포인터를 레지스트리에 반영 (설정에서 빠진 alias/버전은 건너뛴다)
+func ApplyAliases(ctx context.Context, store AliasStore, collections *Collections) error {
+	aliases, err := store.Aliases(ctx)
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		_ = collections.SetActive(alias.Alias, alias.Active)
+	}
+	return nil
+}
+
+// GormAliasStore MySQL 테이블 기반 AliasStore
+type GormAliasStore struct {
+	db *gorm.DB
+}
+
+// NewGormAliasStore alias 테이블을 AutoMigrate 후 생성
+func NewGormAliasStore(db *gorm.DB) (*GormAliasStore, error) {
+	if err := db.AutoMigrate(&domain.CollectionAlias{}); err != nil {
+		return nil, fmt.Errorf("alias 테이블 마이그레이션 실패: %w", err)
+	}
+	return &GormAliasStore{db: db}, nil
+}
+
+func (s *GormAliasStore) Aliases(ctx context.Context) ([]domain.CollectionAlias, error) {
+	var aliases []domain.CollectionAlias
+	if err := s.db.WithContext(ctx).Order("alias").Find(&aliases).Error; err != nil {
+		return nil, fmt.Errorf("alias 목록 조회 실패: %w", err)
+	}
+	return aliases, nil
+}
+
+func (s *GormAliasStore) Alias(ctx context.Context, alias string) (*domain.CollectionAlias, error) {
+	var row domain.CollectionAlias
+	err := s.db.WithContext(ctx).First(&row, "alias = ?", alias).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAliasNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("alias %s 조회 실패: %w", alias, err)
+	}
+	return &row, nil
+}
+
+// SaveAlias 조건부 UPDATE (WHERE alias=? AND active=?) 또는 INSERT ... ON CONFLICT DO NOTHING, 바뀐 행이 없으면 충돌
+func (s *GormAliasStore) SaveAlias(ctx context.Context, alias *domain.CollectionAlias, expected string) error {
+	db := s.db.WithContext(ctx)
+	var result *gorm.DB
+	if expected == "" {
+		result = db.Clauses(clause.OnConflict{DoNothing: true}).Create(alias)
+	} else {
+		result = db.Model(&domain.CollectionAlias{}).
+			Where("alias = ? AND active = ?", alias.Alias, expected).
+			Updates(map[string]interface{}{
+				"active":     alias.Active,
+				"previous":   alias.Previous,
+				"updated_at": alias.UpdatedAt,
+			})
+	}
+	if result.Error != nil {
+		return fmt.Errorf("alias %s 저장 실패: %w", alias.Alias, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("alias %s: %w", alias.Alias, ErrAliasConflict)
+	}
+	return nil
+}
+
+// FileAliasStore 디렉토리 기반 AliasStore (standalone 모드), alias당 alias_{alias}.json
+// 저장은 alias_{alias}.json.lock을 잠근 채 비교 후 쓴다 (같은 호스트의 다른 프로세스와도 배타)
+type FileAliasStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewFileAliasStore(dir string) (*FileAliasStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("alias 디렉토리 생성 실패: %w", err)
+	}
+	return &FileAliasStore{dir: dir}, nil
+}
+
+func (s *FileAliasStore) Aliases(ctx context.Context) ([]domain.CollectionAlias, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "alias_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("alias 목록 조회 실패: %w", err)
+	}
+	sort.Strings(paths)
+
+	aliases := make([]domain.CollectionAlias, 0, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "alias_"), ".json")
+		alias, err := s.Alias(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, *alias)
+	}
+	return aliases, nil
+}
+
+func (s *FileAliasStore) Alias(ctx context.Context, alias string) (*domain.CollectionAlias, error) {
+	data, err := os.ReadFile(s.path(alias))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrAliasNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("alias %s 조회 실패: %w", alias, err)
+	}
+	var row domain.CollectionAlias
+	if err := json.Unmarshal(data, &row); err != nil {
+		return nil, fmt.Errorf("alias %s 파싱 실패: %w", alias, err)
+	}
+	return &row, nil
+}
+
+func (s *FileAliasStore) SaveAlias(ctx context.Context, alias *domain.CollectionAlias, expected string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := lockFile(s.path(alias.Alias) + ".lock")
+	if err != nil {
+		return fmt.Errorf("alias %s 잠금 실패: %w", alias.Alias, err)
+	}
+	defer unlock()
+
+	current, err := s.Alias(ctx, alias.Alias)
+	switch {
+	case errors.Is(err, ErrAliasNotFound):
+		if expected != "" {
+			return fmt.Errorf("alias %s: %w", alias.Alias, ErrAliasConflict)
+		}
+	case err != nil:
+		return err
+	case current.Active != expected:
+		return fmt.Errorf("alias %s: %w", alias.Alias, ErrAliasConflict)
+	}
+	if err := writeJSONFile(s.path(alias.Alias), alias); err != nil {
+		return fmt.Errorf("alias %s 저장 실패: %w", alias.Alias, err)
+	}
+	return nil
+}
+
+func (s *FileAliasStore) path(alias string) string {
+	return filepath.Join(s.dir, "alias_"+filepath.Base(alias)+".json")
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// 두 저장소 모두 읽은 활성 버전이 그대로일 때만 포인터를 바꾼다
+func TestSaveAliasCompareAndSwap(t *testing.T) {
+	gormStore, err := NewGormAliasStore(newTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileStore, err := NewFileAliasStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, store := range map[string]AliasStore{"gorm": gormStore, "file": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			save := func(active, previous, expected string) error {
+				return store.SaveAlias(ctx, &domain.CollectionAlias{Alias: "whisky", Active: active, Previous: previous, UpdatedAt: time.Now()}, expected)
+			}
+
+			steps := []struct {
+				name                       string
+				active, previous, expected string
+				conflict                   bool
+			}{
+				{"없으면 생성", "whisky_v2", "whisky_v1", "", false},
+				{"이미 있으면 생성 실패", "whisky_v3", "whisky_v1", "", true},
+				{"다른 활성 버전 기준", "whisky_v3", "whisky_v1", "whisky_v1", true},
+				{"읽은 활성 버전 기준", "whisky_v3", "whisky_v2", "whisky_v2", false},
+				{"같은 전환 두 번", "whisky_v3", "whisky_v2", "whisky_v2", true},
+			}
+			for _, step := range steps {
+				err := save(step.active, step.previous, step.expected)
+				if got := errors.Is(err, ErrAliasConflict); got != step.conflict || (!step.conflict && err != nil) {
+					t.Fatalf("%s: err = %v, conflict %v", step.name, err, step.conflict)
+				}
+			}
+
+			row, err := store.Alias(ctx, "whisky")
+			if err != nil || row.Active != "whisky_v3" || row.Previous != "whisky_v2" {
+				t.Fatalf("저장된 포인터 %+v, %v", row, err)
+			}
+			aliases, err := store.Aliases(ctx)
+			if err != nil || len(aliases) != 1 {
+				t.Fatalf("목록 %+v, %v", aliases, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Collections 이름 → 컬렉션 스키마 레지스트리
+// Alias가 같은 컬렉션들은 블루/그린 버전이며, alias 이름으로 조회하면 활성 버전을 돌려준다
+type Collections struct {
+	schemas map[string]Schema
+	// versions alias → 버전 컬렉션 이름 (설정 순서)
+	versions map[string][]string
+
+	mu     sync.RWMutex
+	active map[string]string
+}
+
+// NewCollections 생성자, alias의 활성 버전은 설정에서 처음 나온 컬렉션
+func NewCollections(schemas ...Schema) (*Collections, error) {
+	c := &Collections{
+		schemas:  make(map[string]Schema, len(schemas)),
+		versions: make(map[string][]string),
+		active:   make(map[string]string),
+	}
+	for _, s := range schemas {
+		if _, exists := c.schemas[s.Name]; exists {
+			return nil, fmt.Errorf("중복된 컬렉션 이름: %s", s.Name)
+		}
+		c.schemas[s.Name] = s
+		if s.Alias != "" {
+			c.versions[s.Alias] = append(c.versions[s.Alias], s.Name)
+		}
+	}
+	for alias, names := range c.versions {
+		if _, exists := c.schemas[alias]; exists {
+			return nil, fmt.Errorf("alias %s가 컬렉션 이름과 겹침", alias)
+		}
+		c.active[alias] = names[0]
+	}
+	return c, nil
+}
+
+// Get 이름으로 컬렉션 조회, alias면 현재 활성 버전
+func (c *Collections) Get(name string) (Schema, bool) {
+	if s, ok := c.schemas[name]; ok {
+		return s, true
+	}
+	c.mu.RLock()
+	active, ok := c.active[name]
+	c.mu.RUnlock()
+	if !ok {
+		return Schema{}, false
+	}
+	return c.schemas[active], true
+}
+
+// All 전체 컬렉션 (이름순, alias 제외)
+func (c *Collections) All() []Schema {
+	names := make([]string, 0, len(c.schemas))
+	for name := range c.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schemas := make([]Schema, 0, len(names))
+	for _, name := range names {
+		schemas = append(schemas, c.schemas[name])
+	}
+	return schemas
+}
+
+// Aliases alias 이름 목록 (이름순)
+func (c *Collections) Aliases() []string {
+	aliases := make([]string, 0, len(c.versions))
+	for alias := range c.versions {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Versions alias에 속한 버전 컬렉션 (설정 순서)
+func (c *Collections) Versions(alias string) []Schema {
+	names := c.versions[alias]
+	schemas := make([]Schema, 0, len(names))
+	for _, name := range names {
+		schemas = append(schemas, c.schemas[name])
+	}
+	return schemas
+}
+
+// Active alias의 활성 버전 이름
+func (c *Collections) Active(alias string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	name, ok := c.active[alias]
+	return name, ok
+}
+
+// SetActive alias의 활성 버전 교체 (이후 Get(alias)부터 바로 반영)
+func (c *Collections) SetActive(alias, name string) error {
+	if _, ok := c.versions[alias]; !ok {
+		return fmt.Errorf("알 수 없는 alias: %s", alias)
+	}
+	if s, ok := c.schemas[name]; !ok || s.Alias != alias {
+		return fmt.Errorf("컬렉션 %s는 alias %s의 버전이 아님", name, alias)
+	}
+	c.mu.Lock()
+	c.active[alias] = name
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,541 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/hnsw"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// EmbeddedStore 프로세스 내 HNSW 인덱스 기반 VectorStore (외부 벡터 DB 없는 standalone 모드)
+// 컬렉션별로 dir/{collection}/ 아래에 문서(JSON)와 벡터 필드별 HNSW 스냅샷을 저장한다
+// 스냅샷은 saveInterval마다(바뀐 내용이 있을 때만)와 Close 때 기록하며,
+// 한 디렉토리는 한 프로세스만 열 수 있다 (serve와 index가 서로의 스냅샷을 덮어쓰지 않도록)
+type EmbeddedStore struct {
+	dir         string
+	collections map[string]*embeddedCollection
+
+	// saveMu 주기 저장과 Close의 동시 Save 직렬화
+	saveMu sync.Mutex
+	// dirty 마지막 스냅샷 이후 쓰기가 있었는지
+	dirty     atomic.Bool
+	unlock    func() error
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+type embeddedCollection struct {
+	// writeMu 쓰기(Upsert/Update/Delete)는 공유로, 스냅샷은 배타로 잡아 문서와 인덱스가 같은 시점으로 저장되게 한다
+	writeMu sync.RWMutex
+
+	mu      sync.RWMutex
+	docs    map[int64]embeddedDocument
+	indexes map[string]*hnsw.Index
+}
+
+// embeddedDocument 벡터를 제외한 문서 (벡터는 HNSW 인덱스에 저장)
+type embeddedDocument struct {
+	ID     int64                  `json:"id"`
+	Sparse map[string]float32     `json:"sparse,omitempty"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// NewEmbeddedStore 디렉토리 잠금 후 컬렉션별 인덱스 생성, dir에 스냅샷이 있으면 복원
+// saveInterval이 0보다 크면 그 주기로 바뀐 내용을 스냅샷에 기록한다 (0이면 Close 때만)
+func NewEmbeddedStore(dir string, schemas []Schema, saveInterval time.Duration) (*EmbeddedStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("embedded 디렉토리 생성 실패: %w", err)
+	}
+	unlock, err := lockDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &EmbeddedStore{
+		dir:         dir,
+		collections: make(map[string]*embeddedCollection, len(schemas)),
+		unlock:      unlock,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, s := range schemas {
+		collection, err := loadEmbeddedCollection(filepath.Join(dir, s.Name), s)
+		if err != nil {
+			_ = unlock()
+			return nil, fmt.Errorf("컬렉션 %s 로드 실패: %w", s.Name, err)
+		}
+		store.collections[s.Name] = collection
+	}
+
+	if saveInterval > 0 {
+		go store.autoSave(saveInterval)
+	} else {
+		close(store.done)
+	}
+	return store, nil
+}
+
+// autoSave 주기마다 바뀐 내용이 있으면 스냅샷 기록 (실패하면 다음 주기에 다시 시도)
+func (store *EmbeddedStore) autoSave(interval time.Duration) {
+	defer close(store.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-store.stop:
+			return
+		case <-ticker.C:
+			if !store.dirty.Load() {
+				continue
+			}
+			if err := store.Save(); err != nil {
+				fmt.Printf("[WARN] embedded 스냅샷 저장 실패: %v\n", err)
+			}
+		}
+	}
+}
+
+func loadEmbeddedCollection(dir string, s Schema) (*embeddedCollection, error) {
+	collection := &embeddedCollection{
+		docs:    make(map[int64]embeddedDocument),
+		indexes: make(map[string]*hnsw.Index, len(s.Vectors)),
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "documents.json"))
+	switch {
+	case err == nil:
+		var docs []embeddedDocument
+		if err := json.Unmarshal(raw, &docs); err != nil {
+			return nil, fmt.Errorf("documents.json 파싱 실패: %w", err)
+		}
+		for _, doc := range docs {
+			collection.docs[doc.ID] = doc
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	for _, v := range s.Vectors {
+		index, err := hnsw.LoadFile(filepath.Join(dir, v.Name+".hnsw"))
+		if os.IsNotExist(err) {
+			index, err = hnsw.New(hnsw.DefaultConfig(v.Dimension, hnsw.Cosine))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s 인덱스 로드 실패: %w", v.Name, err)
+		}
+		collection.indexes[v.Name] = index
+	}
+	return collection, nil
+}
+
+func (store *EmbeddedStore) collection(s Schema) (*embeddedCollection, error) {
+	collection, ok := store.collections[s.Name]
+	if !ok {
+		return nil, fmt.Errorf("embedded: 등록되지 않은 컬렉션 %s", s.Name)
+	}
+	return collection, nil
+}
+
+// Upsert 문서 저장 (HNSW 인덱스는 동시 삽입을 지원하므로 문서 맵만 잠근다)
+// 문서를 먼저 넣어 동시 검색이 벡터만 있고 페이로드가 없는 문서를 돌려주지 않게 한다
+func (store *EmbeddedStore) Upsert(ctx context.Context, s Schema, docs []Document) error {
+	collection, err := store.collection(s)
+	if err != nil {
+		return err
+	}
+	collection.writeMu.RLock()
+	defer collection.writeMu.RUnlock()
+	defer store.dirty.Store(true)
+
+	for _, doc := range docs {
+		collection.mu.Lock()
+		collection.docs[doc.ID] = embeddedDocument{ID: doc.ID, Sparse: doc.Sparse, Fields: doc.Fields}
+		collection.mu.Unlock()
+
+		for name, index := range collection.indexes {
+			vector, ok := doc.Vectors[name]
+			if !ok {
+				index.Delete(doc.ID)
+				continue
+			}
+			if err := index.Insert(doc.ID, vector); err != nil {
+				return fmt.Errorf("문서 %d %s 벡터 저장 실패: %w", doc.ID, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Update 부분 갱신, 바뀐 벡터만 HNSW에 다시 삽입 (기존 노드는 tombstone 처리)
+func (store *EmbeddedStore) Update(ctx context.Context, s Schema, docs []Document) error {
+	collection, err := store.collection(s)
+	if err != nil {
+		return err
+	}
+	collection.writeMu.RLock()
+	defer collection.writeMu.RUnlock()
+	defer store.dirty.Store(true)
+
+	for _, doc := range docs {
+		for name := range doc.Vectors {
+			if _, ok := collection.indexes[name]; !ok {
+				return fmt.Errorf("embedded: 벡터 필드 %s 없음", name)
+			}
+		}
+
+		collection.mu.Lock()
+		stored, ok := collection.docs[doc.ID]
+		if ok {
+			merged := mergeDocument(Document{ID: stored.ID, Sparse: stored.Sparse, Fields: stored.Fields}, Document{Sparse: doc.Sparse, Fields: doc.Fields})
+			collection.docs[doc.ID] = embeddedDocument{ID: doc.ID, Sparse: merged.Sparse, Fields: merged.Fields}
+		}
+		collection.mu.Unlock()
+		if !ok {
+			return fmt.Errorf("문서 %d 갱신 실패: %w", doc.ID, ErrNotFound)
+		}
+
+		for name, vector := range doc.Vectors {
+			if err := collection.indexes[name].Insert(doc.ID, vector); err != nil {
+				return fmt.Errorf("문서 %d %s 벡터 저장 실패: %w", doc.ID, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetFields 지정 필드만 복사해 반환
+func (store *EmbeddedStore) GetFields(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string]interface{}, error) {
+	collection, err := store.collection(s)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.mu.RLock()
+	defer collection.mu.RUnlock()
+	result := make(map[int64]map[string]interface{}, len(ids))
+	for _, id := range ids {
+		if doc, ok := collection.docs[id]; ok {
+			result[id] = pickFields(doc.Fields, names)
+		}
+	}
+	return result, nil
+}
+
+// GetVectors HNSW 인덱스에서 지정 벡터만 반환
+func (store *EmbeddedStore) GetVectors(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string][]float32, error) {
+	collection, err := store.collection(s)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.mu.RLock()
+	defer collection.mu.RUnlock()
+	result := make(map[int64]map[string][]float32, len(ids))
+	for _, id := range ids {
+		if _, ok := collection.docs[id]; !ok {
+			continue
+		}
+		vectors := make(map[string][]float32, len(names))
+		for _, name := range names {
+			if index, ok := collection.indexes[name]; ok {
+				if vector, ok := index.Vector(id); ok {
+					vectors[name] = vector
+				}
+			}
+		}
+		result[id] = vectors
+	}
+	return result, nil
+}
+
+// Delete 문서 삭제 (HNSW는 tombstone 처리)
+func (store *EmbeddedStore) Delete(ctx context.Context, s Schema, ids []int64) error {
+	collection, err := store.collection(s)
+	if err != nil {
+		return err
+	}
+	collection.writeMu.RLock()
+	defer collection.writeMu.RUnlock()
+	defer store.dirty.Store(true)
+
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+	for _, id := range ids {
+		for _, index := range collection.indexes {
+			index.Delete(id)
+		}
+		delete(collection.docs, id)
+	}
+	return nil
+}
+
+// Get 단일 문서 조회 (벡터는 정규화된 값)
+func (store *EmbeddedStore) Get(ctx context.Context, s Schema, id int64) (*Document, error) {
+	collection, err := store.collection(s)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.mu.RLock()
+	defer collection.mu.RUnlock()
+	return collection.document(id)
+}
+
+func (collection *embeddedCollection) document(id int64) (*Document, error) {
+	stored, ok := collection.docs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	doc := &Document{
+		ID:      stored.ID,
+		Vectors: make(map[string][]float32, len(collection.indexes)),
+		Sparse:  stored.Sparse,
+		Fields:  stored.Fields,
+	}
+	for name, index := range collection.indexes {
+		if vector, ok := index.Vector(id); ok {
+			doc.Vectors[name] = vector
+		}
+	}
+	return doc, nil
+}
+
+// Search 단일 벡터 필드 HNSW 검색 (필터는 그래프 탐색 중 적용)
+func (store *EmbeddedStore) Search(ctx context.Context, s Schema, q KNNQuery) ([]Hit, error) {
+	collection, err := store.collection(s)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.mu.RLock()
+	defer collection.mu.RUnlock()
+
+	hits, err := collection.searchDense(q.Field, q.Vector, q.Offset+q.Limit, q.Filters)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(hits, q.Offset, q.Limit), nil
+}
+
+// HybridSearch 필드별 HNSW 검색 + sparse 내적 결과를 융합
+func (store *EmbeddedStore) HybridSearch(ctx context.Context, s Schema, q HybridQuery) ([]Hit, error) {
+	collection, err := store.collection(s)
+	if err != nil {
+		return nil, err
+	}
+
+	targetHits := q.TargetHits
+	if targetHits <= 0 {
+		targetHits = defaultTargetHits
+	}
+
+	collection.mu.RLock()
+	defer collection.mu.RUnlock()
+
+	legs := make(map[string][]Hit)
+	for _, field := range q.DenseFields(s) {
+		hits, err := collection.searchDense(field, q.Dense, targetHits, q.Filters)
+		if err != nil {
+			return nil, err
+		}
+		legs[field] = hits
+	}
+	if len(q.Sparse) > 0 {
+		legs[schema.SparseKeywords] = paginate(collection.scoreSparse(q.Sparse, q.Filters), 0, targetHits)
+	}
+
+	return paginate(FuseHits(legs, q.Weights, q.Fusion), q.Offset, q.Limit), nil
+}
+
+func (collection *embeddedCollection) searchDense(field string, vector []float32, k int, filters []Filter) ([]Hit, error) {
+	index, ok := collection.indexes[field]
+	if !ok {
+		return nil, fmt.Errorf("embedded: 벡터 필드 %s 없음", field)
+	}
+
+	var filter func(id int64) bool
+	if len(filters) > 0 {
+		filter = func(id int64) bool {
+			return matchFilters(collection.docs[id].Fields, filters)
+		}
+	}
+	results, err := index.Search(vector, k, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(results))
+	for _, r := range results {
+		doc, ok := collection.docs[r.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, Hit{ID: r.ID, Score: r.Score, Fields: doc.Fields})
+	}
+	return hits, nil
+}
+
+func (collection *embeddedCollection) scoreSparse(sparse map[string]float32, filters []Filter) []Hit {
+	hits := make([]Hit, 0)
+	for _, doc := range collection.docs {
+		if !matchFilters(doc.Fields, filters) {
+			continue
+		}
+		score := 0.0
+		for token, weight := range sparse {
+			score += float64(weight * doc.Sparse[token])
+		}
+		if score > 0 {
+			hits = append(hits, Hit{ID: doc.ID, Score: score, Fields: doc.Fields})
+		}
+	}
+	sortHits(hits)
+	return hits
+}
+
+// Visit 전체 문서 순회 (ID 오름차순)
+func (store *EmbeddedStore) Visit(ctx context.Context, s Schema, fn func(doc *Document) error) error {
+	collection, err := store.collection(s)
+	if err != nil {
+		return err
+	}
+
+	collection.mu.RLock()
+	ids := make([]int64, 0, len(collection.docs))
+	for id := range collection.docs {
+		ids = append(ids, id)
+	}
+	collection.mu.RUnlock()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		collection.mu.RLock()
+		doc, err := collection.document(id)
+		collection.mu.RUnlock()
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err := fn(doc); err != nil {
+			if errors.Is(err, ErrStopVisit) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// VisitFields 지정 필드만 복사해 ID 오름차순 순회 (HNSW 벡터는 읽지 않는다)
+func (store *EmbeddedStore) VisitFields(ctx context.Context, s Schema, names []string, fn func(id int64, fields map[string]interface{}) error) error {
+	collection, err := store.collection(s)
+	if err != nil {
+		return err
+	}
+
+	collection.mu.RLock()
+	ids := make([]int64, 0, len(collection.docs))
+	for id := range collection.docs {
+		ids = append(ids, id)
+	}
+	collection.mu.RUnlock()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		collection.mu.RLock()
+		doc, ok := collection.docs[id]
+		var fields map[string]interface{}
+		if ok {
+			fields = pickFields(doc.Fields, names)
+		}
+		collection.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		if err := fn(id, fields); err != nil {
+			if errors.Is(err, ErrStopVisit) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Save 전체 컬렉션 스냅샷 기록 (컬렉션마다 쓰기를 멈추고 문서와 인덱스를 같은 시점으로 저장)
+func (store *EmbeddedStore) Save() error {
+	store.saveMu.Lock()
+	defer store.saveMu.Unlock()
+
+	// 저장 중에 들어온 쓰기는 다시 dirty로 표시되어 다음 주기에 저장된다
+	store.dirty.Store(false)
+	for name, collection := range store.collections {
+		if err := collection.save(filepath.Join(store.dir, name)); err != nil {
+			store.dirty.Store(true)
+			return fmt.Errorf("컬렉션 %s 스냅샷 실패: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Close 주기 저장 중지, 마지막 스냅샷 기록 후 디렉토리 잠금 해제
+func (store *EmbeddedStore) Close() error {
+	store.closeOnce.Do(func() {
+		select {
+		case <-store.done:
+		default:
+			close(store.stop)
+			<-store.done
+		}
+		store.closeErr = store.Save()
+		if err := store.unlock(); err != nil && store.closeErr == nil {
+			store.closeErr = fmt.Errorf("embedded 디렉토리 잠금 해제 실패: %w", err)
+		}
+	})
+	return store.closeErr
+}
+
+func (collection *embeddedCollection) save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	collection.writeMu.Lock()
+	defer collection.writeMu.Unlock()
+
+	collection.mu.RLock()
+	docs := make([]embeddedDocument, 0, len(collection.docs))
+	for _, doc := range collection.docs {
+		docs = append(docs, doc)
+	}
+	collection.mu.RUnlock()
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+
+	raw, err := json.Marshal(docs)
+	if err != nil {
+		return fmt.Errorf("documents.json 인코딩 실패: %w", err)
+	}
+	path := filepath.Join(dir, "documents.json")
+	if err := os.WriteFile(path+".tmp", raw, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+
+	for name, index := range collection.indexes {
+		if err := index.SaveFile(filepath.Join(dir, name+".hnsw")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package repository
+
+// lockDir flock이 없는 플랫폼은 잠그지 않는다 (한 디렉토리를 여러 프로세스가 열지 않도록 주의)
+func lockDir(dir string) (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockDir dir/.lock에 배타 flock (프로세스가 죽으면 커널이 풀어 준다)
+func lockDir(dir string) (func() error, error) {
+	f, err := os.OpenFile(filepath.Join(dir, ".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("embedded 잠금 파일 열기 실패: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("embedded 저장소 %s를 다른 프로세스가 사용 중 (serve와 index 등을 동시에 실행할 수 없음)", dir)
+		}
+		return nil, fmt.Errorf("embedded 저장소 잠금 실패: %w", err)
+	}
+	return func() error {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		return f.Close()
+	}, nil
+}
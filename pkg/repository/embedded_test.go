@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func embeddedDoc(id int64, x float32) Document {
+	return Document{
+		ID:      id,
+		Vectors: map[string][]float32{"flavor": {x, 1, 0}, "identity": {1, x, 0}},
+		Fields:  map[string]interface{}{"kor_name": "위스키"},
+	}
+}
+
+func TestEmbeddedStoreAutoSave(t *testing.T) {
+	dir := t.TempDir()
+	s := testWhiskySchema()
+	store, err := NewEmbeddedStore(dir, []Schema{s}, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.Upsert(context.Background(), s, []Document{embeddedDoc(1, 0.5), embeddedDoc(2, -0.5)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close 전에도 주기 저장으로 스냅샷이 생긴다
+	path := filepath.Join(dir, s.Name, "documents.json")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("주기 저장 스냅샷 없음")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("임시 파일 남음: %v", err)
+	}
+}
+
+func TestEmbeddedStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	s := testWhiskySchema()
+	ctx := context.Background()
+
+	store, err := NewEmbeddedStore(dir, []Schema{s}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Upsert(ctx, s, []Document{embeddedDoc(1, 0.5), embeddedDoc(2, -0.5)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, s, []int64{2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 두 번째 Close는 같은 결과
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewEmbeddedStore(dir, []Schema{s}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	doc, err := reopened.Get(ctx, s, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Fields["kor_name"] != "위스키" || len(doc.Vectors["flavor"]) != 3 {
+		t.Fatalf("복원 문서 %+v", doc)
+	}
+	if _, err := reopened.Get(ctx, s, 2); err != ErrNotFound {
+		t.Fatalf("삭제된 문서: %v", err)
+	}
+}
+
+func TestEmbeddedStoreDirLock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("디렉토리 잠금은 unix에서만")
+	}
+	dir := t.TempDir()
+	s := testWhiskySchema()
+
+	store, err := NewEmbeddedStore(dir, []Schema{s}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEmbeddedStore(dir, []Schema{s}, 0); err == nil {
+		t.Fatal("같은 디렉토리를 두 번 열 수 있음")
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 닫은 뒤에는 다시 열 수 있다
+	reopened, err := NewEmbeddedStore(dir, []Schema{s}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened.Close()
+}
+
+// go test -race로 쓰기/검색/스냅샷 동시 실행을 확인한다
+func TestEmbeddedStoreConcurrentSave(t *testing.T) {
+	s := testWhiskySchema()
+	store, err := NewEmbeddedStore(t.TempDir(), []Schema{s}, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				id := int64(w*100 + i)
+				if err := store.Upsert(ctx, s, []Document{embeddedDoc(id, float32(i)/100)}); err != nil {
+					t.Error(err)
+					return
+				}
+				hits, err := store.Search(ctx, s, KNNQuery{Field: "flavor", Vector: []float32{1, 1, 0}, Limit: 5})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for _, hit := range hits {
+					if hit.Fields == nil {
+						t.Errorf("문서 %d 필드 없음", hit.ID)
+						return
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
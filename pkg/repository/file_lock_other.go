@@ -0,0 +1,8 @@
+//go:build !unix
+
+package repository
+
+// lockFile flock이 없는 플랫폼은 프로세스 사이를 잠그지 않는다 (한 프로세스 안은 호출자가 mutex로 막는다)
+func lockFile(path string) (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+)
+
+// FilterOp 필터 조건식 연산
+type FilterOp string
+
+const (
+	FilterEq    FilterOp = "eq"
+	FilterIn    FilterOp = "in"
+	FilterRange FilterOp = "range"
+	FilterNot   FilterOp = "not"
+	FilterAnd   FilterOp = "and"
+	FilterOr    FilterOp = "or"
+)
+
+// Filter 메타데이터 조건식 (배열 필드는 포함 여부), 쿼리의 Filters 목록은 AND
+// Op를 비우면 Min/Max 유무로 eq 또는 range
+type Filter struct {
+	Op    FilterOp
+	Field string
+	// Value eq 비교 값
+	Value interface{}
+	// Values in 후보 값 (하나라도 같으면 일치)
+	Values []interface{}
+	// Min, Max 범위 경계 (nil이면 경계 없음, Exclusive가 아니면 양끝 포함)
+	Min          *float64
+	Max          *float64
+	ExclusiveMin bool
+	ExclusiveMax bool
+	// Children not(1개), and, or의 하위 조건
+	Children []Filter
+}
+
+// Eq field == value 조건
+func Eq(field string, value interface{}) Filter {
+	return Filter{Op: FilterEq, Field: field, Value: value}
+}
+
+// In field가 values 중 하나인 조건
+func In(field string, values ...interface{}) Filter {
+	return Filter{Op: FilterIn, Field: field, Values: values}
+}
+
+// RangeFilter min <= field <= max 범위 조건 (nil이면 해당 경계 없음)
+func RangeFilter(field string, min, max *float64) Filter {
+	return Filter{Op: FilterRange, Field: field, Min: min, Max: max}
+}
+
+// OverlapFilters 저장된 범위 [field_min, field_max]가 r(field 범위 조건)과 겹치는 조건
+// field_max >= Min AND field_min <= Max (40~43%로 저장된 술도 "43도", abv_min=43에 걸린다)
+func OverlapFilters(r Filter) []Filter {
+	var filters []Filter
+	if r.Min != nil {
+		filter := RangeFilter(r.Field+"_max", r.Min, nil)
+		filter.ExclusiveMin = r.ExclusiveMin
+		filters = append(filters, filter)
+	}
+	if r.Max != nil {
+		filter := RangeFilter(r.Field+"_min", nil, r.Max)
+		filter.ExclusiveMax = r.ExclusiveMax
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// Not 조건 부정
+func Not(f Filter) Filter {
+	return Filter{Op: FilterNot, Children: []Filter{f}}
+}
+
+// And 모든 조건 만족
+func And(filters ...Filter) Filter {
+	return Filter{Op: FilterAnd, Children: filters}
+}
+
+// Or 하나 이상의 조건 만족
+func Or(filters ...Filter) Filter {
+	return Filter{Op: FilterOr, Children: filters}
+}
+
+// Operation 연산 (Op를 비웠으면 Min/Max 유무로 eq 또는 range)
+func (f Filter) Operation() FilterOp {
+	if f.Op != "" {
+		return f.Op
+	}
+	if f.Min != nil || f.Max != nil {
+		return FilterRange
+	}
+	return FilterEq
+}
+
+// IsRange 범위 조건인지
+func (f Filter) IsRange() bool {
+	return f.Operation() == FilterRange
+}
+
+// filterFieldPattern 필드 이름은 YQL/Qdrant 키에 그대로 들어가므로 식별자만 허용
+var filterFieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate 조건식 구조와 필드 이름 확인
+func (f Filter) Validate() error {
+	switch op := f.Operation(); op {
+	case FilterEq, FilterIn, FilterRange:
+		if !filterFieldPattern.MatchString(f.Field) {
+			return fmt.Errorf("잘못된 필터 필드 이름: %q", f.Field)
+		}
+		if op == FilterEq && !isScalar(f.Value) {
+			return fmt.Errorf("%s eq 값은 문자열/숫자/불리언이어야 함", f.Field)
+		}
+		if op == FilterIn {
+			if len(f.Values) == 0 {
+				return fmt.Errorf("%s in 값이 비어 있음", f.Field)
+			}
+			for _, v := range f.Values {
+				if !isScalar(v) {
+					return fmt.Errorf("%s in 값은 문자열/숫자/불리언이어야 함", f.Field)
+				}
+			}
+		}
+		if op == FilterRange {
+			if f.Min == nil && f.Max == nil {
+				return fmt.Errorf("%s 범위 경계 없음", f.Field)
+			}
+			if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+				return fmt.Errorf("%s 범위 하한이 상한보다 큼", f.Field)
+			}
+		}
+	case FilterNot:
+		if len(f.Children) != 1 {
+			return errors.New("not은 조건 하나만 받음")
+		}
+		return f.Children[0].Validate()
+	case FilterAnd, FilterOr:
+		if len(f.Children) == 0 {
+			return fmt.Errorf("%s 하위 조건이 비어 있음", op)
+		}
+		for _, child := range f.Children {
+			if err := child.Validate(); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("알 수 없는 필터 연산: %q", op)
+	}
+	return nil
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, float64, float32, int, int32, int64:
+		return true
+	}
+	return false
+}
+
+// ParseFilter JSON 조건식 파싱 (배열이면 AND)
+//
+//	{"eq": {"type": "Single Malt"}}
+//	{"in": {"region_id": [1, 2]}}
+//	{"range": {"abv": {"gte": 40, "lt": 50}}}
+//	{"not": {"eq": {"tastingTags": "스모키"}}}
+//	{"and": [...]}, {"or": [...]}
+//
+// eq/in/range 객체에 필드가 여러 개면 필드별 조건의 AND
+func ParseFilter(data []byte) (Filter, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return Filter{}, fmt.Errorf("필터 JSON 파싱 실패: %w", err)
+	}
+	// 첫 값 뒤에는 공백만 허용 ({"eq":...}{"in":...} 같은 입력이 뒤쪽을 버린 채 통과하지 않도록)
+	if _, err := decoder.Token(); err != io.EOF {
+		return Filter{}, errors.New("필터 JSON 뒤에 다른 데이터가 있음")
+	}
+	f, err := parseFilterNode(raw)
+	if err != nil {
+		return Filter{}, err
+	}
+	if err := f.Validate(); err != nil {
+		return Filter{}, err
+	}
+	return f, nil
+}
+
+func parseFilterNode(raw interface{}) (Filter, error) {
+	if list, ok := raw.([]interface{}); ok {
+		return parseFilterList(FilterAnd, list)
+	}
+	node, ok := raw.(map[string]interface{})
+	if !ok || len(node) != 1 {
+		return Filter{}, errors.New("필터는 연산 하나를 키로 가진 객체여야 함 (eq, in, range, not, and, or)")
+	}
+
+	for key, value := range node {
+		switch op := FilterOp(key); op {
+		case FilterAnd, FilterOr:
+			list, ok := value.([]interface{})
+			if !ok {
+				return Filter{}, fmt.Errorf("%s 값은 배열이어야 함", op)
+			}
+			return parseFilterList(op, list)
+		case FilterNot:
+			child, err := parseFilterNode(value)
+			if err != nil {
+				return Filter{}, err
+			}
+			return Not(child), nil
+		case FilterEq, FilterIn, FilterRange:
+			fields, ok := value.(map[string]interface{})
+			if !ok || len(fields) == 0 {
+				return Filter{}, fmt.Errorf("%s 값은 {필드: 값} 객체여야 함", op)
+			}
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			filters := make([]Filter, 0, len(names))
+			for _, name := range names {
+				f, err := parseFieldFilter(op, name, fields[name])
+				if err != nil {
+					return Filter{}, err
+				}
+				filters = append(filters, f)
+			}
+			if len(filters) == 1 {
+				return filters[0], nil
+			}
+			return And(filters...), nil
+		default:
+			return Filter{}, fmt.Errorf("알 수 없는 필터 연산: %q", key)
+		}
+	}
+	return Filter{}, nil
+}
+
+func parseFilterList(op FilterOp, list []interface{}) (Filter, error) {
+	children := make([]Filter, 0, len(list))
+	for _, item := range list {
+		child, err := parseFilterNode(item)
+		if err != nil {
+			return Filter{}, err
+		}
+		children = append(children, child)
+	}
+	return Filter{Op: op, Children: children}, nil
+}
+
+func parseFieldFilter(op FilterOp, field string, value interface{}) (Filter, error) {
+	switch op {
+	case FilterEq:
+		return Eq(field, value), nil
+	case FilterIn:
+		values, ok := value.([]interface{})
+		if !ok {
+			return Filter{}, fmt.Errorf("%s in 값은 배열이어야 함", field)
+		}
+		return In(field, values...), nil
+	}
+
+	bounds, ok := value.(map[string]interface{})
+	if !ok || len(bounds) == 0 {
+		return Filter{}, fmt.Errorf("%s range 값은 {gte, gt, lte, lt} 객체여야 함", field)
+	}
+	f := Filter{Op: FilterRange, Field: field}
+	for key, raw := range bounds {
+		number, ok := raw.(float64)
+		if !ok {
+			return Filter{}, fmt.Errorf("%s range %s 값은 숫자여야 함", field, key)
+		}
+		switch key {
+		case "gte", "gt":
+			if f.Min != nil {
+				return Filter{}, fmt.Errorf("%s range 하한 중복", field)
+			}
+			f.Min, f.ExclusiveMin = &number, key == "gt"
+		case "lte", "lt":
+			if f.Max != nil {
+				return Filter{}, fmt.Errorf("%s range 상한 중복", field)
+			}
+			f.Max, f.ExclusiveMax = &number, key == "lt"
+		default:
+			return Filter{}, fmt.Errorf("%s range 알 수 없는 경계: %q", field, key)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,477 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// 같은 조건식을 Vespa YQL, Qdrant 조건, 메모리 matchFilter로 바꿨을 때 같은 문서를 고르는지 확인한다
+// YQL과 Qdrant 조건은 아래 작은 해석기로 각 엔진의 매칭 규칙대로 평가한다
+//   - 배열 필드는 원소 하나라도 일치하면 일치
+//   - 값이 없는 필드는 eq/in/range 모두 불일치, not이면 일치
+
+// filterTestDocs 필터 평가용 페이로드 (JSON으로 저장됐다 읽은 모양: 숫자는 float64)
+var filterTestDocs = map[int64]map[string]interface{}{
+	1: {"type": "Single Malt", "abv": 40.0, "age": 12.0, "region_id": 10.0, "tastingTags": []interface{}{"스모키", "바닐라"}, "cask_strength": false},
+	2: {"type": "Single Malt", "abv": 46.0, "age": 18.0, "region_id": 20.0, "tastingTags": []interface{}{"바닐라"}, "cask_strength": false},
+	3: {"type": "Blend", "abv": 43.0, "region_id": 10.0, "tastingTags": []interface{}{}, "cask_strength": false},
+	4: {"type": "Blend", "abv": 57.1, "age": 10.0, "region_id": 30.0, "tastingTags": []interface{}{"스모키"}, "cask_strength": true},
+	5: {"type": "Bourbon"},
+}
+
+func TestFilterBackendsAgree(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		filters []Filter
+		want    []int64
+	}{
+		{"문자열 eq", []Filter{Eq("type", "Blend")}, []int64{3, 4}},
+		{"숫자 eq", []Filter{Eq("region_id", float64(10))}, []int64{1, 3}},
+		{"정수 값 eq", []Filter{Eq("region_id", int64(20))}, []int64{2}},
+		{"불리언 eq", []Filter{Eq("cask_strength", true)}, []int64{4}},
+		{"배열 포함", []Filter{Eq("tastingTags", "스모키")}, []int64{1, 4}},
+		{"in", []Filter{In("type", "Blend", "Bourbon")}, []int64{3, 4, 5}},
+		{"숫자 in", []Filter{In("region_id", float64(20), float64(30))}, []int64{2, 4}},
+		{"범위 양끝 포함", []Filter{RangeFilter("abv", f(43), f(46))}, []int64{2, 3}},
+		{"범위 양끝 제외", []Filter{{Op: FilterRange, Field: "abv", Min: f(40), Max: f(57.1), ExclusiveMin: true, ExclusiveMax: true}}, []int64{2, 3}},
+		{"한쪽 범위, 값 없는 문서 제외", []Filter{RangeFilter("age", nil, f(12))}, []int64{1, 4}},
+		{"not, 값 없는 문서는 일치", []Filter{Not(RangeFilter("age", f(12), nil))}, []int64{3, 4, 5}},
+		{"배열 not", []Filter{Not(Eq("tastingTags", "바닐라"))}, []int64{3, 4, 5}},
+		{"not in", []Filter{Not(In("type", "Blend", "Bourbon"))}, []int64{1, 2}},
+		{"목록은 AND", []Filter{Eq("type", "Single Malt"), RangeFilter("abv", f(45), nil)}, []int64{2}},
+		{"긍정 조건과 not", []Filter{Eq("region_id", float64(10)), Not(Eq("type", "Blend"))}, []int64{1}},
+		{"not만 있는 목록", []Filter{Not(Eq("type", "Blend")), Not(Eq("type", "Bourbon"))}, []int64{1, 2}},
+		{"or 안의 not", []Filter{Or(Eq("region_id", float64(30)), Not(Eq("type", "Blend")))}, []int64{1, 2, 4, 5}},
+		{"and 안의 not만", []Filter{Or(Eq("type", "Bourbon"), And(Not(Eq("type", "Blend")), Not(RangeFilter("abv", f(45), nil))))}, []int64{1, 5}},
+		{"이중 부정", []Filter{Not(Not(Eq("type", "Blend")))}, []int64{3, 4}},
+		{"not 안의 or", []Filter{Not(Or(Eq("type", "Blend"), Eq("tastingTags", "바닐라")))}, []int64{5}},
+		{"중첩 and/or", []Filter{And(Or(Eq("region_id", float64(10)), Eq("region_id", float64(30))), Or(RangeFilter("age", f(12), nil), Eq("cask_strength", true)))}, []int64{1, 4}},
+		{"조건 없음", nil, []int64{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range tt.filters {
+				if err := filter.Validate(); err != nil {
+					t.Fatalf("잘못된 테스트 필터: %v", err)
+				}
+			}
+
+			var yql yqlExpr = yqlTrue{}
+			if len(tt.filters) > 0 {
+				expr := yqlFilters(tt.filters, false)
+				var err error
+				if yql, err = parseYQL(expr); err != nil {
+					t.Fatalf("YQL %q: %v", expr, err)
+				}
+			}
+			qdrant := qdrantWire(t, qdrantFilter(tt.filters))
+
+			var memory, vespa, qd []int64
+			for id := int64(1); id <= int64(len(filterTestDocs)); id++ {
+				doc := filterTestDocs[id]
+				if matchFilters(doc, tt.filters) {
+					memory = append(memory, id)
+				}
+				if yql.eval(doc) {
+					vespa = append(vespa, id)
+				}
+				if qdrantMatch(doc, qdrant) {
+					qd = append(qd, id)
+				}
+			}
+			if !equalIDs(memory, tt.want) {
+				t.Errorf("memory %v, want %v", memory, tt.want)
+			}
+			if !equalIDs(vespa, tt.want) {
+				t.Errorf("vespa %v, want %v (%s)", vespa, tt.want, yqlFilters(tt.filters, false))
+			}
+			if !equalIDs(qd, tt.want) {
+				t.Errorf("qdrant %v, want %v (%v)", qd, tt.want, qdrant)
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Filter
+		wantErr string
+	}{
+		{"eq", `{"eq": {"type": "Blend"}}`, Eq("type", "Blend"), ""},
+		{"뒤 공백 허용", "{\"eq\": {\"type\": \"Blend\"}}\n  ", Eq("type", "Blend"), ""},
+		{"뒤에 다른 값", `{"eq": {"type": "Blend"}} {"eq": {"type": "Bourbon"}}`, Filter{}, "뒤에 다른 데이터"},
+		{"뒤에 쓰레기", `{"eq": {"type": "Blend"}}x`, Filter{}, "뒤에 다른 데이터"},
+		{"잘못된 JSON", `{"eq": `, Filter{}, "파싱 실패"},
+		{"모르는 연산", `{"like": {"type": "B"}}`, Filter{}, "알 수 없는 필터 연산"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilter([]byte(tt.input))
+			if tt.want.Op == "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// qdrantWire 요청 본문처럼 JSON을 거친 조건 (숫자는 float64, 목록은 []interface{})
+func qdrantWire(t *testing.T, filter map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	raw, err := json.Marshal(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wire map[string]interface{}
+	if err := json.Unmarshal(raw, &wire); err != nil {
+		t.Fatal(err)
+	}
+	return wire
+}
+
+// qdrantMatch Qdrant 조건 평가 (must: 모두, should: 하나 이상, must_not: 하나도 없음)
+func qdrantMatch(payload map[string]interface{}, cond map[string]interface{}) bool {
+	if key, ok := cond["key"].(string); ok {
+		values := payloadValues(payload[key])
+		if match, ok := cond["match"].(map[string]interface{}); ok {
+			candidates := []interface{}{match["value"]}
+			if any, ok := match["any"].([]interface{}); ok {
+				candidates = any
+			}
+			for _, v := range values {
+				for _, want := range candidates {
+					if v == want {
+						return true
+					}
+				}
+			}
+			return false
+		}
+		bounds := cond["range"].(map[string]interface{})
+		for _, v := range values {
+			n, ok := v.(float64)
+			if !ok {
+				continue
+			}
+			if gte, ok := bounds["gte"].(float64); ok && n < gte {
+				continue
+			}
+			if gt, ok := bounds["gt"].(float64); ok && n <= gt {
+				continue
+			}
+			if lte, ok := bounds["lte"].(float64); ok && n > lte {
+				continue
+			}
+			if lt, ok := bounds["lt"].(float64); ok && n >= lt {
+				continue
+			}
+			return true
+		}
+		return false
+	}
+
+	if must, ok := cond["must"].([]interface{}); ok {
+		for _, c := range must {
+			if !qdrantMatch(payload, c.(map[string]interface{})) {
+				return false
+			}
+		}
+	}
+	if should, ok := cond["should"].([]interface{}); ok && len(should) > 0 {
+		matched := false
+		for _, c := range should {
+			if qdrantMatch(payload, c.(map[string]interface{})) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if mustNot, ok := cond["must_not"].([]interface{}); ok {
+		for _, c := range mustNot {
+			if qdrantMatch(payload, c.(map[string]interface{})) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// payloadValues 스칼라는 한 원소, 배열은 원소들, 값이 없으면 빈 목록
+func payloadValues(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+// yqlExpr yqlFilters가 만드는 YQL 부분 문법의 평가 트리
+type yqlExpr interface {
+	eval(fields map[string]interface{}) bool
+}
+
+type yqlTrue struct{}
+
+func (yqlTrue) eval(map[string]interface{}) bool { return true }
+
+type yqlAnd struct{ terms []yqlExpr }
+
+func (e yqlAnd) eval(fields map[string]interface{}) bool {
+	for _, term := range e.terms {
+		if !term.eval(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+type yqlOr struct{ terms []yqlExpr }
+
+func (e yqlOr) eval(fields map[string]interface{}) bool {
+	for _, term := range e.terms {
+		if term.eval(fields) {
+			return true
+		}
+	}
+	return false
+}
+
+type yqlNot struct{ expr yqlExpr }
+
+func (e yqlNot) eval(fields map[string]interface{}) bool { return !e.expr.eval(fields) }
+
+// yqlCompare field contains "s" | field = v | field >= n ...
+type yqlCompare struct {
+	field string
+	op    string
+	value interface{}
+}
+
+func (e yqlCompare) eval(fields map[string]interface{}) bool {
+	for _, v := range payloadValues(fields[e.field]) {
+		switch e.op {
+		case "contains":
+			if v == e.value {
+				return true
+			}
+		case "=":
+			if v == e.value {
+				return true
+			}
+		default:
+			n, ok := v.(float64)
+			want, _ := e.value.(float64)
+			if !ok {
+				continue
+			}
+			if (e.op == ">=" && n >= want) || (e.op == ">" && n > want) ||
+				(e.op == "<=" && n <= want) || (e.op == "<" && n < want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// yqlParser 재귀 하강 파서, Vespa 규칙도 확인한다:
+// !(...)는 and의 항으로만 쓸 수 있고, 그 and에는 긍정 항이 하나 이상 있어야 한다
+type yqlParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseYQL(s string) (yqlExpr, error) {
+	tokens, err := tokenizeYQL(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &yqlParser{tokens: tokens}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("남은 토큰 %v", p.tokens[p.pos:])
+	}
+	return expr, nil
+}
+
+func (p *yqlParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *yqlParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *yqlParser) or() (yqlExpr, error) {
+	var terms []yqlExpr
+	for {
+		term, negated, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		if negated {
+			return nil, fmt.Errorf("or의 항으로 not을 쓸 수 없음")
+		}
+		terms = append(terms, term)
+		if p.peek() != "or" {
+			break
+		}
+		p.next()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return yqlOr{terms}, nil
+}
+
+// and 항이 하나뿐이고 그것이 not이면 negated (호출한 쪽의 and가 긍정 항을 가져야 한다)
+func (p *yqlParser) and() (yqlExpr, bool, error) {
+	var terms []yqlExpr
+	positive := false
+	for {
+		term, negated, err := p.unary()
+		if err != nil {
+			return nil, false, err
+		}
+		positive = positive || !negated
+		terms = append(terms, term)
+		if p.peek() != "and" {
+			break
+		}
+		p.next()
+	}
+	if len(terms) == 1 {
+		return terms[0], !positive, nil
+	}
+	if !positive {
+		return nil, false, fmt.Errorf("긍정 항 없는 and")
+	}
+	return yqlAnd{terms}, false, nil
+}
+
+func (p *yqlParser) unary() (yqlExpr, bool, error) {
+	switch token := p.next(); token {
+	case "!":
+		if p.next() != "(" {
+			return nil, false, fmt.Errorf("! 뒤에 ( 필요")
+		}
+		expr, err := p.or()
+		if err != nil {
+			return nil, false, err
+		}
+		if p.next() != ")" {
+			return nil, false, fmt.Errorf(") 필요")
+		}
+		return yqlNot{expr}, true, nil
+	case "(":
+		expr, err := p.or()
+		if err != nil {
+			return nil, false, err
+		}
+		if p.next() != ")" {
+			return nil, false, fmt.Errorf(") 필요")
+		}
+		return expr, false, nil
+	case "true":
+		return yqlTrue{}, false, nil
+	default:
+		field := token
+		op := p.next()
+		raw := p.next()
+		var value interface{}
+		switch {
+		case op == "contains":
+			s, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, false, fmt.Errorf("contains 값 %q: %w", raw, err)
+			}
+			value = s
+		case raw == "true" || raw == "false":
+			if op != "=" {
+				return nil, false, fmt.Errorf("불리언 비교 %s", op)
+			}
+			value = raw == "true"
+		default:
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s %s 숫자 아님: %q", field, op, raw)
+			}
+			value = n
+		}
+		return yqlCompare{field: field, op: op, value: value}, false, nil
+	}
+}
+
+func tokenizeYQL(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case c == ' ':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '!' || c == '=' || c == '<' || c == '>':
+			j := i + 1
+			if j < len(s) && s[j] == '=' && c != '!' && c != '=' {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("닫히지 않은 문자열: %s", s[i:])
+			}
+			tokens = append(tokens, s[i:j+1])
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || strings.ContainsRune("_.-", rune(s[j]))) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("알 수 없는 문자 %q", c)
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// ErrJobNotFound 인덱싱 작업 없음
+var ErrJobNotFound = errors.New("index job not found")
+
+// JobStore 인덱싱 작업 기록 + 증분 동기화 기준 시각 저장소 (MySQL 또는 로컬 파일)
+type JobStore interface {
+	// Save 작업 저장 (있으면 덮어쓰기)
+	Save(ctx context.Context, job *domain.IndexJob) error
+	// Get 작업 조회, 없으면 ErrJobNotFound
+	Get(ctx context.Context, id string) (*domain.IndexJob, error)
+	// Watermark 컬렉션의 증분 동기화 기준 시각 (없으면 zero time)
+	Watermark(ctx context.Context, collection string) (time.Time, error)
+	// SaveWatermark 기준 시각 저장
+	SaveWatermark(ctx context.Context, collection string, watermark time.Time) error
+}
+
+// GormJobStore MySQL 테이블 기반 JobStore
+type GormJobStore struct {
+	db *gorm.DB
+}
+
+// NewGormJobStore 작업 테이블을 AutoMigrate 후 생성
+func NewGormJobStore(db *gorm.DB) (*GormJobStore, error) {
+	if err := db.AutoMigrate(&domain.IndexJob{}, &domain.SyncState{}); err != nil {
+		return nil, fmt.Errorf("작업 테이블 마이그레이션 실패: %w", err)
+	}
+	return &GormJobStore{db: db}, nil
+}
+
+func (s *GormJobStore) Save(ctx context.Context, job *domain.IndexJob) error {
+	if err := s.db.WithContext(ctx).Save(job).Error; err != nil {
+		return fmt.Errorf("작업 %s 저장 실패: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (s *GormJobStore) Get(ctx context.Context, id string) (*domain.IndexJob, error) {
+	var job domain.IndexJob
+	err := s.db.WithContext(ctx).First(&job, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("작업 %s 조회 실패: %w", id, err)
+	}
+	return &job, nil
+}
+
+func (s *GormJobStore) Watermark(ctx context.Context, collection string) (time.Time, error) {
+	var state domain.SyncState
+	err := s.db.WithContext(ctx).First(&state, "collection = ?", collection).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s 동기화 기준 시각 조회 실패: %w", collection, err)
+	}
+	return state.Watermark, nil
+}
+
+func (s *GormJobStore) SaveWatermark(ctx context.Context, collection string, watermark time.Time) error {
+	state := domain.SyncState{Collection: collection, Watermark: watermark, UpdatedAt: time.Now()}
+	if err := s.db.WithContext(ctx).Save(&state).Error; err != nil {
+		return fmt.Errorf("%s 동기화 기준 시각 저장 실패: %w", collection, err)
+	}
+	return nil
+}
+
+// FileJobStore 디렉토리 기반 JobStore (standalone 모드), 작업당 {id}.json
+type FileJobStore struct {
+	dir string
+}
+
+func NewFileJobStore(dir string) (*FileJobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("작업 디렉토리 생성 실패: %w", err)
+	}
+	return &FileJobStore{dir: dir}, nil
+}
+
+func (s *FileJobStore) Save(ctx context.Context, job *domain.IndexJob) error {
+	if err := writeJSONFile(s.path(job.ID), job); err != nil {
+		return fmt.Errorf("작업 %s 저장 실패: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (s *FileJobStore) Get(ctx context.Context, id string) (*domain.IndexJob, error) {
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("작업 %s 조회 실패: %w", id, err)
+	}
+	var job domain.IndexJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("작업 %s 파싱 실패: %w", id, err)
+	}
+	return &job, nil
+}
+
+func (s *FileJobStore) Watermark(ctx context.Context, collection string) (time.Time, error) {
+	data, err := os.ReadFile(s.syncPath(collection))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s 동기화 기준 시각 조회 실패: %w", collection, err)
+	}
+	var state domain.SyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return time.Time{}, fmt.Errorf("%s 동기화 기준 시각 파싱 실패: %w", collection, err)
+	}
+	return state.Watermark, nil
+}
+
+func (s *FileJobStore) SaveWatermark(ctx context.Context, collection string, watermark time.Time) error {
+	state := domain.SyncState{Collection: collection, Watermark: watermark, UpdatedAt: time.Now()}
+	if err := writeJSONFile(s.syncPath(collection), state); err != nil {
+		return fmt.Errorf("%s 동기화 기준 시각 저장 실패: %w", collection, err)
+	}
+	return nil
+}
+
+func (s *FileJobStore) path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".json")
+}
+
+func (s *FileJobStore) syncPath(collection string) string {
+	return filepath.Join(s.dir, "sync_"+filepath.Base(collection)+".json")
+}
+
+// writeJSONFile 임시 파일에 쓴 뒤 rename (중간에 죽어도 이전 내용이 남는다)
+func writeJSONFile(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+func TestJobStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) JobStore{
+		"gorm": func(t *testing.T) JobStore {
+			store, err := NewGormJobStore(newTestDB(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+		"file": func(t *testing.T) JobStore {
+			store, err := NewFileJobStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+				t.Fatalf("없는 작업 err = %v", err)
+			}
+
+			started := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+			job := &domain.IndexJob{ID: "job-1", Collection: "whisky", Status: domain.JobRunning, Limit: 10, ModelRevision: "rev", StrategyVersion: "v2", StartedAt: started, UpdatedAt: started}
+			if err := store.Save(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+			// 같은 ID로 저장하면 덮어쓴다
+			job.Status = domain.JobInterrupted
+			job.LastCommittedID = 42
+			job.Processed = 7
+			if err := store.Save(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+			got, err := store.Get(ctx, "job-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != domain.JobInterrupted || got.LastCommittedID != 42 || got.Processed != 7 || got.Limit != 10 || got.StrategyVersion != "v2" || !got.StartedAt.Equal(started) {
+				t.Fatalf("작업 %+v", got)
+			}
+
+			watermark, err := store.Watermark(ctx, "whisky")
+			if err != nil || !watermark.IsZero() {
+				t.Fatalf("처음 기준 시각 %s, %v", watermark, err)
+			}
+			if err := store.SaveWatermark(ctx, "whisky", started); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.SaveWatermark(ctx, "other", started.Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			watermark, err = store.Watermark(ctx, "whisky")
+			if err != nil || !watermark.Equal(started) {
+				t.Fatalf("기준 시각 %s, %v", watermark, err)
+			}
+		})
+	}
+}
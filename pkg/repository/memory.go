@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/Whale0928/embedding-worker/pkg/fusion"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// MemoryStore 프로세스 내 브루트포스 VectorStore (테스트/소규모 배포용)
+type MemoryStore struct {
+	mu          sync.RWMutex
+	collections map[string]map[int64]Document
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{collections: make(map[string]map[int64]Document)}
+}
+
+// Upsert 문서 저장
+func (store *MemoryStore) Upsert(ctx context.Context, s Schema, docs []Document) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	collection, ok := store.collections[s.Name]
+	if !ok {
+		collection = make(map[int64]Document)
+		store.collections[s.Name] = collection
+	}
+	for _, doc := range docs {
+		collection[doc.ID] = doc
+	}
+	return nil
+}
+
+// Update 부분 갱신 (저장된 문서를 복사해 병합)
+func (store *MemoryStore) Update(ctx context.Context, s Schema, docs []Document) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	collection := store.collections[s.Name]
+	for _, doc := range docs {
+		stored, ok := collection[doc.ID]
+		if !ok {
+			return fmt.Errorf("문서 %d 갱신 실패: %w", doc.ID, ErrNotFound)
+		}
+		collection[doc.ID] = mergeDocument(stored, doc)
+	}
+	return nil
+}
+
+// mergeDocument 저장된 문서에 부분 갱신 내용을 덮어쓴 새 문서 (원본 맵은 건드리지 않는다)
+func mergeDocument(stored, update Document) Document {
+	merged := Document{
+		ID:      stored.ID,
+		Vectors: make(map[string][]float32, len(stored.Vectors)),
+		Sparse:  stored.Sparse,
+		Fields:  make(map[string]interface{}, len(stored.Fields)+len(update.Fields)),
+	}
+	for name, vector := range stored.Vectors {
+		merged.Vectors[name] = vector
+	}
+	for name, vector := range update.Vectors {
+		merged.Vectors[name] = vector
+	}
+	if update.Sparse != nil {
+		merged.Sparse = update.Sparse
+	}
+	for name, value := range stored.Fields {
+		merged.Fields[name] = value
+	}
+	for name, value := range update.Fields {
+		if value == nil {
+			delete(merged.Fields, name)
+			continue
+		}
+		merged.Fields[name] = value
+	}
+	return merged
+}
+
+// GetFields 지정 필드만 복사해 반환
+func (store *MemoryStore) GetFields(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string]interface{}, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	collection := store.collections[s.Name]
+	result := make(map[int64]map[string]interface{}, len(ids))
+	for _, id := range ids {
+		if doc, ok := collection[id]; ok {
+			result[id] = pickFields(doc.Fields, names)
+		}
+	}
+	return result, nil
+}
+
+// GetVectors 지정 벡터만 반환
+func (store *MemoryStore) GetVectors(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string][]float32, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	collection := store.collections[s.Name]
+	result := make(map[int64]map[string][]float32, len(ids))
+	for _, id := range ids {
+		if doc, ok := collection[id]; ok {
+			result[id] = pickVectors(doc.Vectors, names)
+		}
+	}
+	return result, nil
+}
+
+// pickVectors names에 있는 벡터만 (없는 벡터는 빠진다)
+func pickVectors(vectors map[string][]float32, names []string) map[string][]float32 {
+	picked := make(map[string][]float32, len(names))
+	for _, name := range names {
+		if vector, ok := vectors[name]; ok {
+			picked[name] = vector
+		}
+	}
+	return picked
+}
+
+// pickFields names에 있는 필드만 (없는 필드는 빠진다)
+func pickFields(fields map[string]interface{}, names []string) map[string]interface{} {
+	picked := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if value, ok := fields[name]; ok {
+			picked[name] = value
+		}
+	}
+	return picked
+}
+
+// Delete 문서 삭제
+func (store *MemoryStore) Delete(ctx context.Context, s Schema, ids []int64) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, id := range ids {
+		delete(store.collections[s.Name], id)
+	}
+	return nil
+}
+
+// Get 단일 문서 조회
+func (store *MemoryStore) Get(ctx context.Context, s Schema, id int64) (*Document, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	doc, ok := store.collections[s.Name][id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &doc, nil
+}
+
+// Search 단일 벡터 필드 kNN 검색 (코사인 유사도)
+func (store *MemoryStore) Search(ctx context.Context, s Schema, q KNNQuery) ([]Hit, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	hits := store.scoreDense(s, q.Field, q.Vector, q.Filters)
+	return paginate(hits, q.Offset, q.Limit), nil
+}
+
+// HybridSearch 필드별 후보를 뽑은 뒤 q.Fusion 방식으로 융합
+func (store *MemoryStore) HybridSearch(ctx context.Context, s Schema, q HybridQuery) ([]Hit, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	targetHits := q.TargetHits
+	if targetHits <= 0 {
+		targetHits = defaultTargetHits
+	}
+
+	legs := make(map[string][]Hit)
+	for _, field := range q.DenseFields(s) {
+		legs[field] = paginate(store.scoreDense(s, field, q.Dense, q.Filters), 0, targetHits)
+	}
+	if len(q.Sparse) > 0 {
+		legs[schema.SparseKeywords] = paginate(store.scoreSparse(s, q.Sparse, q.Filters), 0, targetHits)
+	}
+
+	return paginate(FuseHits(legs, q.Weights, q.Fusion), q.Offset, q.Limit), nil
+}
+
+// Visit 전체 문서 순회 (ID 오름차순)
+func (store *MemoryStore) Visit(ctx context.Context, s Schema, fn func(doc *Document) error) error {
+	store.mu.RLock()
+	collection := store.collections[s.Name]
+	ids := make([]int64, 0, len(collection))
+	for id := range collection {
+		ids = append(ids, id)
+	}
+	store.mu.RUnlock()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		doc, err := store.Get(ctx, s, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err := fn(doc); err != nil {
+			if errors.Is(err, ErrStopVisit) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// VisitFields 지정 필드만 복사해 ID 오름차순 순회
+func (store *MemoryStore) VisitFields(ctx context.Context, s Schema, names []string, fn func(id int64, fields map[string]interface{}) error) error {
+	store.mu.RLock()
+	collection := store.collections[s.Name]
+	ids := make([]int64, 0, len(collection))
+	for id := range collection {
+		ids = append(ids, id)
+	}
+	store.mu.RUnlock()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		store.mu.RLock()
+		doc, ok := store.collections[s.Name][id]
+		var fields map[string]interface{}
+		if ok {
+			fields = pickFields(doc.Fields, names)
+		}
+		store.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		if err := fn(id, fields); err != nil {
+			if errors.Is(err, ErrStopVisit) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *MemoryStore) scoreDense(s Schema, field string, vector []float32, filters []Filter) []Hit {
+	hits := make([]Hit, 0)
+	for _, doc := range store.collections[s.Name] {
+		stored, ok := doc.Vectors[field]
+		if !ok || !matchFilters(doc.Fields, filters) {
+			continue
+		}
+		hits = append(hits, newHit(doc, Cosine(vector, stored)))
+	}
+	sortHits(hits)
+	return hits
+}
+
+func (store *MemoryStore) scoreSparse(s Schema, sparse map[string]float32, filters []Filter) []Hit {
+	hits := make([]Hit, 0)
+	for _, doc := range store.collections[s.Name] {
+		if !matchFilters(doc.Fields, filters) {
+			continue
+		}
+		score := 0.0
+		for token, weight := range sparse {
+			score += float64(weight * doc.Sparse[token])
+		}
+		if score > 0 {
+			hits = append(hits, newHit(doc, score))
+		}
+	}
+	sortHits(hits)
+	return hits
+}
+
+func newHit(doc Document, score float64) Hit {
+	return Hit{ID: doc.ID, Score: score, Fields: doc.Fields}
+}
+
+// Cosine 코사인 유사도 (차원이 다르거나 영벡터면 0)
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// FuseHits leg별 결과를 하나의 순위로 융합 (Hit.Scores에 leg별 원점수, Fields는 비어 있지 않은 첫 값)
+// 저장소 종류와 상관없이 클라이언트에서 융합하므로 여러 쿼리/저장소의 결과를 섞어도 된다
+func FuseHits(legs map[string][]Hit, weights map[string]float64, method Fusion) []Hit {
+	items := make(map[string][]fusion.Item, len(legs))
+	fields := make(map[int64]map[string]interface{})
+	for name, hits := range legs {
+		leg := make([]fusion.Item, 0, len(hits))
+		for _, h := range hits {
+			leg = append(leg, fusion.Item{ID: h.ID, Score: h.Score})
+			if len(fields[h.ID]) == 0 {
+				fields[h.ID] = h.Fields
+			}
+		}
+		items[name] = leg
+	}
+
+	fused := fusion.Fuse(items, fusion.Options{Method: method, Weights: weights})
+	hits := make([]Hit, 0, len(fused))
+	for _, r := range fused {
+		hits = append(hits, Hit{ID: r.ID, Score: r.Score, Scores: r.Scores, Fields: fields[r.ID]})
+	}
+	return hits
+}
+
+// sortHits 점수 내림차순, 동점이면 ID 오름차순
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+}
+
+func paginate(hits []Hit, offset, limit int) []Hit {
+	if offset >= len(hits) {
+		return []Hit{}
+	}
+	end := len(hits)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return hits[offset:end]
+}
+
+// matchFilters 페이로드가 모든 필터 조건을 만족하는지 확인
+func matchFilters(fields map[string]interface{}, filters []Filter) bool {
+	for _, f := range filters {
+		if !matchFilter(fields, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFilter 조건식 하나 평가 (값이 없는 필드는 eq/in/range 모두 불일치, not이면 일치)
+func matchFilter(fields map[string]interface{}, f Filter) bool {
+	switch f.Operation() {
+	case FilterRange:
+		return matchRange(fields[f.Field], f)
+	case FilterIn:
+		for _, want := range f.Values {
+			if matchValue(fields[f.Field], want) {
+				return true
+			}
+		}
+		return false
+	case FilterNot:
+		return len(f.Children) == 1 && !matchFilter(fields, f.Children[0])
+	case FilterAnd:
+		return matchFilters(fields, f.Children)
+	case FilterOr:
+		for _, child := range f.Children {
+			if matchFilter(fields, child) {
+				return true
+			}
+		}
+		return false
+	default:
+		return matchValue(fields[f.Field], f.Value)
+	}
+}
+
+// matchRange 숫자 필드가 범위 안에 있는지 (숫자가 아니거나 값이 없으면 불일치)
+func matchRange(stored interface{}, f Filter) bool {
+	value, ok := normalizeNumber(stored).(float64)
+	if !ok {
+		return false
+	}
+	if f.Min != nil && (value < *f.Min || f.ExclusiveMin && value == *f.Min) {
+		return false
+	}
+	if f.Max != nil && (value > *f.Max || f.ExclusiveMax && value == *f.Max) {
+		return false
+	}
+	return true
+}
+
+// matchValue 스칼라는 동등 비교, 배열은 포함 여부 (숫자 타입 차이는 무시)
+func matchValue(stored, want interface{}) bool {
+	switch v := stored.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if matchValue(item, want) {
+				return true
+			}
+		}
+		return false
+	case []string:
+		for _, item := range v {
+			if matchValue(item, want) {
+				return true
+			}
+		}
+		return false
+	}
+	return fmt.Sprint(normalizeNumber(stored)) == fmt.Sprint(normalizeNumber(want))
+}
+
+// normalizeNumber 숫자 타입을 float64로 통일
+func normalizeNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case *int64:
+		if v == nil {
+			return nil
+		}
+		return float64(*v)
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return value
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// OutboxRepository embedding_outbox 테이블 소비
+// embedding_outbox는 메인 앱이 소유하므로 건드리지 않고, 재시도/격리 상태는 embedding_outbox_state에 둔다
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository 워커 상태 테이블(embedding_outbox_state)만 AutoMigrate 후 생성
+func NewOutboxRepository(db *gorm.DB) (*OutboxRepository, error) {
+	if !db.Migrator().HasTable(&domain.OutboxEvent{}) {
+		return nil, fmt.Errorf("embedding_outbox 테이블 없음 (메인 앱 마이그레이션 필요)")
+	}
+	if err := db.AutoMigrate(&domain.OutboxState{}); err != nil {
+		return nil, fmt.Errorf("outbox 상태 테이블 마이그레이션 실패: %w", err)
+	}
+	return &OutboxRepository{db: db}, nil
+}
+
+// outboxRow 이벤트 + 워커 상태 (상태 행이 없으면 NULL)
+type outboxRow struct {
+	ID         int64
+	EntityType string
+	EntityID   int64
+	Op         string
+	CreatedAt  time.Time
+	Attempts   *int
+	LastError  *string
+}
+
+// Lease 처리할 수 있는 이벤트를 ID 순으로 limit개 가져와 lease 동안 다른 워커가 가져가지 못하게 표시
+// 짧은 트랜잭션 안에서 이벤트를 잠그고(SKIP LOCKED) 상태의 attempts를 올리고 available_at을 lease 만료 시각으로 옮긴 뒤 바로 커밋한다
+// 처리는 트랜잭션 밖에서 하고 Finish로 결과를 저장한다, 처리 중 죽으면 lease가 끝난 뒤 다시 가져간다
+// 이미 maxAttempts번 가져갔는데 끝나지 않은 이벤트(처리 중 반복해서 죽는 이벤트)는 가져가지 않고 격리한다
+func (r *OutboxRepository) Lease(ctx context.Context, limit int, lease time.Duration, maxAttempts int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		query := tx.Table("embedding_outbox AS e").
+			Select("e.id, e.entity_type, e.entity_id, e.op, e.created_at, s.attempts, s.last_error").
+			Joins("LEFT JOIN embedding_outbox_state s ON s.event_id = e.id").
+			Where("s.event_id IS NULL OR (s.parked_at IS NULL AND (s.available_at IS NULL OR s.available_at <= ?))", now).
+			Order("e.id").
+			Limit(limit)
+		// SQLite(테스트)는 행 잠금 구문이 없다
+		if tx.Dialector.Name() != "sqlite" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "e"}, Options: "SKIP LOCKED"})
+		}
+		var rows []outboxRow
+		if err := query.Scan(&rows).Error; err != nil {
+			return fmt.Errorf("outbox 이벤트 조회 실패: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		leasedUntil := now.Add(lease)
+		states := make([]domain.OutboxState, 0, len(rows))
+		for _, row := range rows {
+			state := domain.OutboxState{EventID: row.ID}
+			if row.Attempts != nil {
+				state.Attempts = *row.Attempts
+			}
+			if row.LastError != nil {
+				state.LastError = *row.LastError
+			}
+			if maxAttempts > 0 && state.Attempts >= maxAttempts {
+				state.ParkedAt = &now
+				state.LastError = fmt.Sprintf("처리 중 %d번 중단됨 (lease 만료), 마지막 오류: %s", state.Attempts, state.LastError)
+				states = append(states, state)
+				continue
+			}
+
+			state.Attempts++
+			state.AvailableAt = &leasedUntil
+			states = append(states, state)
+			events = append(events, domain.OutboxEvent{
+				ID:          row.ID,
+				EntityType:  row.EntityType,
+				EntityID:    row.EntityID,
+				Op:          row.Op,
+				CreatedAt:   row.CreatedAt,
+				Attempts:    state.Attempts,
+				AvailableAt: state.AvailableAt,
+				LastError:   state.LastError,
+			})
+		}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&states).Error; err != nil {
+			return fmt.Errorf("outbox 이벤트 lease 저장 실패: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Finish 처리 결과 저장 (두 번째 짧은 트랜잭션)
+// done은 이벤트와 상태를 삭제하고, failed는 재시도 시각(available_at), 격리(parked_at), 오류를 저장한다
+func (r *OutboxRepository) Finish(ctx context.Context, done []int64, failed []domain.OutboxEvent) error {
+	if len(done) == 0 && len(failed) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(done) > 0 {
+			if err := tx.Delete(&domain.OutboxEvent{}, done).Error; err != nil {
+				return fmt.Errorf("outbox 이벤트 삭제 실패: %w", err)
+			}
+			if err := tx.Delete(&domain.OutboxState{}, done).Error; err != nil {
+				return fmt.Errorf("outbox 상태 삭제 실패: %w", err)
+			}
+		}
+		for _, event := range failed {
+			err := tx.Model(&domain.OutboxState{}).Where("event_id = ?", event.ID).Updates(map[string]interface{}{
+				"available_at": event.AvailableAt,
+				"parked_at":    event.ParkedAt,
+				"last_error":   event.LastError,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("outbox 이벤트 %d 재시도 정보 저장 실패: %w", event.ID, err)
+			}
+		}
+		return nil
+	})
+}
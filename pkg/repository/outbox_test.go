@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// newTestOutbox 메인 앱이 만든 embedding_outbox를 흉내 내고 이벤트 ID 1~n을 넣는다
+func newTestOutbox(t *testing.T, n int) (*gorm.DB, *OutboxRepository) {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&domain.OutboxEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= n; i++ {
+		event := domain.OutboxEvent{ID: int64(i), EntityType: domain.OutboxAlcohol, EntityID: int64(100 + i), Op: domain.OutboxUpsert}
+		if err := db.Create(&event).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	outbox, err := NewOutboxRepository(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, outbox
+}
+
+func outboxIDs(events []domain.OutboxEvent) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestNewOutboxRepositoryKeepsMainTable(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := NewOutboxRepository(db); err == nil {
+		t.Fatal("embedding_outbox 없을 때 오류 기대")
+	}
+
+	db, _ = newTestOutbox(t, 0)
+	columns, err := db.Migrator().ColumnTypes(&domain.OutboxEvent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name())
+	}
+	want := []string{"id", "entity_type", "entity_id", "op", "created_at"}
+	if len(names) != len(want) {
+		t.Fatalf("embedding_outbox 컬럼 %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("embedding_outbox 컬럼 %v, want %v", names, want)
+		}
+	}
+	if !db.Migrator().HasTable(&domain.OutboxState{}) {
+		t.Fatal("embedding_outbox_state 미생성")
+	}
+}
+
+func TestOutboxLeaseHidesLeasedEvents(t *testing.T) {
+	ctx := context.Background()
+	_, outbox := newTestOutbox(t, 3)
+
+	first, err := outbox.Lease(ctx, 2, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := outboxIDs(first); !equalIDs(got, []int64{1, 2}) {
+		t.Fatalf("첫 lease %v", got)
+	}
+	if first[0].Attempts != 1 || first[0].EntityID != 101 || first[0].Op != domain.OutboxUpsert {
+		t.Fatalf("lease 이벤트 %+v", first[0])
+	}
+
+	// lease 중인 이벤트는 다른 워커가 가져가지 않는다
+	second, err := outbox.Lease(ctx, 10, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := outboxIDs(second); !equalIDs(got, []int64{3}) {
+		t.Fatalf("두 번째 lease %v", got)
+	}
+}
+
+// lease가 끝나면 (처리 중 죽은 경우) 다시 가져간다
+func TestOutboxLeaseExpires(t *testing.T) {
+	ctx := context.Background()
+	_, outbox := newTestOutbox(t, 1)
+
+	if _, err := outbox.Lease(ctx, 10, -time.Second, 10); err != nil {
+		t.Fatal(err)
+	}
+	again, err := outbox.Lease(ctx, 10, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 1 || again[0].Attempts != 2 {
+		t.Fatalf("만료된 lease 재획득 %+v", again)
+	}
+}
+
+func TestOutboxFinish(t *testing.T) {
+	ctx := context.Background()
+	db, outbox := newTestOutbox(t, 3)
+
+	events, err := outbox.Lease(ctx, 10, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retryAt := time.Now().Add(-time.Second)
+	retry := events[1]
+	retry.AvailableAt = &retryAt
+	retry.LastError = "임베딩 실패"
+	parkedAt := time.Now()
+	parked := events[2]
+	parked.ParkedAt = &parkedAt
+	parked.AvailableAt = nil
+	parked.LastError = "한도 초과"
+	if err := outbox.Finish(ctx, []int64{events[0].ID}, []domain.OutboxEvent{retry, parked}); err != nil {
+		t.Fatal(err)
+	}
+
+	var remaining []int64
+	if err := db.Model(&domain.OutboxEvent{}).Order("id").Pluck("id", &remaining).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !equalIDs(remaining, []int64{2, 3}) {
+		t.Fatalf("남은 이벤트 %v", remaining)
+	}
+	var states []domain.OutboxState
+	if err := db.Order("event_id").Find(&states).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 || states[0].EventID != 2 || states[0].LastError != "임베딩 실패" || states[1].ParkedAt == nil {
+		t.Fatalf("상태 %+v", states)
+	}
+
+	// 재시도 시각이 지난 실패 이벤트만 다시 가져가고, 격리된 이벤트는 가져가지 않는다
+	again, err := outbox.Lease(ctx, 10, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 1 || again[0].ID != 2 || again[0].Attempts != 2 || again[0].LastError != "임베딩 실패" {
+		t.Fatalf("재시도 lease %+v", again)
+	}
+}
+
+func TestOutboxLeaseParksCrashLoop(t *testing.T) {
+	ctx := context.Background()
+	db, outbox := newTestOutbox(t, 1)
+
+	// 처리 중 죽어 Finish 없이 lease만 두 번 만료된 이벤트
+	for i := 0; i < 2; i++ {
+		if _, err := outbox.Lease(ctx, 10, -time.Second, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events, err := outbox.Lease(ctx, 10, time.Minute, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("한도를 넘긴 이벤트를 가져감 %+v", events)
+	}
+	var state domain.OutboxState
+	if err := db.First(&state, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if state.ParkedAt == nil || state.Attempts != 2 {
+		t.Fatalf("격리 상태 %+v", state)
+	}
+}
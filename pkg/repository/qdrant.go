@@ -0,0 +1,510 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// QdrantStore Qdrant REST API 기반 VectorStore
+// 컬렉션 이름은 Schema.DocType을 사용한다 (예: whisky_v2)
+type QdrantStore struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// qdrantPoint Qdrant 포인트 (저장/조회 공통)
+type qdrantPoint struct {
+	ID      int64                      `json:"id"`
+	Vector  map[string]json.RawMessage `json:"vector,omitempty"`
+	Payload map[string]interface{}     `json:"payload,omitempty"`
+	Score   float64                    `json:"score,omitempty"`
+}
+
+// qdrantSparse Qdrant sparse 벡터
+type qdrantSparse struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// qdrantResponse Qdrant 공통 응답
+type qdrantResponse struct {
+	Status interface{}     `json:"status"`
+	Result json.RawMessage `json:"result"`
+}
+
+func NewQdrantStore(baseURL, apiKey string) *QdrantStore {
+	return &QdrantStore{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// do 요청 실행 후 result 필드를 out에 디코딩, HTTP 상태 코드 반환
+func (store *QdrantStore) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("qdrant 요청 인코딩 실패: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, store.baseURL+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("qdrant 요청 생성 실패: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if store.apiKey != "" {
+		req.Header.Set("api-key", store.apiKey)
+	}
+
+	resp, err := store.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("qdrant 요청 실패: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("qdrant response HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+
+	var result qdrantResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return resp.StatusCode, fmt.Errorf("qdrant response JSON 디코딩 실패: %w", err)
+	}
+	if out != nil {
+		if err := json.Unmarshal(result.Result, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("qdrant result 디코딩 실패: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func collectionPath(s Schema, suffix string) string {
+	return fmt.Sprintf("/collections/%s/points%s", s.DocType, suffix)
+}
+
+// Upsert 포인트 저장
+// API: PUT /collections/{collection}/points?wait=true
+func (store *QdrantStore) Upsert(ctx context.Context, s Schema, docs []Document) error {
+	points := make([]map[string]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		vectors := make(map[string]interface{}, len(doc.Vectors)+1)
+		for name, vector := range doc.Vectors {
+			vectors[name] = vector
+		}
+		if doc.Sparse != nil {
+			vectors[schema.SparseKeywords] = toQdrantSparse(doc.Sparse)
+		}
+		points = append(points, map[string]interface{}{
+			"id":      doc.ID,
+			"vector":  vectors,
+			"payload": doc.Fields,
+		})
+	}
+
+	_, err := store.do(ctx, http.MethodPut, collectionPath(s, "?wait=true"), map[string]interface{}{"points": points}, nil)
+	return err
+}
+
+// Update 바뀐 named vector와 페이로드만 한 번의 배치 요청으로 갱신
+// API: POST /collections/{collection}/points/batch?wait=true
+func (store *QdrantStore) Update(ctx context.Context, s Schema, docs []Document) error {
+	operations := make([]map[string]interface{}, 0, len(docs))
+	vectorPoints := make([]map[string]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		vectors := make(map[string]interface{}, len(doc.Vectors)+1)
+		for name, vector := range doc.Vectors {
+			vectors[name] = vector
+		}
+		if doc.Sparse != nil {
+			vectors[schema.SparseKeywords] = toQdrantSparse(doc.Sparse)
+		}
+		if len(vectors) > 0 {
+			vectorPoints = append(vectorPoints, map[string]interface{}{"id": doc.ID, "vector": vectors})
+		}
+
+		payload := make(map[string]interface{}, len(doc.Fields))
+		removed := make([]string, 0)
+		for name, value := range doc.Fields {
+			if value == nil {
+				removed = append(removed, name)
+				continue
+			}
+			payload[name] = value
+		}
+		if len(payload) > 0 {
+			operations = append(operations, map[string]interface{}{
+				"set_payload": map[string]interface{}{"payload": payload, "points": []int64{doc.ID}},
+			})
+		}
+		if len(removed) > 0 {
+			operations = append(operations, map[string]interface{}{
+				"delete_payload": map[string]interface{}{"keys": removed, "points": []int64{doc.ID}},
+			})
+		}
+	}
+	if len(vectorPoints) > 0 {
+		operations = append(operations, map[string]interface{}{
+			"update_vectors": map[string]interface{}{"points": vectorPoints},
+		})
+	}
+	if len(operations) == 0 {
+		return nil
+	}
+
+	status, err := store.do(ctx, http.MethodPost, collectionPath(s, "/batch?wait=true"), map[string]interface{}{"operations": operations}, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return fmt.Errorf("qdrant 포인트 갱신 실패: %w", ErrNotFound)
+	}
+	return nil
+}
+
+// GetFields 페이로드에서 지정 필드만 조회 (벡터 제외)
+// API: POST /collections/{collection}/points
+func (store *QdrantStore) GetFields(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string]interface{}, error) {
+	body := map[string]interface{}{
+		"ids":          ids,
+		"with_payload": map[string]interface{}{"include": names},
+		"with_vector":  false,
+	}
+	var points []qdrantPoint
+	status, err := store.do(ctx, http.MethodPost, collectionPath(s, ""), body, &points)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("qdrant 컬렉션 없음: %s", s.DocType)
+	}
+
+	result := make(map[int64]map[string]interface{}, len(points))
+	for _, p := range points {
+		result[p.ID] = pickFields(p.Payload, names)
+	}
+	return result, nil
+}
+
+// GetVectors 지정 named vector만 한 번에 조회 (페이로드 제외)
+// API: POST /collections/{collection}/points
+func (store *QdrantStore) GetVectors(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string][]float32, error) {
+	body := map[string]interface{}{
+		"ids":          ids,
+		"with_payload": false,
+		"with_vector":  names,
+	}
+	var points []qdrantPoint
+	status, err := store.do(ctx, http.MethodPost, collectionPath(s, ""), body, &points)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("qdrant 컬렉션 없음: %s", s.DocType)
+	}
+
+	result := make(map[int64]map[string][]float32, len(points))
+	for _, p := range points {
+		doc, err := fromQdrantPoint(p)
+		if err != nil {
+			return nil, err
+		}
+		result[p.ID] = pickVectors(doc.Vectors, names)
+	}
+	return result, nil
+}
+
+// Delete 포인트 삭제
+// API: POST /collections/{collection}/points/delete?wait=true
+func (store *QdrantStore) Delete(ctx context.Context, s Schema, ids []int64) error {
+	_, err := store.do(ctx, http.MethodPost, collectionPath(s, "/delete?wait=true"), map[string]interface{}{"points": ids}, nil)
+	return err
+}
+
+// Get 단일 포인트 조회
+// API: GET /collections/{collection}/points/{id}
+func (store *QdrantStore) Get(ctx context.Context, s Schema, id int64) (*Document, error) {
+	var point qdrantPoint
+	status, err := store.do(ctx, http.MethodGet, collectionPath(s, "/"+strconv.FormatInt(id, 10)), nil, &point)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	return fromQdrantPoint(point)
+}
+
+// Search 단일 named vector kNN 검색
+// API: POST /collections/{collection}/points/query
+func (store *QdrantStore) Search(ctx context.Context, s Schema, q KNNQuery) ([]Hit, error) {
+	body := map[string]interface{}{
+		"query":        q.Vector,
+		"using":        q.Field,
+		"limit":        q.Limit,
+		"offset":       q.Offset,
+		"with_payload": true,
+	}
+	if len(q.Filters) > 0 {
+		body["filter"] = qdrantFilter(q.Filters)
+	}
+	return store.query(ctx, s, body)
+}
+
+// HybridSearch named vector + sparse를 한 번의 batch 쿼리로 따로 검색한 뒤 클라이언트에서 융합
+// Qdrant 내장 융합(rrf, dbsf)은 가중치와 필드별 점수를 돌려주지 않으므로 쓰지 않는다
+// API: POST /collections/{collection}/points/query/batch
+func (store *QdrantStore) HybridSearch(ctx context.Context, s Schema, q HybridQuery) ([]Hit, error) {
+	targetHits := q.TargetHits
+	if targetHits <= 0 {
+		targetHits = defaultTargetHits
+	}
+
+	names := make([]string, 0)
+	searches := make([]map[string]interface{}, 0)
+	for _, field := range q.DenseFields(s) {
+		names = append(names, field)
+		searches = append(searches, map[string]interface{}{"query": q.Dense, "using": field, "limit": targetHits, "with_payload": true})
+	}
+	if len(q.Sparse) > 0 {
+		names = append(names, schema.SparseKeywords)
+		searches = append(searches, map[string]interface{}{"query": toQdrantSparse(q.Sparse), "using": schema.SparseKeywords, "limit": targetHits, "with_payload": true})
+	}
+	if len(q.Filters) > 0 {
+		for _, search := range searches {
+			search["filter"] = qdrantFilter(q.Filters)
+		}
+	}
+
+	var result []struct {
+		Points []qdrantPoint `json:"points"`
+	}
+	status, err := store.do(ctx, http.MethodPost, collectionPath(s, "/query/batch"), map[string]interface{}{"searches": searches}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("qdrant 컬렉션 없음: %s", s.DocType)
+	}
+	if len(result) != len(names) {
+		return nil, fmt.Errorf("qdrant batch 결과 수 불일치: %d != %d", len(result), len(names))
+	}
+
+	legs := make(map[string][]Hit, len(names))
+	for i, name := range names {
+		hits := make([]Hit, 0, len(result[i].Points))
+		for _, p := range result[i].Points {
+			hits = append(hits, Hit{ID: p.ID, Score: p.Score, Fields: p.Payload})
+		}
+		legs[name] = hits
+	}
+	return paginate(FuseHits(legs, q.Weights, q.Fusion), q.Offset, q.Limit), nil
+}
+
+func (store *QdrantStore) query(ctx context.Context, s Schema, body map[string]interface{}) ([]Hit, error) {
+	var result struct {
+		Points []qdrantPoint `json:"points"`
+	}
+	status, err := store.do(ctx, http.MethodPost, collectionPath(s, "/query"), body, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("qdrant 컬렉션 없음: %s", s.DocType)
+	}
+
+	hits := make([]Hit, 0, len(result.Points))
+	for _, p := range result.Points {
+		hits = append(hits, Hit{ID: p.ID, Score: p.Score, Fields: p.Payload})
+	}
+	return hits, nil
+}
+
+// Visit 전체 포인트 순회, 컬렉션이 없으면 ErrNotFound (빈 순회로 보면 전부 누락/커버리지 1로 오판한다)
+// API: POST /collections/{collection}/points/scroll
+func (store *QdrantStore) Visit(ctx context.Context, s Schema, fn func(doc *Document) error) error {
+	return store.scroll(ctx, s, true, true, func(p qdrantPoint) error {
+		doc, err := fromQdrantPoint(p)
+		if err != nil {
+			return err
+		}
+		return fn(doc)
+	})
+}
+
+// VisitFields 지정 페이로드만 받아 순회 (with_vector=false)
+func (store *QdrantStore) VisitFields(ctx context.Context, s Schema, names []string, fn func(id int64, fields map[string]interface{}) error) error {
+	var withPayload interface{} = false
+	if len(names) > 0 {
+		withPayload = map[string]interface{}{"include": names}
+	}
+	return store.scroll(ctx, s, withPayload, false, func(p qdrantPoint) error {
+		return fn(p.ID, pickFields(p.Payload, names))
+	})
+}
+
+// scroll next_page_offset을 따라 전체 포인트 순회, 컬렉션이 없으면 ErrNotFound
+func (store *QdrantStore) scroll(ctx context.Context, s Schema, withPayload, withVector interface{}, fn func(p qdrantPoint) error) error {
+	var offset interface{}
+	for {
+		body := map[string]interface{}{
+			"limit":        visitPageSize,
+			"with_payload": withPayload,
+			"with_vector":  withVector,
+		}
+		if offset != nil {
+			body["offset"] = offset
+		}
+
+		var result struct {
+			Points         []qdrantPoint `json:"points"`
+			NextPageOffset interface{}   `json:"next_page_offset"`
+		}
+		status, err := store.do(ctx, http.MethodPost, collectionPath(s, "/scroll"), body, &result)
+		if err != nil {
+			return err
+		}
+		if status == http.StatusNotFound {
+			return fmt.Errorf("qdrant 컬렉션 %s: %w", s.DocType, ErrNotFound)
+		}
+
+		for _, p := range result.Points {
+			if err := fn(p); err != nil {
+				if errors.Is(err, ErrStopVisit) {
+					return nil
+				}
+				return err
+			}
+		}
+		if result.NextPageOffset == nil {
+			return nil
+		}
+		offset = result.NextPageOffset
+	}
+}
+
+// qdrantFilter 필터를 must 조건으로 변환 (배열 페이로드는 Qdrant가 포함 여부로 매칭)
+func qdrantFilter(filters []Filter) map[string]interface{} {
+	return map[string]interface{}{"must": qdrantConditions(filters)}
+}
+
+func qdrantConditions(filters []Filter) []map[string]interface{} {
+	conditions := make([]map[string]interface{}, 0, len(filters))
+	for _, f := range filters {
+		conditions = append(conditions, qdrantCondition(f))
+	}
+	return conditions
+}
+
+// qdrantCondition 조건식 → Qdrant 조건 (and/or/not은 중첩 filter)
+func qdrantCondition(f Filter) map[string]interface{} {
+	switch f.Operation() {
+	case FilterRange:
+		bounds := make(map[string]interface{}, 2)
+		if f.Min != nil {
+			key := "gte"
+			if f.ExclusiveMin {
+				key = "gt"
+			}
+			bounds[key] = *f.Min
+		}
+		if f.Max != nil {
+			key := "lte"
+			if f.ExclusiveMax {
+				key = "lt"
+			}
+			bounds[key] = *f.Max
+		}
+		return map[string]interface{}{"key": f.Field, "range": bounds}
+	case FilterIn:
+		return map[string]interface{}{"key": f.Field, "match": map[string]interface{}{"any": f.Values}}
+	case FilterNot:
+		return map[string]interface{}{"must_not": qdrantConditions(f.Children)}
+	case FilterAnd:
+		return map[string]interface{}{"must": qdrantConditions(f.Children)}
+	case FilterOr:
+		return map[string]interface{}{"should": qdrantConditions(f.Children)}
+	default:
+		return map[string]interface{}{"key": f.Field, "match": map[string]interface{}{"value": f.Value}}
+	}
+}
+
+// toQdrantSparse 토큰 → 가중치 맵을 Qdrant sparse 벡터로 변환
+// 토큰이 숫자(토크나이저 ID)면 그대로, 아니면 FNV 해시를 인덱스로 사용
+// 가중치는 임베더가 만든 값 그대로다: sparse_linear.pt 없이 만든 토큰 빈도는 Python이 넣은 BGE-M3 가중치와
+// 척도가 달라 같은 컬렉션에 섞으면 안 된다 (임베더 시작 시 [WARN], 재색인 필요)
+func toQdrantSparse(sparse map[string]float32) qdrantSparse {
+	merged := make(map[uint32]float32, len(sparse))
+	for token, weight := range sparse {
+		merged[sparseIndex(token)] += weight
+	}
+
+	result := qdrantSparse{
+		Indices: make([]uint32, 0, len(merged)),
+		Values:  make([]float32, 0, len(merged)),
+	}
+	for index, weight := range merged {
+		result.Indices = append(result.Indices, index)
+		result.Values = append(result.Values, weight)
+	}
+	return result
+}
+
+func sparseIndex(token string) uint32 {
+	if index, err := strconv.ParseUint(token, 10, 32); err == nil {
+		return uint32(index)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(token))
+	return h.Sum32()
+}
+
+func fromQdrantPoint(p qdrantPoint) (*Document, error) {
+	doc := &Document{
+		ID:      p.ID,
+		Vectors: make(map[string][]float32),
+		Fields:  p.Payload,
+	}
+	if doc.Fields == nil {
+		doc.Fields = make(map[string]interface{})
+	}
+
+	for name, raw := range p.Vector {
+		if name == schema.SparseKeywords {
+			var sparse qdrantSparse
+			if err := json.Unmarshal(raw, &sparse); err != nil {
+				return nil, fmt.Errorf("qdrant sparse 벡터 디코딩 실패: %w", err)
+			}
+			doc.Sparse = make(map[string]float32, len(sparse.Indices))
+			for i, index := range sparse.Indices {
+				doc.Sparse[strconv.FormatUint(uint64(index), 10)] = sparse.Values[i]
+			}
+			continue
+		}
+
+		var vector []float32
+		if err := json.Unmarshal(raw, &vector); err != nil {
+			return nil, fmt.Errorf("qdrant 벡터 %s 디코딩 실패: %w", name, err)
+		}
+		doc.Vectors[name] = vector
+	}
+	return doc, nil
+}
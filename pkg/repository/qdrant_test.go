@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestQdrantVisitMissingCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/collections/whisky_typo/points/scroll" {
+			t.Errorf("경로 %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":{"error":"Not found: Collection whisky_typo doesn't exist!"}}`))
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(server.URL, "")
+	visited := 0
+	err := store.Visit(context.Background(), Schema{Name: "whisky", DocType: "whisky_typo"}, func(doc *Document) error {
+		visited++
+		return nil
+	})
+	if !errors.Is(err, ErrNotFound) || visited != 0 {
+		t.Fatalf("err = %v, visited %d", err, visited)
+	}
+}
+
+func TestQdrantVisitPages(t *testing.T) {
+	pages := []string{
+		`{"status":"ok","result":{"points":[{"id":1,"payload":{"kor_name":"a"},"vector":{"flavor":[1,0]}}],"next_page_offset":2}}`,
+		`{"status":"ok","result":{"points":[{"id":2,"payload":{"kor_name":"b"},"vector":{"flavor":[0,1]}}],"next_page_offset":null}}`,
+	}
+	var offsets []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		offsets = append(offsets, body["offset"])
+		_, _ = w.Write([]byte(pages[len(offsets)-1]))
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(server.URL, "")
+	var ids []int64
+	err := store.Visit(context.Background(), Schema{DocType: "whisky"}, func(doc *Document) error {
+		ids = append(ids, doc.ID)
+		if len(doc.Vectors["flavor"]) != 2 {
+			t.Errorf("문서 %d 벡터 %v", doc.ID, doc.Vectors)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2}) || offsets[0] != nil || offsets[1] != 2.0 {
+		t.Fatalf("ids %v, offsets %v", ids, offsets)
+	}
+}
+
+func TestQdrantVisitFieldsSkipsVectors(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"status":"ok","result":{"points":[{"id":3,"payload":{"flavor_hash":"h"}}],"next_page_offset":null}}`))
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(server.URL, "")
+	var got map[string]interface{}
+	err := store.VisitFields(context.Background(), Schema{DocType: "whisky"}, []string{"flavor_hash"}, func(id int64, fields map[string]interface{}) error {
+		if id != 3 {
+			t.Errorf("id %d", id)
+		}
+		got = fields
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["flavor_hash"] != "h" {
+		t.Fatalf("필드 %v", got)
+	}
+	// ID만 순회하면 페이로드도 받지 않는다
+	if err := store.VisitFields(context.Background(), Schema{DocType: "whisky"}, nil, func(int64, map[string]interface{}) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	include, _ := bodies[0]["with_payload"].(map[string]interface{})
+	if bodies[0]["with_vector"] != false || include == nil || bodies[1]["with_payload"] != false || bodies[1]["with_vector"] != false {
+		t.Fatalf("scroll 요청 %v", bodies)
+	}
+}
+
+func TestQdrantGetVectorsBatch(t *testing.T) {
+	requests := 0
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/collections/whisky/points" {
+			t.Errorf("경로 %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"status":"ok","result":[{"id":1,"vector":{"flavor":[1,0]}},{"id":3,"vector":{"flavor":[0,1]}}]}`))
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(server.URL, "")
+	vectors, err := store.GetVectors(context.Background(), Schema{DocType: "whisky"}, []int64{1, 2, 3}, []string{"flavor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 없는 문서(2)는 빠지고, 요청은 한 번
+	if requests != 1 || len(vectors) != 2 || len(vectors[1]["flavor"]) != 2 || vectors[3]["flavor"][1] != 1 {
+		t.Fatalf("요청 %d, 벡터 %v", requests, vectors)
+	}
+	if body["with_payload"] != false || len(body["ids"].([]interface{})) != 3 || body["with_vector"].([]interface{})[0] != "flavor" {
+		t.Fatalf("요청 본문 %v", body)
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound 문서 없음
+	ErrNotFound = errors.New("document not found")
+	// ErrStopVisit Visit 콜백에서 반환하면 순회를 중단한다 (Visit은 nil 반환)
+	ErrStopVisit = errors.New("stop visit")
+)
+
+// VectorStore 벡터 저장소 공통 인터페이스 (Vespa, Qdrant, in-memory)
+type VectorStore interface {
+	// Upsert 문서 전체 저장 (있으면 덮어쓰기)
+	Upsert(ctx context.Context, s Schema, docs []Document) error
+	// Update 부분 갱신: 문서에 담긴 벡터/sparse/필드만 덮어쓰고 나머지는 유지 (필드 값이 nil이면 삭제)
+	// 없는 문서면 오류
+	Update(ctx context.Context, s Schema, docs []Document) error
+	// GetFields 여러 문서의 지정한 필드만 조회 (없는 문서는 결과에서 빠진다)
+	GetFields(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string]interface{}, error)
+	// GetVectors 여러 문서의 지정한 dense 벡터만 조회 (없는 문서는 결과에서 빠진다)
+	GetVectors(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string][]float32, error)
+	// Delete 문서 삭제 (없는 ID는 무시)
+	Delete(ctx context.Context, s Schema, ids []int64) error
+	// Get 단일 문서 조회, 없으면 ErrNotFound
+	Get(ctx context.Context, s Schema, id int64) (*Document, error)
+	// Search 단일 벡터 필드 kNN 검색
+	Search(ctx context.Context, s Schema, q KNNQuery) ([]Hit, error)
+	// HybridSearch 여러 dense 필드 + sparse 융합 검색
+	HybridSearch(ctx context.Context, s Schema, q HybridQuery) ([]Hit, error)
+	// Visit 전체 문서 순회
+	Visit(ctx context.Context, s Schema, fn func(doc *Document) error) error
+	// VisitFields 전체 문서를 ID와 지정한 필드만 순회 (벡터는 읽지 않는다, names가 비면 ID만)
+	VisitFields(ctx context.Context, s Schema, names []string, fn func(id int64, fields map[string]interface{}) error) error
+}
+
+// Document 벡터 저장소 문서
+type Document struct {
+	ID int64
+	// Vectors named dense 벡터 (flavor, identity, ...)
+	Vectors map[string][]float32
+	// Sparse 토큰 → 가중치 (keywords)
+	Sparse map[string]float32
+	// Fields 페이로드 (rag_context, 필터 메타데이터)
+	Fields map[string]interface{}
+}
+
+// KNNQuery 단일 벡터 필드 kNN 검색 파라미터
+type KNNQuery struct {
+	Field   string
+	Vector  []float32
+	Filters []Filter
+	Offset  int
+	Limit   int
+}
+
+// Hit 검색 결과 단건
+type Hit struct {
+	ID    int64
+	Score float64
+	// Scores 필드별 점수 (하이브리드 검색에서 매칭된 벡터 필드)
+	Scores map[string]float64
+	Fields map[string]interface{}
+}
+
+// Backend 벡터 저장소 종류
+type Backend string
+
+const (
+	BackendVespa  Backend = "vespa"
+	BackendQdrant Backend = "qdrant"
+	BackendMemory Backend = "memory"
+	// BackendEmbedded 프로세스 내 HNSW + 디스크 스냅샷 (standalone)
+	BackendEmbedded Backend = "embedded"
+)
+
+// ParseBackend 설정 문자열을 Backend로 변환
+func ParseBackend(value string) (Backend, error) {
+	switch b := Backend(value); b {
+	case BackendVespa, BackendQdrant, BackendMemory, BackendEmbedded:
+		return b, nil
+	default:
+		return "", fmt.Errorf("지원하지 않는 벡터 저장소: %q", value)
+	}
+}
@@ -1,16 +1,34 @@
 package repository
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Schema Vespa 문서 스키마 (namespace/docType)
 type Schema struct {
+	// Name 컬렉션 이름 (API 경로의 {collection})
+	Name string
+	// Alias 블루/그린 버전 그룹 이름 (비우면 단독 컬렉션)
+	Alias     string
 	Namespace string
 	DocType   string
+	Cluster   string
+	Vectors   []VectorField
+}
+
+// VectorField dense 벡터 필드 이름과 차원
+type VectorField struct {
+	Name      string
+	Dimension int
 }
 
 // VespaClient Vespa Document API 클라이언트
@@ -39,34 +57,182 @@ func NewVespaClient(baseURL string) *VespaClient {
 	}
 }
 
-// ListDocuments 저장된 문서 목록 조회 (Visit API)
-func (client *VespaClient) ListDocuments(schema Schema, count int) (*VisitResponse, error) {
-	url := fmt.Sprintf("%s/document/v1/%s/%s/docid?wantedDocumentCount=%d", client.baseURL, schema.Namespace, schema.DocType, count)
-	resp, err := client.httpClient.Get(url)
+// documentPath 문서 경로 (/document/v1/{namespace}/{docType}/docid[/{id}])
+func documentPath(schema Schema, id string) string {
+	path := fmt.Sprintf("/document/v1/%s/%s/docid", schema.Namespace, schema.DocType)
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return path
+}
+
+// do 요청 실행 후 JSON 응답을 out에 디코딩, HTTP 상태 코드 반환
+func (client *VespaClient) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("vespa client 요청 인코딩 실패: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, client.baseURL+path, reader)
 	if err != nil {
-		return nil, fmt.Errorf("vespa client  요청 실패: %w", err)
+		return 0, fmt.Errorf("vespa client 요청 생성 실패: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("vespa client  요청 실패: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("vespa client response HTTP %d: %s", resp.StatusCode, resp.Status)
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("vespa client response HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("vespa client response JSON 디코딩 실패: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// Visit 문서 한 페이지 조회 (Visit API), continuation이 비어 있으면 처음부터
+// 문서 타입이 없으면 ErrNotFound
+func (client *VespaClient) Visit(ctx context.Context, schema Schema, continuation string, count int) (*VisitResponse, error) {
+	return client.visit(ctx, schema, continuation, count, "")
+}
+
+// VisitFields id와 지정 필드만 담은 문서 한 페이지 조회 (fieldSet={docType}:id,{fields}, 텐서를 받지 않는다)
+func (client *VespaClient) VisitFields(ctx context.Context, schema Schema, continuation string, count int, fields []string) (*VisitResponse, error) {
+	fieldSet := schema.DocType + ":" + strings.Join(append([]string{"id"}, fields...), ",")
+	return client.visit(ctx, schema, continuation, count, fieldSet)
+}
+
+func (client *VespaClient) visit(ctx context.Context, schema Schema, continuation string, count int, fieldSet string) (*VisitResponse, error) {
+	query := url.Values{}
+	query.Set("wantedDocumentCount", strconv.Itoa(count))
+	query.Set("format.tensors", "short-value")
+	if fieldSet != "" {
+		query.Set("fieldSet", fieldSet)
+	}
+	if schema.Cluster != "" {
+		query.Set("cluster", schema.Cluster)
+	}
+	if continuation != "" {
+		query.Set("continuation", continuation)
 	}
 
 	var result VisitResponse
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	status, err := client.do(ctx, http.MethodGet, documentPath(schema, "")+"?"+query.Encode(), nil, &result)
 	if err != nil {
-		return nil, fmt.Errorf("vespa client response JSON 디코딩 실패: %w", err)
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("vespa 문서 타입 %s/%s: %w", schema.Namespace, schema.DocType, ErrNotFound)
 	}
 	return &result, nil
 }
 
-// GetDocument 단일 문서 조회
+// ListDocuments 저장된 문서 목록 조회 (Visit API)
+func (client *VespaClient) ListDocuments(ctx context.Context, schema Schema, count int) (*VisitResponse, error) {
+	return client.Visit(ctx, schema, "", count)
+}
+
+// GetDocument 단일 문서 조회, 없으면 ErrNotFound
 // API: GET /document/v1/{namespace}/{docType}/docid/{id}
-func (client *VespaClient) GetDocument(schema Schema, id string) (*VespaDocument, error) {
-	// TODO: ListDocuments 참고해서 구현
-	return nil, fmt.Errorf("not implemented")
+func (client *VespaClient) GetDocument(ctx context.Context, schema Schema, id string) (*VespaDocument, error) {
+	var result VespaDocument
+	status, err := client.do(ctx, http.MethodGet, documentPath(schema, id)+"?format.tensors=short-value", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	return &result, nil
+}
+
+// PutDocument 문서 저장 (전체 덮어쓰기)
+// API: POST /document/v1/{namespace}/{docType}/docid/{id}
+func (client *VespaClient) PutDocument(ctx context.Context, schema Schema, id string, fields map[string]interface{}) error {
+	body := map[string]interface{}{"fields": fields}
+	status, err := client.do(ctx, http.MethodPost, documentPath(schema, id), body, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return fmt.Errorf("vespa client: 문서 타입 %s/%s 없음", schema.Namespace, schema.DocType)
+	}
+	return nil
+}
+
+// GetDocumentFields 단일 문서의 지정 필드만 조회, 없으면 ErrNotFound
+// API: GET /document/v1/{namespace}/{docType}/docid/{id}?fieldSet={docType}:{fields}
+func (client *VespaClient) GetDocumentFields(ctx context.Context, schema Schema, id string, fields []string) (*VespaDocument, error) {
+	query := url.Values{}
+	query.Set("format.tensors", "short-value")
+	query.Set("fieldSet", schema.DocType+":"+strings.Join(fields, ","))
+
+	var result VespaDocument
+	status, err := client.do(ctx, http.MethodGet, documentPath(schema, id)+"?"+query.Encode(), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	return &result, nil
 }
 
-func (client *VespaClient) GetAllDocuments(schema Schema) ([]VisitResponse, error) {
-	return nil, nil
+// UpdateDocument 필드별 assign 부분 갱신 (값이 nil이면 필드 삭제), 없는 문서면 ErrNotFound
+// API: PUT /document/v1/{namespace}/{docType}/docid/{id}
+func (client *VespaClient) UpdateDocument(ctx context.Context, schema Schema, id string, fields map[string]interface{}) error {
+	assigns := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		assigns[name] = map[string]interface{}{"assign": value}
+	}
+	body := map[string]interface{}{"fields": assigns}
+	status, err := client.do(ctx, http.MethodPut, documentPath(schema, id), body, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteDocument 문서 삭제 (없는 문서도 성공)
+// API: DELETE /document/v1/{namespace}/{docType}/docid/{id}
+func (client *VespaClient) DeleteDocument(ctx context.Context, schema Schema, id string) error {
+	_, err := client.do(ctx, http.MethodDelete, documentPath(schema, id), nil, nil)
+	return err
+}
+
+// GetAllDocuments continuation을 따라 전체 문서 조회
+func (client *VespaClient) GetAllDocuments(ctx context.Context, schema Schema) ([]VisitResponse, error) {
+	pages := make([]VisitResponse, 0)
+	continuation := ""
+	for {
+		page, err := client.Visit(ctx, schema, continuation, 100)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, *page)
+		if page.Continuation == "" {
+			return pages, nil
+		}
+		continuation = page.Continuation
+	}
 }
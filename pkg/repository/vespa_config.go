@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// 로컬 단일 애플리케이션 배포 경로 (tenant/application/instance 모두 default)
+const vespaApplicationPath = "/application/v2/tenant/default/application/default/environment/prod/region/default/instance/default"
+
+// VespaConfigClient Vespa Config Server 클라이언트 (배포된 애플리케이션 조회)
+type VespaConfigClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// vespaServices services.xml 중 content 클러스터 부분
+type vespaServices struct {
+	Contents []struct {
+		ID        string `xml:"id,attr"`
+		Documents []struct {
+			Type string `xml:"type,attr"`
+		} `xml:"documents>document"`
+	} `xml:"content"`
+}
+
+func NewVespaConfigClient(baseURL string) *VespaConfigClient {
+	return &VespaConfigClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// getContent 배포된 애플리케이션 패키지의 파일 내용 조회
+// API: GET /application/v2/.../content/{path}
+func (client *VespaConfigClient) getContent(path string) ([]byte, error) {
+	url := fmt.Sprintf("%s%s/content/%s", client.baseURL, vespaApplicationPath, path)
+	resp, err := client.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("vespa config server 요청 실패: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vespa config server response HTTP %d: %s (%s)", resp.StatusCode, resp.Status, path)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Validate 컬렉션의 content 클러스터, 문서 타입, 벡터 필드가 배포되어 있는지 검증
+func (client *VespaConfigClient) Validate(s Schema) error {
+	raw, err := client.getContent("services.xml")
+	if err != nil {
+		return err
+	}
+
+	var services vespaServices
+	if err := xml.Unmarshal(raw, &services); err != nil {
+		return fmt.Errorf("services.xml 파싱 실패: %w", err)
+	}
+
+	found := false
+	for _, content := range services.Contents {
+		if content.ID != s.Cluster {
+			continue
+		}
+		for _, doc := range content.Documents {
+			if doc.Type == s.DocType {
+				found = true
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("컬렉션 %q: content 클러스터 %q에 문서 타입 %q 없음", s.Name, s.Cluster, s.DocType)
+	}
+
+	sd, err := client.getContent(fmt.Sprintf("schemas/%s.sd", s.DocType))
+	if err != nil {
+		return err
+	}
+	for _, v := range s.Vectors {
+		pattern := fmt.Sprintf(`field\s+%s\s+type\s+tensor<float>\(x\[%d\]\)`, regexp.QuoteMeta(v.Name), v.Dimension)
+		if !regexp.MustCompile(pattern).Match(sd) {
+			return fmt.Errorf("컬렉션 %q: 벡터 필드 %s (x[%d]) 없음", s.Name, v.Name, v.Dimension)
+		}
+	}
+	return nil
+}
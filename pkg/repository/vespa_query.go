@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Whale0928/embedding-worker/pkg/fusion"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// Fusion 하이브리드 검색 융합 방식
+type Fusion = fusion.Method
+
+const (
+	FusionRRF    = fusion.RRF
+	FusionLinear = fusion.Linear
+	FusionDBSF   = fusion.DBSF
+	FusionZScore = fusion.ZScore
+)
+
+// ParseFusion 설정 문자열을 Fusion으로 변환
+func ParseFusion(value string) (Fusion, error) {
+	return fusion.Parse(value)
+}
+
+// defaultTargetHits nearestNeighbor 연산자별 후보 수
+const defaultTargetHits = 100
+
+// HybridQuery 4개 named vector + sparse 하이브리드 검색 파라미터
+type HybridQuery struct {
+	Dense  []float32
+	Sparse map[string]float32
+	// Weights 필드별 가중치 (없으면 1.0)
+	Weights map[string]float64
+	// Fields nearestNeighbor 대상 dense 필드 (없으면 전체)
+	Fields     []string
+	Filters    []Filter
+	Fusion     Fusion
+	TargetHits int
+	Offset     int
+	Limit      int
+}
+
+// SearchResponse Vespa Query API 응답
+type SearchResponse struct {
+	Root struct {
+		Fields struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"fields"`
+		Children []SearchHit `json:"children"`
+		Errors   []struct {
+			Code    int    `json:"code"`
+			Summary string `json:"summary"`
+			Message string `json:"message"`
+		} `json:"errors,omitempty"`
+	} `json:"root"`
+}
+
+// SearchHit 검색 결과 단건
+type SearchHit struct {
+	ID        string                 `json:"id"`
+	Relevance float64                `json:"relevance"`
+	Fields    map[string]interface{} `json:"fields"`
+}
+
+// MatchFeatures 랭킹 프로파일의 match-features 값
+func (h SearchHit) MatchFeatures() map[string]float64 {
+	features := make(map[string]float64)
+	raw, ok := h.Fields["matchfeatures"].(map[string]interface{})
+	if !ok {
+		return features
+	}
+	for name, value := range raw {
+		if f, ok := value.(float64); ok {
+			features[name] = f
+		}
+	}
+	return features
+}
+
+// RankProfile 융합 방식에 대응하는 랭킹 프로파일 이름
+// 프로파일이 없는 방식(dbsf, zscore)은 hybrid_linear의 match-features로 클라이언트에서 융합한다
+func (q HybridQuery) RankProfile() string {
+	if q.Fusion == FusionRRF || q.Fusion == "" {
+		return schema.RankProfileHybridRRF
+	}
+	return schema.RankProfileHybridLinear
+}
+
+// ServerFusion Vespa global-phase에서 융합할 수 있는 방식인지
+func (q HybridQuery) ServerFusion() bool {
+	return q.Fusion == FusionRRF || q.Fusion == FusionLinear || q.Fusion == ""
+}
+
+// DenseFields 검색 대상 dense 필드 (지정하지 않으면 컬렉션의 전체 벡터 필드)
+func (q HybridQuery) DenseFields(s Schema) []string {
+	if len(q.Fields) > 0 {
+		return q.Fields
+	}
+	fields := make([]string, 0, len(s.Vectors))
+	for _, v := range s.Vectors {
+		fields = append(fields, v.Name)
+	}
+	return fields
+}
+
+// YQL nearestNeighbor 연산자와 sparse 토큰 wand를 OR로 결합한 쿼리 생성
+// wand가 없으면 keywords는 kNN 후보의 점수에만 쓰여, kNN targetHits 밖의 정확한 키워드 일치를 놓친다
+func (q HybridQuery) YQL(s Schema) string {
+	targetHits := q.TargetHits
+	if targetHits <= 0 {
+		targetHits = defaultTargetHits
+	}
+	if targetHits < q.Offset+q.Limit {
+		targetHits = q.Offset + q.Limit
+	}
+
+	fields := q.DenseFields(s)
+	clauses := make([]string, 0, len(fields))
+	for _, field := range fields {
+		clauses = append(clauses, fmt.Sprintf("({targetHits:%d}nearestNeighbor(%s, %s))", targetHits, field, schema.QueryDense))
+	}
+	if tokens := wandTokens(q.Sparse); tokens != "" {
+		clauses = append(clauses, fmt.Sprintf("({targetHits:%d}wand(%s, %s))", targetHits, schema.SparseTokens, tokens))
+	}
+	where := "(" + strings.Join(clauses, " or ") + ")"
+	if len(q.Filters) > 0 {
+		where += " and " + yqlFilters(q.Filters, true)
+	}
+	return fmt.Sprintf("select * from %s where %s", s.DocType, where)
+}
+
+// Body Query API 요청 바디 생성
+func (q HybridQuery) Body(s Schema) map[string]interface{} {
+	body := map[string]interface{}{
+		"yql":                        q.YQL(s),
+		"ranking.profile":            q.RankProfile(),
+		"hits":                       q.Limit,
+		"offset":                     q.Offset,
+		inputKey(schema.QueryDense):  q.Dense,
+		inputKey(schema.QuerySparse): sparseTensorLiteral(q.Sparse),
+	}
+	for field, weight := range q.Weights {
+		body[inputKey(schema.WeightInput(field))] = weight
+	}
+	preFilter(body, q.Filters)
+	return body
+}
+
+// YQL 단일 필드 nearestNeighbor 쿼리 생성
+func (q KNNQuery) YQL(s Schema) string {
+	targetHits := q.Offset + q.Limit
+	if targetHits < defaultTargetHits {
+		targetHits = defaultTargetHits
+	}
+	where := fmt.Sprintf("{targetHits:%d}nearestNeighbor(%s, %s)", targetHits, q.Field, schema.QueryDense)
+	if len(q.Filters) > 0 {
+		where += " and " + yqlFilters(q.Filters, true)
+	}
+	return fmt.Sprintf("select * from %s where %s", s.DocType, where)
+}
+
+// Body Query API 요청 바디 생성
+func (q KNNQuery) Body(s Schema) map[string]interface{} {
+	body := map[string]interface{}{
+		"yql":                       q.YQL(s),
+		"ranking.profile":           schema.KNNRankProfile(q.Field),
+		"hits":                      q.Limit,
+		"offset":                    q.Offset,
+		inputKey(schema.QueryDense): q.Vector,
+	}
+	preFilter(body, q.Filters)
+	return body
+}
+
+// preFilter 필터가 있으면 nearestNeighbor를 항상 사전 필터링으로 실행
+// (사후 필터링은 targetHits 중 조건에 맞는 것만 남아 limit보다 적게 반환될 수 있다)
+func preFilter(body map[string]interface{}, filters []Filter) {
+	if len(filters) > 0 {
+		body["ranking.matching.postFilterThreshold"] = 1.0
+	}
+}
+
+// yqlFilters 필터 조건을 AND로 결합, not은 and 안에서 !(...)로 쓴다
+// Vespa는 부정만 있는 and를 받지 않으므로 positive(같은 and에 다른 긍정 조건이 있는지)가 false이고
+// 모든 조건이 not이면 true를 앞에 붙인다
+func yqlFilters(filters []Filter, positive bool) string {
+	clauses := make([]string, 0, len(filters)+1)
+	for _, f := range filters {
+		if f.Operation() == FilterNot {
+			clauses = append(clauses, "!("+yqlFilter(f.Children[0])+")")
+			continue
+		}
+		positive = true
+		clauses = append(clauses, yqlFilter(f))
+	}
+	if !positive {
+		clauses = append([]string{"true"}, clauses...)
+	}
+	return strings.Join(clauses, " and ")
+}
+
+// yqlFilter 조건식 → YQL
+// 문자열은 contains, 숫자/불리언은 = 비교, in은 OR, 범위는 > / >= / < / <= 비교
+// not은 and의 일부로만 쓸 수 있어 or 안이나 not 안에서는 (true and !(...))로 바꾼다
+func yqlFilter(f Filter) string {
+	switch f.Operation() {
+	case FilterRange:
+		clauses := make([]string, 0, 2)
+		if f.Min != nil {
+			op := ">="
+			if f.ExclusiveMin {
+				op = ">"
+			}
+			clauses = append(clauses, fmt.Sprintf("%s %s %s", f.Field, op, strconv.FormatFloat(*f.Min, 'g', -1, 64)))
+		}
+		if f.Max != nil {
+			op := "<="
+			if f.ExclusiveMax {
+				op = "<"
+			}
+			clauses = append(clauses, fmt.Sprintf("%s %s %s", f.Field, op, strconv.FormatFloat(*f.Max, 'g', -1, 64)))
+		}
+		return "(" + strings.Join(clauses, " and ") + ")"
+	case FilterIn:
+		clauses := make([]string, 0, len(f.Values))
+		for _, v := range f.Values {
+			clauses = append(clauses, yqlMatch(f.Field, v))
+		}
+		return "(" + strings.Join(clauses, " or ") + ")"
+	case FilterNot:
+		return "(" + yqlFilters([]Filter{f}, false) + ")"
+	case FilterAnd:
+		return "(" + yqlFilters(f.Children, false) + ")"
+	case FilterOr:
+		clauses := make([]string, 0, len(f.Children))
+		for _, child := range f.Children {
+			clauses = append(clauses, yqlFilter(child))
+		}
+		return "(" + strings.Join(clauses, " or ") + ")"
+	default:
+		return yqlMatch(f.Field, f.Value)
+	}
+}
+
+// yqlMatch 값 하나 일치 조건
+func yqlMatch(field string, value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%s contains %s", field, strconv.Quote(v))
+	case float64:
+		return fmt.Sprintf("%s = %s", field, strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Sprintf("%s = %v", field, v)
+	}
+}
+
+func inputKey(name string) string {
+	return fmt.Sprintf("input.query(%s)", name)
+}
+
+// wandTokens sparse 가중치 → wand 가중치 집합 리터럴 ({"123":250,...}), 가중치는 SparseTokenScale배 정수
+func wandTokens(sparse map[string]float32) string {
+	weights := sparseTokenWeights(sparse)
+	tokens := make([]string, 0, len(weights))
+	for token := range weights {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	cells := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		cells = append(cells, fmt.Sprintf("%s:%d", strconv.Quote(token), weights[token]))
+	}
+	if len(cells) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(cells, ",") + "}"
+}
+
+// sparseTokenWeights sparse 가중치 → weightedset 정수 가중치 (0 이하는 빼고, 반올림해 0이 되면 1)
+func sparseTokenWeights(sparse map[string]float32) map[string]int {
+	weights := make(map[string]int, len(sparse))
+	for token, weight := range sparse {
+		if weight <= 0 {
+			continue
+		}
+		weights[token] = max(1, int(math.Round(float64(weight)*schema.SparseTokenScale)))
+	}
+	return weights
+}
+
+// sparseTensorLiteral map → mapped tensor 리터럴 ({{token:"a"}:0.5,...})
+func sparseTensorLiteral(sparse map[string]float32) string {
+	tokens := make([]string, 0, len(sparse))
+	for token := range sparse {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	cells := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		cells = append(cells, fmt.Sprintf("{token:%s}:%s", strconv.Quote(token), strconv.FormatFloat(float64(sparse[token]), 'g', -1, 32)))
+	}
+	return "{" + strings.Join(cells, ",") + "}"
+}
+
+// Search Query API 호출
+// API: POST /search/
+func (client *VespaClient) Search(ctx context.Context, body map[string]interface{}) (*SearchResponse, error) {
+	var result SearchResponse
+	if _, err := client.do(ctx, http.MethodPost, "/search/", body, &result); err != nil {
+		return nil, fmt.Errorf("vespa search 실패: %w", err)
+	}
+	if len(result.Root.Errors) > 0 {
+		return nil, fmt.Errorf("vespa search 실패: %s", result.Root.Errors[0].Message)
+	}
+	return &result, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func testWhiskySchema() Schema {
+	return Schema{
+		Name:      "whisky",
+		Namespace: "whisky",
+		DocType:   "whisky",
+		Vectors:   []VectorField{{Name: "flavor", Dimension: 3}, {Name: "identity", Dimension: 3}},
+	}
+}
+
+func TestHybridQueryYQLAddsWandLeg(t *testing.T) {
+	q := HybridQuery{
+		Dense:  []float32{1, 0, 0},
+		Sparse: map[string]float32{"42": 0.25, "7": 2, "9": 0, "11": 0.0001},
+		Limit:  10,
+	}
+	got := q.YQL(testWhiskySchema())
+	want := `select * from whisky where (({targetHits:100}nearestNeighbor(flavor, q)) or ({targetHits:100}nearestNeighbor(identity, q)) or ({targetHits:100}wand(keyword_tokens, {"11":1,"42":250,"7":2000})))`
+	if got != want {
+		t.Fatalf("YQL\n got: %s\nwant: %s", got, want)
+	}
+
+	q.Sparse = nil
+	if got := q.YQL(testWhiskySchema()); strings.Contains(got, "wand") {
+		t.Fatalf("sparse 없는 쿼리에 wand: %s", got)
+	}
+}
+
+func TestYQLFilterNegation(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{
+			name:   "최상위 not은 and의 일부",
+			filter: Not(Eq("type", "Blend")),
+			want:   `!(type contains "Blend")`,
+		},
+		{
+			name:   "or 안의 not은 true and로 감싼다",
+			filter: Or(Eq("region_id", float64(1)), Not(Eq("type", "Blend"))),
+			want:   `(region_id = 1 or (true and !(type contains "Blend")))`,
+		},
+		{
+			name:   "긍정 조건이 있는 and",
+			filter: And(Eq("region_id", float64(1)), Not(Eq("type", "Blend"))),
+			want:   `(region_id = 1 and !(type contains "Blend"))`,
+		},
+		{
+			name:   "or 안의 not만 있는 and",
+			filter: Or(Eq("region_id", float64(1)), And(Not(Eq("type", "Blend")), Not(Eq("age", float64(12))))),
+			want:   `(region_id = 1 or (true and !(type contains "Blend") and !(age = 12)))`,
+		},
+		{
+			name:   "이중 부정",
+			filter: Not(Not(Eq("type", "Blend"))),
+			want:   `!((true and !(type contains "Blend")))`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yqlFilters([]Filter{tt.filter}, true); got != tt.want {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVespaFieldsSparseTokens(t *testing.T) {
+	doc := Document{ID: 1, Sparse: map[string]float32{"5": 1.5}}
+	fields := toVespaFields(doc)
+	tokens, ok := fields["keyword_tokens"].(map[string]int)
+	if !ok || tokens["5"] != 1500 {
+		t.Fatalf("keyword_tokens = %#v", fields["keyword_tokens"])
+	}
+
+	back := fromVespaFields(testWhiskySchema(), map[string]interface{}{
+		"id":             float64(1),
+		"keywords":       map[string]interface{}{"5": 1.5},
+		"keyword_tokens": map[string]interface{}{"5": float64(1500)},
+	})
+	if _, ok := back.Fields["keyword_tokens"]; ok {
+		t.Fatal("keyword_tokens가 페이로드로 남음")
+	}
+	if back.Sparse["5"] != 1.5 {
+		t.Fatalf("sparse = %v", back.Sparse)
+	}
+}
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// visitPageSize Visit API 페이지 크기
+const visitPageSize = 100
+
+// vespaFetchConcurrency GetVectors에서 동시에 조회하는 문서 수
+const vespaFetchConcurrency = 8
+
+// VespaStore Vespa 기반 VectorStore
+type VespaStore struct {
+	client *VespaClient
+}
+
+func NewVespaStore(client *VespaClient) *VespaStore {
+	return &VespaStore{client: client}
+}
+
+// Upsert 문서 저장
+func (store *VespaStore) Upsert(ctx context.Context, s Schema, docs []Document) error {
+	for _, doc := range docs {
+		if err := store.client.PutDocument(ctx, s, strconv.FormatInt(doc.ID, 10), toVespaFields(doc)); err != nil {
+			return fmt.Errorf("문서 %d 저장 실패: %w", doc.ID, err)
+		}
+	}
+	return nil
+}
+
+// Update 부분 갱신 (문서에 담긴 필드만 assign)
+func (store *VespaStore) Update(ctx context.Context, s Schema, docs []Document) error {
+	for _, doc := range docs {
+		fields := toVespaFields(doc)
+		delete(fields, "id")
+		if err := store.client.UpdateDocument(ctx, s, strconv.FormatInt(doc.ID, 10), fields); err != nil {
+			return fmt.Errorf("문서 %d 갱신 실패: %w", doc.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetFields 문서마다 fieldSet으로 지정 필드만 조회
+func (store *VespaStore) GetFields(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string]interface{}, error) {
+	result := make(map[int64]map[string]interface{}, len(ids))
+	for _, id := range ids {
+		doc, err := store.client.GetDocumentFields(ctx, s, strconv.FormatInt(id, 10), names)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("문서 %d 조회 실패: %w", id, err)
+		}
+		result[id] = pickFields(doc.Fields, names)
+	}
+	return result, nil
+}
+
+// GetVectors 문서마다 fieldSet으로 지정 텐서만 조회
+// 텐서는 summary에 없어 검색 응답으로 받을 수 없고 Document API에 다건 조회가 없으므로, 요청을 동시에 보낸다
+func (store *VespaStore) GetVectors(ctx context.Context, s Schema, ids []int64, names []string) (map[int64]map[string][]float32, error) {
+	result := make(map[int64]map[string][]float32, len(ids))
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, vespaFetchConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			doc, err := store.client.GetDocumentFields(ctx, s, strconv.FormatInt(id, 10), names)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				result[id] = pickVectors(fromVespaFields(s, doc.Fields).Vectors, names)
+			case errors.Is(err, ErrNotFound):
+			case firstErr == nil:
+				firstErr = fmt.Errorf("문서 %d 벡터 조회 실패: %w", id, err)
+			}
+		}(id)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
+// Delete 문서 삭제
+func (store *VespaStore) Delete(ctx context.Context, s Schema, ids []int64) error {
+	for _, id := range ids {
+		if err := store.client.DeleteDocument(ctx, s, strconv.FormatInt(id, 10)); err != nil {
+			return fmt.Errorf("문서 %d 삭제 실패: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Get 단일 문서 조회
+func (store *VespaStore) Get(ctx context.Context, s Schema, id int64) (*Document, error) {
+	doc, err := store.client.GetDocument(ctx, s, strconv.FormatInt(id, 10))
+	if err != nil {
+		return nil, err
+	}
+	return fromVespaFields(s, doc.Fields), nil
+}
+
+// Search 단일 벡터 필드 kNN 검색
+func (store *VespaStore) Search(ctx context.Context, s Schema, q KNNQuery) ([]Hit, error) {
+	resp, err := store.client.Search(ctx, q.Body(s))
+	if err != nil {
+		return nil, err
+	}
+	return toHits(resp), nil
+}
+
+// HybridSearch 하이브리드 검색
+// rrf/linear는 global-phase에서 융합하고, 그 외 방식은 후보를 받아 match-features(필드별 점수)로 클라이언트에서 융합
+func (store *VespaStore) HybridSearch(ctx context.Context, s Schema, q HybridQuery) ([]Hit, error) {
+	if q.ServerFusion() {
+		resp, err := store.client.Search(ctx, q.Body(s))
+		if err != nil {
+			return nil, err
+		}
+		return toHits(resp), nil
+	}
+
+	candidates := q
+	candidates.Offset = 0
+	candidates.Limit = q.TargetHits
+	if candidates.Limit <= 0 {
+		candidates.Limit = defaultTargetHits
+	}
+	if candidates.Limit < q.Offset+q.Limit {
+		candidates.Limit = q.Offset + q.Limit
+	}
+	resp, err := store.client.Search(ctx, candidates.Body(s))
+	if err != nil {
+		return nil, err
+	}
+	return paginate(FuseHits(legsFromScores(toHits(resp)), q.Weights, q.Fusion), q.Offset, q.Limit), nil
+}
+
+// legsFromScores 후보의 필드별 점수로 leg 구성 (점수가 0이면 그 필드로는 찾지 못한 문서)
+func legsFromScores(hits []Hit) map[string][]Hit {
+	legs := make(map[string][]Hit)
+	for _, hit := range hits {
+		for field, score := range hit.Scores {
+			if score > 0 {
+				legs[field] = append(legs[field], Hit{ID: hit.ID, Score: score, Fields: hit.Fields})
+			}
+		}
+	}
+	for _, leg := range legs {
+		sortHits(leg)
+	}
+	return legs
+}
+
+// Visit continuation을 따라 전체 문서 순회
+func (store *VespaStore) Visit(ctx context.Context, s Schema, fn func(doc *Document) error) error {
+	continuation := ""
+	for {
+		page, err := store.client.Visit(ctx, s, continuation, visitPageSize)
+		if err != nil {
+			return err
+		}
+		for _, raw := range page.Documents {
+			if err := fn(fromVespaFields(s, raw.Fields)); err != nil {
+				if errors.Is(err, ErrStopVisit) {
+					return nil
+				}
+				return err
+			}
+		}
+		if page.Continuation == "" {
+			return nil
+		}
+		continuation = page.Continuation
+	}
+}
+
+// VisitFields continuation을 따라 id와 지정 필드만 순회
+func (store *VespaStore) VisitFields(ctx context.Context, s Schema, names []string, fn func(id int64, fields map[string]interface{}) error) error {
+	continuation := ""
+	for {
+		page, err := store.client.VisitFields(ctx, s, continuation, visitPageSize, names)
+		if err != nil {
+			return err
+		}
+		for _, raw := range page.Documents {
+			fields := make(map[string]interface{}, len(names))
+			for _, name := range names {
+				if value, ok := raw.Fields[name]; ok {
+					fields[name] = value
+				}
+			}
+			if err := fn(toInt64(raw.Fields["id"]), fields); err != nil {
+				if errors.Is(err, ErrStopVisit) {
+					return nil
+				}
+				return err
+			}
+		}
+		if page.Continuation == "" {
+			return nil
+		}
+		continuation = page.Continuation
+	}
+}
+
+// toVespaFields Document → Vespa 문서 필드
+func toVespaFields(doc Document) map[string]interface{} {
+	fields := make(map[string]interface{}, len(doc.Fields)+len(doc.Vectors)+2)
+	for name, value := range doc.Fields {
+		fields[name] = value
+	}
+	fields["id"] = doc.ID
+	for name, vector := range doc.Vectors {
+		fields[name] = vector
+	}
+	if doc.Sparse != nil {
+		fields[schema.SparseKeywords] = doc.Sparse
+		fields[schema.SparseTokens] = sparseTokenWeights(doc.Sparse)
+	}
+	return fields
+}
+
+// fromVespaFields Vespa 문서 필드 (format.tensors=short-value) → Document
+func fromVespaFields(s Schema, fields map[string]interface{}) *Document {
+	doc := &Document{
+		Vectors: make(map[string][]float32),
+		Fields:  make(map[string]interface{}),
+	}
+
+	vectorFields := make(map[string]bool, len(s.Vectors))
+	for _, v := range s.Vectors {
+		vectorFields[v.Name] = true
+	}
+
+	for name, value := range fields {
+		switch {
+		case name == "id":
+			doc.ID = toInt64(value)
+		case vectorFields[name]:
+			doc.Vectors[name] = toFloat32s(value)
+		case name == schema.SparseKeywords:
+			doc.Sparse = toSparse(value)
+		case name == schema.SparseTokens:
+			// keywords에서 만든 검색용 사본
+		default:
+			doc.Fields[name] = value
+		}
+	}
+	return doc
+}
+
+// toHits 검색 응답 → Hit 목록 (match-features의 *_score를 필드별 점수로)
+func toHits(resp *SearchResponse) []Hit {
+	hits := make([]Hit, 0, len(resp.Root.Children))
+	for _, child := range resp.Root.Children {
+		hit := Hit{
+			Score:  child.Relevance,
+			Scores: make(map[string]float64),
+			Fields: make(map[string]interface{}),
+		}
+		for name, value := range child.MatchFeatures() {
+			hit.Scores[strings.TrimSuffix(name, "_score")] = value
+		}
+		for name, value := range child.Fields {
+			switch name {
+			case "id":
+				hit.ID = toInt64(value)
+			case "matchfeatures", "sddocname", "documentid":
+			default:
+				hit.Fields[name] = value
+			}
+		}
+		hits = append(hits, hit)
+	}
+	return hits
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case string:
+		id, _ := strconv.ParseInt(v, 10, 64)
+		return id
+	}
+	return 0
+}
+
+func toFloat32s(value interface{}) []float32 {
+	switch v := value.(type) {
+	case []float32:
+		return v
+	case []interface{}:
+		vector := make([]float32, 0, len(v))
+		for _, x := range v {
+			f, _ := x.(float64)
+			vector = append(vector, float32(f))
+		}
+		return vector
+	}
+	return nil
+}
+
+func toSparse(value interface{}) map[string]float32 {
+	switch v := value.(type) {
+	case map[string]float32:
+		return v
+	case map[string]interface{}:
+		sparse := make(map[string]float32, len(v))
+		for token, x := range v {
+			f, _ := x.(float64)
+			sparse[token] = float32(f)
+		}
+		return sparse
+	}
+	return nil
+}
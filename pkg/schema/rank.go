@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 쿼리 입력 이름
+const (
+	// QueryDense 쿼리 dense 벡터 입력 (모든 named vector에 공통 사용)
+	QueryDense = "q"
+	// QuerySparse 쿼리 sparse 벡터 입력
+	QuerySparse = "q_sparse"
+)
+
+// 하이브리드 랭킹 프로파일 이름
+const (
+	// RankProfileHybridRRF 필드별 reciprocal_rank() 가중 합 (가중 RRF)
+	RankProfileHybridRRF = "hybrid_rrf"
+	// RankProfileHybridLinear 가중 min-max 정규화 선형 결합
+	RankProfileHybridLinear = "hybrid_linear"
+
+	// fusionRerankCount global-phase에서 융합할 후보 수
+	fusionRerankCount = 1000
+)
+
+// KNNRankProfile 단일 벡터 필드 kNN 랭킹 프로파일 이름 (knn_flavor)
+func KNNRankProfile(field string) string {
+	return "knn_" + field
+}
+
+// WeightInput 필드별 가중치 쿼리 입력 이름 (query(w_flavor))
+func WeightInput(field string) string {
+	return "w_" + field
+}
+
+// ScoreFunction 필드별 점수 함수 이름 (match-features로 반환됨)
+func ScoreFunction(field string) string {
+	return field + "_score"
+}
+
+// knnRankProfiles dense 필드별 closeness 단일 랭킹 프로파일
+func knnRankProfiles(denseFields []string) []RankProfile {
+	profiles := make([]RankProfile, 0, len(denseFields))
+	for _, field := range denseFields {
+		profiles = append(profiles, RankProfile{
+			Name: KNNRankProfile(field),
+			Inputs: []RankInput{
+				{Name: QueryDense, Type: fmt.Sprintf("tensor<float>(x[%d])", EmbeddingDimension)},
+			},
+			FirstPhase: fmt.Sprintf("closeness(field, %s)", field),
+		})
+	}
+	return profiles
+}
+
+// hybridRankProfiles dense 필드별 closeness와 sparse dot product를 global-phase에서 융합하는 프로파일
+func hybridRankProfiles(denseFields []string, sparseField string) []RankProfile {
+	inputs := []RankInput{
+		{Name: QueryDense, Type: fmt.Sprintf("tensor<float>(x[%d])", EmbeddingDimension)},
+		{Name: QuerySparse, Type: "tensor<float>(token{})"},
+	}
+	functions := make([]RankFunction, 0, len(denseFields)+1)
+	for _, field := range denseFields {
+		inputs = append(inputs, RankInput{Name: WeightInput(field), Type: "double", Default: "1.0"})
+		functions = append(functions, RankFunction{
+			Name:       ScoreFunction(field),
+			Expression: fmt.Sprintf("closeness(field, %s)", field),
+		})
+	}
+	inputs = append(inputs, RankInput{Name: WeightInput(sparseField), Type: "double", Default: "1.0"})
+	functions = append(functions, RankFunction{
+		Name:       ScoreFunction(sparseField),
+		Expression: fmt.Sprintf("sum(query(%s) * attribute(%s))", QuerySparse, sparseField),
+	})
+
+	fields := append(append([]string{}, denseFields...), sparseField)
+	scores := make([]string, 0, len(fields))
+	for _, field := range fields {
+		scores = append(scores, ScoreFunction(field))
+	}
+
+	// fusion 가중 합: query(w_x) * normalize(x_score) + ...
+	fused := func(normalize string) string {
+		terms := make([]string, 0, len(fields))
+		for _, field := range fields {
+			terms = append(terms, fmt.Sprintf("query(%s) * %s(%s)", WeightInput(field), normalize, ScoreFunction(field)))
+		}
+		return strings.Join(terms, " + ")
+	}
+
+	profile := func(name, expression string) RankProfile {
+		return RankProfile{
+			Name:          name,
+			Inputs:        inputs,
+			Functions:     functions,
+			FirstPhase:    strings.Join(scores, " + "),
+			MatchFeatures: scores,
+			GlobalPhase: &GlobalPhase{
+				Expression:  expression,
+				RerankCount: fusionRerankCount,
+			},
+		}
+	}
+
+	return []RankProfile{
+		// reciprocal_rank_fusion(...)은 쿼리 가중치를 받지 않으므로 reciprocal_rank() 가중 합 (가중치가 모두 1이면 같은 점수)
+		profile(RankProfileHybridRRF, fused("reciprocal_rank")),
+		profile(RankProfileHybridLinear, fused("normalize_linear")),
+	}
+}
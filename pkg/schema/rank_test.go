@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHybridRankProfiles(t *testing.T) {
+	profiles := hybridRankProfiles([]string{"flavor", "spec"}, "keywords")
+	want := map[string]string{
+		RankProfileHybridRRF:    "query(w_flavor) * reciprocal_rank(flavor_score) + query(w_spec) * reciprocal_rank(spec_score) + query(w_keywords) * reciprocal_rank(keywords_score)",
+		RankProfileHybridLinear: "query(w_flavor) * normalize_linear(flavor_score) + query(w_spec) * normalize_linear(spec_score) + query(w_keywords) * normalize_linear(keywords_score)",
+	}
+	if len(profiles) != len(want) {
+		t.Fatalf("프로파일 %d개", len(profiles))
+	}
+	for _, p := range profiles {
+		if p.GlobalPhase == nil || p.GlobalPhase.Expression != want[p.Name] {
+			t.Fatalf("%s global-phase %+v", p.Name, p.GlobalPhase)
+		}
+	}
+}
+
+func TestRenderRankProfile(t *testing.T) {
+	var b strings.Builder
+	renderRankProfile(&b, hybridRankProfiles([]string{"flavor"}, "keywords")[0])
+	want := `    rank-profile hybrid_rrf {
+        inputs {
+            query(q) tensor<float>(x[1024])
+            query(q_sparse) tensor<float>(token{})
+            query(w_flavor) double: 1.0
+            query(w_keywords) double: 1.0
+        }
+
+        function flavor_score() {
+            expression: closeness(field, flavor)
+        }
+
+        function keywords_score() {
+            expression: sum(query(q_sparse) * attribute(keywords))
+        }
+
+        first-phase {
+            expression: flavor_score + keywords_score
+        }
+
+        match-features: flavor_score keywords_score
+
+        global-phase {
+            expression: query(w_flavor) * reciprocal_rank(flavor_score) + query(w_keywords) * reciprocal_rank(keywords_score)
+            rerank-count: 1000
+        }
+    }
+`
+	if got := b.String(); got != want {
+		t.Fatalf("renderRankProfile:\n%s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const generatedHeader = "Code generated by `embedder-worker schema`. DO NOT EDIT."
+
+// RenderSD 스키마를 Vespa .sd 파일 내용으로 변환
+func RenderSD(s *Schema) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", generatedHeader)
+	fmt.Fprintf(&b, "schema %s {\n\n", s.Name)
+	fmt.Fprintf(&b, "    document %s {\n", s.Name)
+	for _, f := range s.Fields {
+		b.WriteString("\n")
+		renderField(&b, f)
+	}
+	b.WriteString("    }\n")
+	for _, p := range s.RankProfiles {
+		b.WriteString("\n")
+		renderRankProfile(&b, p)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func renderField(b *strings.Builder, f Field) {
+	fmt.Fprintf(b, "        field %s type %s {\n", f.Name, f.Type)
+	fmt.Fprintf(b, "            indexing: %s\n", strings.Join(f.Indexing, " | "))
+
+	if f.FastSearch || f.DistanceMetric != "" {
+		b.WriteString("            attribute {\n")
+		if f.FastSearch {
+			b.WriteString("                fast-search\n")
+		}
+		if f.DistanceMetric != "" {
+			fmt.Fprintf(b, "                distance-metric: %s\n", f.DistanceMetric)
+		}
+		b.WriteString("            }\n")
+	}
+
+	if f.HNSW != nil {
+		b.WriteString("            index {\n")
+		b.WriteString("                hnsw {\n")
+		fmt.Fprintf(b, "                    max-links-per-node: %d\n", f.HNSW.MaxLinksPerNode)
+		fmt.Fprintf(b, "                    neighbors-to-explore-at-insert: %d\n", f.HNSW.NeighborsToExploreAtInsert)
+		b.WriteString("                }\n")
+		b.WriteString("            }\n")
+	}
+
+	b.WriteString("        }\n")
+}
+
+func renderRankProfile(b *strings.Builder, p RankProfile) {
+	fmt.Fprintf(b, "    rank-profile %s {\n", p.Name)
+
+	if len(p.Inputs) > 0 {
+		b.WriteString("        inputs {\n")
+		for _, in := range p.Inputs {
+			if in.Default != "" {
+				fmt.Fprintf(b, "            query(%s) %s: %s\n", in.Name, in.Type, in.Default)
+			} else {
+				fmt.Fprintf(b, "            query(%s) %s\n", in.Name, in.Type)
+			}
+		}
+		b.WriteString("        }\n")
+	}
+
+	for _, fn := range p.Functions {
+		fmt.Fprintf(b, "\n        function %s() {\n", fn.Name)
+		fmt.Fprintf(b, "            expression: %s\n", fn.Expression)
+		b.WriteString("        }\n")
+	}
+
+	if p.FirstPhase != "" {
+		b.WriteString("\n        first-phase {\n")
+		fmt.Fprintf(b, "            expression: %s\n", p.FirstPhase)
+		b.WriteString("        }\n")
+	}
+
+	if len(p.MatchFeatures) > 0 {
+		fmt.Fprintf(b, "\n        match-features: %s\n", strings.Join(p.MatchFeatures, " "))
+	}
+
+	if p.GlobalPhase != nil {
+		b.WriteString("\n        global-phase {\n")
+		fmt.Fprintf(b, "            expression: %s\n", p.GlobalPhase.Expression)
+		fmt.Fprintf(b, "            rerank-count: %d\n", p.GlobalPhase.RerankCount)
+		b.WriteString("        }\n")
+	}
+
+	b.WriteString("    }\n")
+}
+
+// RenderServices 애플리케이션을 services.xml 내용으로 변환
+func RenderServices(app *Application) string {
+	var b strings.Builder
+
+	b.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\" ?>\n")
+	fmt.Fprintf(&b, "<!-- %s -->\n", generatedHeader)
+	b.WriteString("<services version=\"1.0\">\n\n")
+
+	b.WriteString("  <!-- container: Search/Feed API server (port 8080) -->\n")
+	fmt.Fprintf(&b, "  <container id=\"%s\" version=\"1.0\">\n", app.ContainerID)
+	b.WriteString("    <search/>\n")
+	b.WriteString("    <document-api/>\n")
+	b.WriteString("  </container>\n")
+
+	for _, cluster := range app.Clusters {
+		b.WriteString("\n  <!-- content: Data storage -->\n")
+		fmt.Fprintf(&b, "  <content id=\"%s\" version=\"1.0\">\n", cluster.ID)
+		fmt.Fprintf(&b, "    <redundancy>%d</redundancy>\n", cluster.Redundancy)
+		b.WriteString("    <documents>\n")
+		for _, s := range cluster.Schemas {
+			fmt.Fprintf(&b, "      <document type=\"%s\" mode=\"index\"/>\n", s.Name)
+		}
+		b.WriteString("    </documents>\n")
+		b.WriteString("  </content>\n")
+	}
+
+	b.WriteString("\n</services>\n")
+
+	return b.String()
+}
+
+// Files 애플리케이션 패키지의 파일 경로(상대) → 내용
+func Files(app *Application) map[string]string {
+	files := map[string]string{
+		"services.xml": RenderServices(app),
+	}
+	for _, s := range app.Schemas() {
+		files[filepath.Join("schemas", s.Name+".sd")] = RenderSD(s)
+	}
+	return files
+}
+
+// Write 애플리케이션 패키지 파일을 dir 아래에 기록
+func Write(app *Application, dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0755); err != nil {
+		return fmt.Errorf("schemas 디렉토리 생성 실패: %w", err)
+	}
+
+	for path, content := range Files(app) {
+		fullPath := filepath.Join(dir, path)
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("%s 기록 실패: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Diff dir 아래 파일 중 생성 결과와 다른 파일 목록 반환
+func Diff(app *Application, dir string) ([]string, error) {
+	changed := make([]string, 0)
+	for path, content := range Files(app) {
+		current, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			if os.IsNotExist(err) {
+				changed = append(changed, path)
+				continue
+			}
+			return nil, fmt.Errorf("%s 읽기 실패: %w", path, err)
+		}
+		if !bytes.Equal(current, []byte(content)) {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
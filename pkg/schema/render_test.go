@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func testApplication() *Application {
+	s := &Schema{
+		Name: "beer",
+		Fields: []Field{
+			{Name: "id", Type: "long", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "name", Type: "string", Indexing: []string{"summary", "index"}},
+			{Name: "taste", Type: "tensor<float>(x[4])", Indexing: []string{"attribute", "index"}, DistanceMetric: "angular", HNSW: &HNSW{MaxLinksPerNode: 16, NeighborsToExploreAtInsert: 200}},
+		},
+	}
+	return &Application{ContainerID: "default", Clusters: []ContentCluster{{ID: "beer", Redundancy: 2, Schemas: []*Schema{s}}}}
+}
+
+func TestRenderSD(t *testing.T) {
+	got := RenderSD(testApplication().Schemas()[0])
+	want := `# Code generated by ` + "`embedder-worker schema`" + `. DO NOT EDIT.
+
+schema beer {
+
+    document beer {
+
+        field id type long {
+            indexing: summary | attribute
+            attribute {
+                fast-search
+            }
+        }
+
+        field name type string {
+            indexing: summary | index
+        }
+
+        field taste type tensor<float>(x[4]) {
+            indexing: attribute | index
+            attribute {
+                distance-metric: angular
+            }
+            index {
+                hnsw {
+                    max-links-per-node: 16
+                    neighbors-to-explore-at-insert: 200
+                }
+            }
+        }
+    }
+}
+`
+	if got != want {
+		t.Fatalf("RenderSD:\n%s", got)
+	}
+}
+
+func TestRenderServices(t *testing.T) {
+	got := RenderServices(testApplication())
+	want := `<?xml version="1.0" encoding="utf-8" ?>
+<!-- Code generated by ` + "`embedder-worker schema`" + `. DO NOT EDIT. -->
+<services version="1.0">
+
+  <!-- container: Search/Feed API server (port 8080) -->
+  <container id="default" version="1.0">
+    <search/>
+    <document-api/>
+  </container>
+
+  <!-- content: Data storage -->
+  <content id="beer" version="1.0">
+    <redundancy>2</redundancy>
+    <documents>
+      <document type="beer" mode="index"/>
+    </documents>
+  </content>
+
+</services>
+`
+	if got != want {
+		t.Fatalf("RenderServices:\n%s", got)
+	}
+}
+
+func TestWriteAndDiff(t *testing.T) {
+	app := testApplication()
+	dir := t.TempDir()
+
+	files := Files(app)
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	sdPath := filepath.Join("schemas", "beer.sd")
+	if !reflect.DeepEqual(paths, []string{sdPath, "services.xml"}) {
+		t.Fatalf("파일 %v", paths)
+	}
+
+	// 아무것도 없으면 전부 다름
+	changed, err := Diff(app, dir)
+	if err != nil || !reflect.DeepEqual(changed, []string{sdPath, "services.xml"}) {
+		t.Fatalf("빈 디렉토리 diff %v, %v", changed, err)
+	}
+
+	if err := Write(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := Diff(app, dir); err != nil || len(changed) != 0 {
+		t.Fatalf("기록 직후 diff %v, %v", changed, err)
+	}
+
+	// 손으로 고친 파일만 다름
+	edited := strings.Replace(files[sdPath], "max-links-per-node: 16", "max-links-per-node: 8", 1)
+	if err := os.WriteFile(filepath.Join(dir, sdPath), []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := Diff(app, dir); err != nil || !reflect.DeepEqual(changed, []string{sdPath}) {
+		t.Fatalf("수정 후 diff %v, %v", changed, err)
+	}
+}
+
+// 저장소에 커밋된 vespa-app이 생성 결과와 같아야 한다 (schema --check와 같은 검사)
+func TestDefaultApplicationUpToDate(t *testing.T) {
+	changed, err := Diff(DefaultApplication(), filepath.Join("..", "..", "vespa-app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 0 {
+		t.Fatalf("vespa-app이 생성 결과와 다름 (go run . schema --out vespa-app): %v", changed)
+	}
+}
@@ -0,0 +1,97 @@
+package schema
+
+// Application Vespa 애플리케이션 패키지 정의 (services.xml + schemas/*.sd)
+type Application struct {
+	ContainerID string
+	Clusters    []ContentCluster
+}
+
+// ContentCluster services.xml의 content 클러스터
+type ContentCluster struct {
+	ID         string
+	Redundancy int
+	Schemas    []*Schema
+}
+
+// Schema Vespa 스키마(.sd) 정의
+type Schema struct {
+	Name         string
+	Fields       []Field
+	RankProfiles []RankProfile
+}
+
+// Field 문서 필드 정의
+type Field struct {
+	Name     string
+	Type     string
+	Indexing []string
+	// FastSearch attribute 필드에 fast-search 인덱스 생성 (필터용)
+	FastSearch bool
+	// DistanceMetric 텐서 필드의 거리 함수 (angular, euclidean, dotproduct, ...)
+	DistanceMetric string
+	HNSW           *HNSW
+}
+
+// HNSW 근사 최근접 이웃 인덱스 설정
+type HNSW struct {
+	MaxLinksPerNode            int
+	NeighborsToExploreAtInsert int
+}
+
+// RankProfile 랭킹 프로파일 정의
+type RankProfile struct {
+	Name          string
+	Inputs        []RankInput
+	Functions     []RankFunction
+	FirstPhase    string
+	MatchFeatures []string
+	GlobalPhase   *GlobalPhase
+}
+
+// RankInput 쿼리 입력 (query(name) 타입[: 기본값])
+type RankInput struct {
+	Name    string
+	Type    string
+	Default string
+}
+
+// RankFunction 랭킹 함수 (인자 없는 매크로)
+type RankFunction struct {
+	Name       string
+	Expression string
+}
+
+// GlobalPhase 전체 후보에 대한 정규화/융합 단계
+type GlobalPhase struct {
+	Expression  string
+	RerankCount int
+}
+
+// Field 이름으로 필드 조회
+func (s *Schema) Field(name string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Schemas 애플리케이션에 포함된 전체 스키마 목록
+func (a *Application) Schemas() []*Schema {
+	schemas := make([]*Schema, 0)
+	for _, cluster := range a.Clusters {
+		schemas = append(schemas, cluster.Schemas...)
+	}
+	return schemas
+}
+
+// RankProfile 이름으로 랭킹 프로파일 조회
+func (s *Schema) RankProfile(name string) (RankProfile, bool) {
+	for _, p := range s.RankProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return RankProfile{}, false
+}
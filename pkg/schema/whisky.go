@@ -0,0 +1,121 @@
+package schema
+
+import "fmt"
+
+const (
+	// EmbeddingDimension 임베딩 모델 출력 차원 (KURE-v1 / BGE-m3-ko)
+	EmbeddingDimension = 1024
+
+	// WhiskyName v2 전략 문서 타입 이름
+	WhiskyName = "whisky"
+)
+
+// Named vector 필드 이름 (v2 WhiskyEmbeddingStrategy)
+const (
+	VectorFlavor   = "flavor"
+	VectorIdentity = "identity"
+	VectorOrigin   = "origin"
+	VectorSpec     = "spec"
+
+	SparseKeywords = "keywords"
+	// SparseTokens keywords와 같은 토큰의 weightedset (wand로 키워드 후보를 직접 찾는 용도)
+	SparseTokens = "keyword_tokens"
+)
+
+// SparseTokenScale sparse 가중치 → weightedset 정수 가중치 배율 (wand는 정수 가중치만 받는다)
+const SparseTokenScale = 1000
+
+// FieldsHash 벡터를 제외한 필드(메타데이터, rag_context 등)의 내용 해시 필드
+const FieldsHash = "fields_hash"
+
+// HashField named vector 입력 내용 해시 필드 이름 (flavor_hash)
+func HashField(vector string) string {
+	return vector + "_hash"
+}
+
+// DenseVectors v2 전략의 named vector 필드 목록
+var DenseVectors = []string{VectorFlavor, VectorIdentity, VectorOrigin, VectorSpec}
+
+// Whisky v2 전략 문서 스키마
+// 4개 named vector + sparse keywords + 필터 메타데이터 + RAG 컨텍스트
+func Whisky() *Schema {
+	s := &Schema{
+		Name: WhiskyName,
+		Fields: []Field{
+			{Name: "id", Type: "long", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "kor_name", Type: "string", Indexing: []string{"summary", "index"}},
+			{Name: "eng_name", Type: "string", Indexing: []string{"summary", "index"}},
+			{Name: "image_url", Type: "string", Indexing: []string{"summary"}},
+
+			// 필터 메타데이터 (Python filter_metadata 키와 동일)
+			{Name: "type", Type: "string", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "abv", Type: "double", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "age", Type: "int", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "categoryGroup", Type: "string", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "region_id", Type: "long", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "distillery_id", Type: "long", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "tastingTags", Type: "array<string>", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+
+			// 정규화된 범위 (pkg/normalize), 범위 필터용
+			{Name: "abv_min", Type: "double", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "abv_max", Type: "double", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "age_min", Type: "int", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "age_max", Type: "int", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+			{Name: "nas", Type: "bool", Indexing: []string{"summary", "attribute"}},
+			{Name: "volume_ml", Type: "double", Indexing: []string{"summary", "attribute"}},
+
+			// RAG용 자연어 컨텍스트
+			{Name: "rag_context", Type: "string", Indexing: []string{"summary", "index"}},
+
+			// 벡터를 만든 임베딩 전략 버전
+			{Name: "strategy_version", Type: "string", Indexing: []string{"summary", "attribute"}, FastSearch: true},
+		},
+	}
+
+	// 내용 해시: 입력이 그대로인 벡터/필드는 다시 임베딩하거나 갱신하지 않는다
+	for _, name := range DenseVectors {
+		s.Fields = append(s.Fields, Field{Name: HashField(name), Type: "string", Indexing: []string{"summary", "attribute"}})
+	}
+	s.Fields = append(s.Fields, Field{Name: FieldsHash, Type: "string", Indexing: []string{"summary", "attribute"}})
+
+	for _, name := range DenseVectors {
+		s.Fields = append(s.Fields, Field{
+			Name:           name,
+			Type:           fmt.Sprintf("tensor<float>(x[%d])", EmbeddingDimension),
+			Indexing:       []string{"attribute", "index"},
+			DistanceMetric: "angular",
+			HNSW: &HNSW{
+				MaxLinksPerNode:            16,
+				NeighborsToExploreAtInsert: 200,
+			},
+		})
+	}
+
+	// sparse 벡터: 토큰 → 가중치
+	s.Fields = append(s.Fields, Field{
+		Name:     SparseKeywords,
+		Type:     "tensor<float>(token{})",
+		Indexing: []string{"attribute"},
+	})
+	// 텐서 필드는 검색 연산자로 찾을 수 없어 같은 토큰을 weightedset으로도 저장한다
+	s.Fields = append(s.Fields, Field{
+		Name:       SparseTokens,
+		Type:       "weightedset<string>",
+		Indexing:   []string{"attribute"},
+		FastSearch: true,
+	})
+
+	s.RankProfiles = append(knnRankProfiles(DenseVectors), hybridRankProfiles(DenseVectors, SparseKeywords)...)
+
+	return s
+}
+
+// DefaultApplication 로컬 개발용 Vespa 애플리케이션
+func DefaultApplication() *Application {
+	return &Application{
+		ContainerID: "default",
+		Clusters: []ContentCluster{
+			{ID: WhiskyName, Redundancy: 1, Schemas: []*Schema{Whisky()}},
+		},
+	}
+}
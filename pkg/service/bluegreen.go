@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// 새 버전 검증 기본값
+const (
+	DefaultValidateSamples     = 50
+	DefaultValidateK           = 10
+	DefaultValidateMinCoverage = 0.99
+	DefaultValidateMinSelf     = 0.95
+	DefaultValidateMinOverlap  = 0.5
+)
+
+// ValidateOptions 새 버전 검증 기준
+type ValidateOptions struct {
+	// Samples 표본 쿼리로 쓸 문서 수
+	Samples int
+	// K 비교할 상위 결과 수
+	K int
+	// MinCoverage 기존 버전 문서 중 새 버전에도 있는 비율
+	MinCoverage float64
+	// MinSelfRecall 표본 문서 벡터로 검색했을 때 자기 자신이 상위 K에 드는 비율
+	MinSelfRecall float64
+	// MinOverlap 같은 표본 문서의 상위 K 이웃이 두 버전에서 겹치는 평균 비율 (짧은 쪽 결과 수 기준)
+	MinOverlap float64
+}
+
+func (o *ValidateOptions) applyDefaults() {
+	if o.Samples <= 0 {
+		o.Samples = DefaultValidateSamples
+	}
+	if o.K <= 0 {
+		o.K = DefaultValidateK
+	}
+	if o.MinCoverage <= 0 {
+		o.MinCoverage = DefaultValidateMinCoverage
+	}
+	if o.MinSelfRecall <= 0 {
+		o.MinSelfRecall = DefaultValidateMinSelf
+	}
+	if o.MinOverlap <= 0 {
+		o.MinOverlap = DefaultValidateMinOverlap
+	}
+}
+
+// FieldValidation 벡터 필드별 검증 결과
+type FieldValidation struct {
+	SelfRecall float64 `json:"self_recall"`
+	Overlap    float64 `json:"overlap"`
+}
+
+// ValidationReport 새 버전 검증 결과
+type ValidationReport struct {
+	From       string                     `json:"from"`
+	To         string                     `json:"to"`
+	FromCount  int                        `json:"from_count"`
+	ToCount    int                        `json:"to_count"`
+	Coverage   float64                    `json:"coverage"`
+	Samples    int                        `json:"samples"`
+	SelfRecall float64                    `json:"self_recall"`
+	Overlap    float64                    `json:"overlap"`
+	Fields     map[string]FieldValidation `json:"fields"`
+	Passed     bool                       `json:"passed"`
+	// Reasons 기준에 못 미친 항목
+	Reasons []string `json:"reasons"`
+}
+
+// ValidateCollection 새 버전(to)을 기존 버전(from)과 비교
+// 모델이 바뀌면 두 버전의 벡터 공간이 다르므로, 표본 문서를 각 버전에 저장된 자기 벡터로 검색해
+// 자기 자신을 찾는지(self recall)와 상위 K 이웃이 얼마나 겹치는지(overlap)를 본다
+func ValidateCollection(ctx context.Context, store repository.VectorStore, from, to repository.Schema, opts ValidateOptions) (*ValidationReport, error) {
+	opts.applyDefaults()
+	report := &ValidationReport{From: from.Name, To: to.Name, Fields: make(map[string]FieldValidation), Reasons: []string{}}
+
+	fromIDs, err := visitIDs(ctx, store, from)
+	if err != nil {
+		return nil, fmt.Errorf("%s 순회 실패: %w", from.Name, err)
+	}
+	toIDs, err := visitIDs(ctx, store, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s 순회 실패: %w", to.Name, err)
+	}
+	report.FromCount, report.ToCount = len(fromIDs), len(toIDs)
+
+	inTo := make(map[int64]bool, len(toIDs))
+	for _, id := range toIDs {
+		inTo[id] = true
+	}
+	covered := make([]int64, 0, len(fromIDs))
+	for _, id := range fromIDs {
+		if inTo[id] {
+			covered = append(covered, id)
+		}
+	}
+	// 기존 버전이 비어 있으면 비교 기준이 없으므로 coverage 0 (비어 있는 쪽으로 전환하지 않도록 실패 처리)
+	if len(fromIDs) > 0 {
+		report.Coverage = float64(len(covered)) / float64(len(fromIDs))
+	}
+
+	// 양쪽에 있는 문서를 고르게 표본 추출 (실행마다 같은 표본)
+	samples := covered
+	if len(samples) > opts.Samples {
+		samples = make([]int64, opts.Samples)
+		for i := range samples {
+			samples[i] = covered[i*len(covered)/opts.Samples]
+		}
+	}
+	report.Samples = len(samples)
+
+	fields := commonVectorFields(from, to)
+	var selfTotal, overlapTotal float64
+	for _, field := range fields {
+		var self, overlap float64
+		for _, id := range samples {
+			fromHits, err := searchSelf(ctx, store, from, field, id, opts.K)
+			if err != nil {
+				return nil, err
+			}
+			toHits, err := searchSelf(ctx, store, to, field, id, opts.K)
+			if err != nil {
+				return nil, err
+			}
+			if toHits[id] {
+				self++
+			}
+			shared := 0
+			for hit := range toHits {
+				if fromHits[hit] {
+					shared++
+				}
+			}
+			// 컬렉션이 K보다 작으면 결과 수가 K에 못 미치므로 짧은 쪽 결과 수로 나눈다
+			overlap += float64(shared) / float64(max(1, min(len(fromHits), len(toHits))))
+		}
+		if len(samples) > 0 {
+			self /= float64(len(samples))
+			overlap /= float64(len(samples))
+		}
+		report.Fields[field] = FieldValidation{SelfRecall: self, Overlap: overlap}
+		selfTotal += self
+		overlapTotal += overlap
+	}
+	if len(fields) > 0 {
+		report.SelfRecall = selfTotal / float64(len(fields))
+		report.Overlap = overlapTotal / float64(len(fields))
+	}
+
+	if report.FromCount == 0 {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%s 문서 없음", from.Name))
+	}
+	if report.Coverage < opts.MinCoverage {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("coverage %.3f < %.3f", report.Coverage, opts.MinCoverage))
+	}
+	if report.Samples == 0 {
+		report.Reasons = append(report.Reasons, "표본 문서 없음")
+	}
+	if report.SelfRecall < opts.MinSelfRecall {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("self recall %.3f < %.3f", report.SelfRecall, opts.MinSelfRecall))
+	}
+	if report.Overlap < opts.MinOverlap {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("overlap %.3f < %.3f", report.Overlap, opts.MinOverlap))
+	}
+	report.Passed = len(report.Reasons) == 0
+	return report, nil
+}
+
+// visitIDs 컬렉션의 전체 문서 ID (저장소 순회 순서, 필드/벡터는 읽지 않는다)
+func visitIDs(ctx context.Context, store repository.VectorStore, s repository.Schema) ([]int64, error) {
+	ids := make([]int64, 0)
+	err := store.VisitFields(ctx, s, nil, func(id int64, _ map[string]interface{}) error {
+		ids = append(ids, id)
+		return nil
+	})
+	return ids, err
+}
+
+// commonVectorFields 두 버전에 모두 있는 dense 벡터 필드
+func commonVectorFields(from, to repository.Schema) []string {
+	inFrom := make(map[string]bool, len(from.Vectors))
+	for _, v := range from.Vectors {
+		inFrom[v.Name] = true
+	}
+	fields := make([]string, 0, len(to.Vectors))
+	for _, v := range to.Vectors {
+		if inFrom[v.Name] {
+			fields = append(fields, v.Name)
+		}
+	}
+	return fields
+}
+
+// searchSelf 문서에 저장된 field 벡터로 같은 컬렉션을 검색한 상위 k개 ID
+func searchSelf(ctx context.Context, store repository.VectorStore, s repository.Schema, field string, id int64, k int) (map[int64]bool, error) {
+	doc, err := store.Get(ctx, s, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s 문서 %d 조회 실패: %w", s.Name, id, err)
+	}
+	ids := make(map[int64]bool, k)
+	vector, ok := doc.Vectors[field]
+	if !ok {
+		return ids, nil
+	}
+	hits, err := store.Search(ctx, s, repository.KNNQuery{Field: field, Vector: vector, Limit: k})
+	if err != nil {
+		return nil, fmt.Errorf("%s %s 검색 실패: %w", s.Name, field, err)
+	}
+	for _, hit := range hits {
+		ids[hit.ID] = true
+	}
+	return ids, nil
+}
+
+// Cutover alias의 활성 버전을 target으로 전환 (직전 버전은 롤백용으로 남긴다)
+// 직전 버전은 저장된 포인터 기준이다 (레지스트리는 serve의 주기적 갱신처럼 늦을 수 있다)
+func Cutover(ctx context.Context, aliases repository.AliasStore, collections *repository.Collections, alias, target string) (*domain.CollectionAlias, error) {
+	current, ok := collections.Active(alias)
+	if !ok {
+		return nil, fmt.Errorf("알 수 없는 alias: %s", alias)
+	}
+	expected := ""
+	row, err := aliases.Alias(ctx, alias)
+	switch {
+	case err == nil:
+		current, expected = row.Active, row.Active
+	case !errors.Is(err, repository.ErrAliasNotFound):
+		return nil, err
+	}
+	if current == target {
+		return nil, fmt.Errorf("%s는 이미 %s의 활성 버전", target, alias)
+	}
+	return switchAlias(ctx, aliases, collections, alias, target, current, expected)
+}
+
+// Rollback 직전 활성 버전으로 되돌린다 (한 번 더 실행하면 다시 전환)
+func Rollback(ctx context.Context, aliases repository.AliasStore, collections *repository.Collections, alias string) (*domain.CollectionAlias, error) {
+	row, err := aliases.Alias(ctx, alias)
+	if errors.Is(err, repository.ErrAliasNotFound) {
+		return nil, fmt.Errorf("%s는 전환된 적이 없음", alias)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if row.Previous == "" {
+		return nil, fmt.Errorf("%s의 직전 버전 없음", alias)
+	}
+	return switchAlias(ctx, aliases, collections, alias, row.Previous, row.Active, row.Active)
+}
+
+// switchAlias 읽은 활성 버전(expected)이 그대로일 때만 포인터를 바꾸고 레지스트리에 반영
+// 저장에 실패하면 레지스트리를 원래대로 되돌린다 (재시작 후 상태와 어긋나지 않도록)
+func switchAlias(ctx context.Context, aliases repository.AliasStore, collections *repository.Collections, alias, active, previous, expected string) (*domain.CollectionAlias, error) {
+	original, _ := collections.Active(alias)
+	if err := collections.SetActive(alias, active); err != nil {
+		return nil, err
+	}
+	row := &domain.CollectionAlias{Alias: alias, Active: active, Previous: previous, UpdatedAt: time.Now()}
+	if err := aliases.SaveAlias(ctx, row, expected); err != nil {
+		_ = collections.SetActive(alias, original)
+		if errors.Is(err, repository.ErrAliasConflict) {
+			return nil, fmt.Errorf("%s를 다른 전환이 먼저 바꿈, 상태를 확인하고 다시 실행: %w", alias, err)
+		}
+		return nil, err
+	}
+	return row, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+func versionSchema(name string) repository.Schema {
+	s := testWhiskySchema()
+	s.Name, s.DocType, s.Alias = name, name, "whisky"
+	return s
+}
+
+// seedVersion 같은 텍스트 → 같은 벡터이므로 ids가 같으면 두 버전의 이웃도 같다
+func seedVersion(t *testing.T, store repository.VectorStore, s repository.Schema, ids ...int64) {
+	t.Helper()
+	docs := make([]repository.Document, 0, len(ids))
+	for _, id := range ids {
+		doc := repository.Document{ID: id, Fields: map[string]interface{}{}, Vectors: map[string][]float32{}}
+		for _, v := range s.Vectors {
+			doc.Vectors[v.Name] = fakeVector(fmt.Sprintf("%s-%d", v.Name, id), 4)
+		}
+		docs = append(docs, doc)
+	}
+	if err := store.Upsert(context.Background(), s, docs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateCollection(t *testing.T) {
+	from, to := versionSchema("whisky_v1"), versionSchema("whisky_v2")
+	tests := []struct {
+		name     string
+		fromIDs  []int64
+		toIDs    []int64
+		passed   bool
+		coverage float64
+		reason   string
+	}{
+		{"같은 문서", []int64{1, 2, 3, 4, 5, 6}, []int64{1, 2, 3, 4, 5, 6}, true, 1, ""},
+		// 문서가 K보다 적어도 짧은 쪽 결과 수로 나누므로 overlap 1
+		{"K보다 작은 컬렉션", []int64{1, 2, 3}, []int64{1, 2, 3}, true, 1, ""},
+		{"새 버전 누락", []int64{1, 2, 3, 4}, []int64{1, 2, 3}, false, 0.75, "coverage"},
+		{"기존 버전이 비어 있음", nil, []int64{1, 2}, false, 0, "whisky_v1 문서 없음"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewMemoryStore()
+			seedVersion(t, store, from, tt.fromIDs...)
+			seedVersion(t, store, to, tt.toIDs...)
+
+			report, err := ValidateCollection(context.Background(), store, from, to, ValidateOptions{K: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Passed != tt.passed || report.Coverage != tt.coverage {
+				t.Fatalf("passed %v, coverage %.3f, reasons %v", report.Passed, report.Coverage, report.Reasons)
+			}
+			if report.FromCount != len(tt.fromIDs) || report.ToCount != len(tt.toIDs) {
+				t.Fatalf("건수 %d/%d", report.FromCount, report.ToCount)
+			}
+			if tt.passed && (report.SelfRecall != 1 || report.Overlap != 1) {
+				t.Fatalf("self recall %.3f, overlap %.3f", report.SelfRecall, report.Overlap)
+			}
+			if tt.reason != "" && !strings.Contains(strings.Join(report.Reasons, ", "), tt.reason) {
+				t.Fatalf("reasons %v, want %q", report.Reasons, tt.reason)
+			}
+		})
+	}
+}
+
+// failingAliasStore SaveAlias가 항상 실패
+type failingAliasStore struct {
+	repository.AliasStore
+}
+
+func (s *failingAliasStore) SaveAlias(ctx context.Context, alias *domain.CollectionAlias, expected string) error {
+	return errors.New("db down")
+}
+
+// racingAliasStore 첫 저장 직전에 다른 전환이 끼어든다
+type racingAliasStore struct {
+	repository.AliasStore
+	raced bool
+}
+
+func (s *racingAliasStore) SaveAlias(ctx context.Context, alias *domain.CollectionAlias, expected string) error {
+	if !s.raced {
+		s.raced = true
+		other := &domain.CollectionAlias{Alias: alias.Alias, Active: alias.Active, Previous: alias.Previous, UpdatedAt: time.Now()}
+		if err := s.AliasStore.SaveAlias(ctx, other, expected); err != nil {
+			return err
+		}
+	}
+	return s.AliasStore.SaveAlias(ctx, alias, expected)
+}
+
+func newBlueGreen(t *testing.T) (*repository.Collections, repository.AliasStore) {
+	t.Helper()
+	collections, err := repository.NewCollections(versionSchema("whisky_v1"), versionSchema("whisky_v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliases, err := repository.NewFileAliasStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return collections, aliases
+}
+
+func TestCutoverAndRollback(t *testing.T) {
+	ctx := context.Background()
+	collections, aliases := newBlueGreen(t)
+
+	if _, err := Rollback(ctx, aliases, collections, "whisky"); err == nil || !strings.Contains(err.Error(), "전환된 적이 없음") {
+		t.Fatalf("전환 전 롤백 err = %v", err)
+	}
+	if _, err := Cutover(ctx, aliases, collections, "whisky", "whisky_v1"); err == nil || !strings.Contains(err.Error(), "이미") {
+		t.Fatalf("활성 버전으로 전환 err = %v", err)
+	}
+	if _, err := Cutover(ctx, aliases, collections, "brandy", "whisky_v2"); err == nil {
+		t.Fatal("없는 alias 전환 성공")
+	}
+
+	row, err := Cutover(ctx, aliases, collections, "whisky", "whisky_v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.Active != "whisky_v2" || row.Previous != "whisky_v1" {
+		t.Fatalf("전환 %+v", row)
+	}
+	if s, _ := collections.Get("whisky"); s.Name != "whisky_v2" {
+		t.Fatalf("활성 %s", s.Name)
+	}
+	saved, err := aliases.Alias(ctx, "whisky")
+	if err != nil || saved.Active != "whisky_v2" {
+		t.Fatalf("저장된 포인터 %+v, %v", saved, err)
+	}
+
+	// 롤백은 직전 버전으로, 한 번 더 하면 다시 전환
+	for _, want := range []string{"whisky_v1", "whisky_v2"} {
+		row, err := Rollback(ctx, aliases, collections, "whisky")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if active, _ := collections.Active("whisky"); row.Active != want || active != want {
+			t.Fatalf("롤백 %+v, 활성 %s, want %s", row, active, want)
+		}
+	}
+}
+
+func TestCutoverRevertsWhenSaveFails(t *testing.T) {
+	ctx := context.Background()
+	collections, aliases := newBlueGreen(t)
+
+	_, err := Cutover(ctx, &failingAliasStore{AliasStore: aliases}, collections, "whisky", "whisky_v2")
+	if err == nil || !strings.Contains(err.Error(), "db down") {
+		t.Fatalf("err = %v", err)
+	}
+	// 저장에 실패하면 레지스트리도 되돌린다 (재시작 후 상태와 어긋나지 않도록)
+	if active, _ := collections.Active("whisky"); active != "whisky_v1" {
+		t.Fatalf("활성 %s, want whisky_v1", active)
+	}
+	if _, err := aliases.Alias(ctx, "whisky"); !errors.Is(err, repository.ErrAliasNotFound) {
+		t.Fatalf("포인터가 저장됨: %v", err)
+	}
+}
+
+func TestCutoverConflict(t *testing.T) {
+	ctx := context.Background()
+	collections, aliases := newBlueGreen(t)
+	racing := &racingAliasStore{AliasStore: aliases}
+
+	// 포인터가 없을 때 동시에 만든 쪽이 이긴다
+	if _, err := Cutover(ctx, racing, collections, "whisky", "whisky_v2"); !errors.Is(err, repository.ErrAliasConflict) {
+		t.Fatalf("err = %v, want ErrAliasConflict", err)
+	}
+	if active, _ := collections.Active("whisky"); active != "whisky_v1" {
+		t.Fatalf("충돌 후 활성 %s, want whisky_v1", active)
+	}
+
+	// 레지스트리가 늦어도 저장된 포인터 기준으로 직전 버전을 남긴다
+	if _, err := Cutover(ctx, aliases, collections, "whisky", "whisky_v2"); err == nil || !strings.Contains(err.Error(), "이미") {
+		t.Fatalf("이미 전환된 버전 err = %v", err)
+	}
+	row, err := Rollback(ctx, aliases, collections, "whisky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.Active != "whisky_v1" || row.Previous != "whisky_v2" {
+		t.Fatalf("롤백 %+v", row)
+	}
+
+	// 롤백과 전환이 겹치면 나중 쪽은 덮어쓰지 않고 실패
+	racing.raced = false
+	if _, err := Rollback(ctx, racing, collections, "whisky"); !errors.Is(err, repository.ErrAliasConflict) {
+		t.Fatalf("err = %v, want ErrAliasConflict", err)
+	}
+	saved, err := aliases.Alias(ctx, "whisky")
+	if err != nil || saved.Active != "whisky_v2" || saved.Previous != "whisky_v1" {
+		t.Fatalf("저장된 포인터 %+v, %v", saved, err)
+	}
+}
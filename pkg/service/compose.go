@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// MaxComposeInputs 긍정/부정 입력 각각의 최대 개수
+const MaxComposeInputs = 10
+
+// ErrInvalidCompose 벡터 조합 검색 요청 오류
+var ErrInvalidCompose = errors.New("invalid compose query")
+
+// ComposeInput 조합 입력 하나: 자유 텍스트 또는 술 ID (둘 중 하나만)
+type ComposeInput struct {
+	Text string
+	ID   int64
+	// Weight 가중치 (0보다 커야 한다)
+	Weight float64
+}
+
+// ComposeQuery 벡터 조합 검색 요청 ("라가불린 16 같은데 덜 스모키한": 긍정 ID + 부정 텍스트)
+type ComposeQuery struct {
+	Positive []ComposeInput
+	Negative []ComposeInput
+	// VectorType dense 필드 이름 또는 hybrid (비우면 hybrid)
+	VectorType string
+	// Filters 메타데이터 조건 (AND)
+	Filters []repository.Filter
+	// IncludeInputs 입력으로 쓴 술 ID도 결과에 남길지 (기본은 뺀다)
+	IncludeInputs bool
+	Offset        int
+	Limit         int
+	// Diversity 결과 다양화 설정 (nil이면 SearchOptions.Diversity)
+	Diversity *Diversity
+}
+
+// ComposeResult 벡터 조합 검색 응답
+type ComposeResult struct {
+	VectorType string `json:"vector_type"`
+	// Diversified MMR/그룹 제한으로 결과를 다양화했는지
+	Diversified bool        `json:"diversified,omitempty"`
+	Count       int         `json:"count"`
+	Results     []SearchHit `json:"results"`
+}
+
+// Compose 여러 긍정/부정 입력의 벡터를 필드별로 조합한 질의 벡터로 kNN 검색
+// 필드 f의 질의 벡터 = normalize(Σ w·unit(긍정 f 벡터) − Σ w·unit(부정 f 벡터))
+//   - 텍스트 입력: 검색어 임베딩 dense 벡터 (모든 필드에 같은 벡터)
+//   - ID 입력: 벡터 저장소에 저장된 그 필드 벡터 (다시 임베딩하지 않는다)
+//
+// hybrid면 필드마다 kNN 후 SEARCH_FUSION/SEARCH_WEIGHTS로 융합, 어떤 입력에도 벡터가 없는 필드는 건너뛴다
+func (s *Searcher) Compose(ctx context.Context, q ComposeQuery) (*ComposeResult, error) {
+	if err := validateCompose(q); err != nil {
+		return nil, err
+	}
+	q.Offset, q.Limit = normalizePage(q.Offset, q.Limit)
+	collection, err := s.schema()
+	if err != nil {
+		return nil, err
+	}
+	fields, vectorType, err := similarFields(collection, q.VectorType)
+	if err != nil {
+		return nil, err
+	}
+	d := s.diversity(SearchQuery{Diversity: q.Diversity})
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	inputs, err := s.composeVectors(ctx, collection, append(append([]ComposeInput{}, q.Positive...), q.Negative...))
+	if err != nil {
+		return nil, err
+	}
+	positive, negative := inputs[:len(q.Positive)], inputs[len(q.Positive):]
+
+	exclude := make(map[int64]bool)
+	if !q.IncludeInputs {
+		for _, in := range append(append([]ComposeInput{}, q.Positive...), q.Negative...) {
+			if in.ID != 0 {
+				exclude[in.ID] = true
+			}
+		}
+	}
+	limit := q.Offset + q.Limit + len(exclude)
+	if d.Enabled() {
+		limit = max(limit, s.candidates(SearchQuery{Offset: q.Offset, Limit: q.Limit}, false, d)+len(exclude))
+	}
+
+	legs := make(map[string][]repository.Hit, len(fields))
+	for _, field := range fields {
+		vector, ok, err := combineVectors(field, q.Positive, positive, q.Negative, negative)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		hits, err := s.store.Search(ctx, collection, repository.KNNQuery{Field: field, Vector: vector, Filters: q.Filters, Limit: limit})
+		if err != nil {
+			return nil, fmt.Errorf("%s 조합 검색 실패: %w", field, err)
+		}
+		legs[field] = hits
+	}
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("%w: inputs have no vectors for %s", ErrInvalidCompose, vectorType)
+	}
+
+	results, err := s.legResults(ctx, collection, legs, vectorType, exclude, d, q.Offset, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ComposeResult{
+		VectorType:  vectorType,
+		Diversified: d.Enabled(),
+		Count:       len(results),
+		Results:     results,
+	}, nil
+}
+
+// validateCompose 입력 개수, 텍스트/ID 중 하나, 가중치 확인
+func validateCompose(q ComposeQuery) error {
+	if len(q.Positive) == 0 {
+		return fmt.Errorf("%w: at least one positive input is required", ErrInvalidCompose)
+	}
+	if len(q.Positive) > MaxComposeInputs || len(q.Negative) > MaxComposeInputs {
+		return fmt.Errorf("%w: at most %d positive and %d negative inputs are allowed", ErrInvalidCompose, MaxComposeInputs, MaxComposeInputs)
+	}
+	for _, group := range []struct {
+		name   string
+		inputs []ComposeInput
+	}{{"positive", q.Positive}, {"negative", q.Negative}} {
+		for i, in := range group.inputs {
+			hasText := strings.TrimSpace(in.Text) != ""
+			if hasText == (in.ID != 0) {
+				return fmt.Errorf("%w: %s[%d] needs exactly one of text or id", ErrInvalidCompose, group.name, i)
+			}
+			if in.Weight <= 0 || math.IsInf(in.Weight, 0) || math.IsNaN(in.Weight) {
+				return fmt.Errorf("%w: %s[%d] weight must be positive", ErrInvalidCompose, group.name, i)
+			}
+		}
+	}
+	return nil
+}
+
+// composeVectors 입력 순서대로 필드별 벡터 (텍스트는 한 번에 임베딩, ID는 저장된 문서 조회)
+// 텍스트 입력은 필드 구분 없이 같은 벡터이므로 빈 이름("")에 담는다
+func (s *Searcher) composeVectors(ctx context.Context, collection repository.Schema, inputs []ComposeInput) ([]map[string][]float32, error) {
+	vectors := make([]map[string][]float32, len(inputs))
+
+	var texts []string
+	var textIndex []int
+	for i, in := range inputs {
+		if in.ID != 0 {
+			doc, err := s.store.Get(ctx, collection, in.ID)
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, fmt.Errorf("%w: %d", ErrAlcoholNotFound, in.ID)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("술 %d 조회 실패: %w", in.ID, err)
+			}
+			vectors[i] = doc.Vectors
+			continue
+		}
+		texts = append(texts, strings.TrimSpace(in.Text))
+		textIndex = append(textIndex, i)
+	}
+
+	if len(texts) > 0 {
+		embeddings, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("조합 입력 임베딩 실패: %w", err)
+		}
+		if len(embeddings) != len(texts) {
+			return nil, fmt.Errorf("조합 입력 임베딩 결과 수 불일치: %d != %d", len(embeddings), len(texts))
+		}
+		for j, i := range textIndex {
+			vectors[i] = map[string][]float32{"": embeddings[j].Dense}
+		}
+	}
+	return vectors, nil
+}
+
+// combineVectors field 질의 벡터 = normalize(Σ w·unit(긍정) − Σ w·unit(부정))
+// 긍정 입력 중 이 필드 벡터가 하나도 없으면 ok=false, 합이 영벡터면 오류
+func combineVectors(field string, positive []ComposeInput, positiveVectors []map[string][]float32, negative []ComposeInput, negativeVectors []map[string][]float32) ([]float32, bool, error) {
+	var sum []float64
+	found := false
+	add := func(inputs []ComposeInput, vectors []map[string][]float32, sign float64) error {
+		for i, in := range inputs {
+			v, ok := vectors[i][field]
+			if !ok {
+				v, ok = vectors[i][""]
+			}
+			if !ok {
+				continue
+			}
+			if sum == nil {
+				sum = make([]float64, len(v))
+			}
+			if len(v) != len(sum) {
+				return fmt.Errorf("%w: %s vector dimension mismatch (%d != %d)", ErrInvalidCompose, field, len(v), len(sum))
+			}
+			norm := vectorNorm(v)
+			if norm == 0 {
+				continue
+			}
+			if sign > 0 {
+				found = true
+			}
+			scale := sign * in.Weight / norm
+			for k, x := range v {
+				sum[k] += scale * float64(x)
+			}
+		}
+		return nil
+	}
+	if err := add(positive, positiveVectors, 1); err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, nil
+	}
+	if err := add(negative, negativeVectors, -1); err != nil {
+		return nil, false, err
+	}
+
+	var squared float64
+	for _, x := range sum {
+		squared += x * x
+	}
+	if squared == 0 {
+		return nil, false, fmt.Errorf("%w: positive and negative inputs cancel out for %s", ErrInvalidCompose, field)
+	}
+	norm := math.Sqrt(squared)
+	vector := make([]float32, len(sum))
+	for k, x := range sum {
+		vector[k] = float32(x / norm)
+	}
+	return vector, true, nil
+}
+
+// vectorNorm L2 길이
+func vectorNorm(v []float32) float64 {
+	var squared float64
+	for _, x := range v {
+		squared += float64(x) * float64(x)
+	}
+	return math.Sqrt(squared)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidateCompose(t *testing.T) {
+	many := make([]ComposeInput, MaxComposeInputs+1)
+	for i := range many {
+		many[i] = ComposeInput{ID: int64(i + 1), Weight: 1}
+	}
+	tests := []struct {
+		name string
+		q    ComposeQuery
+		want string
+	}{
+		{"긍정 텍스트", ComposeQuery{Positive: []ComposeInput{{Text: "스모키", Weight: 1}}}, ""},
+		{"긍정 ID + 부정 텍스트", ComposeQuery{Positive: []ComposeInput{{ID: 1, Weight: 2}}, Negative: []ComposeInput{{Text: "피트", Weight: 0.5}}}, ""},
+		{"긍정 없음", ComposeQuery{Negative: []ComposeInput{{Text: "피트", Weight: 1}}}, "at least one positive"},
+		{"입력 초과", ComposeQuery{Positive: many}, "at most"},
+		{"부정 입력 초과", ComposeQuery{Positive: many[:1], Negative: many}, "at most"},
+		{"텍스트와 ID 둘 다", ComposeQuery{Positive: []ComposeInput{{Text: "x", ID: 1, Weight: 1}}}, "positive[0] needs exactly one"},
+		{"공백 텍스트", ComposeQuery{Positive: []ComposeInput{{ID: 1, Weight: 1}}, Negative: []ComposeInput{{Text: "  ", Weight: 1}}}, "negative[0] needs exactly one"},
+		{"가중치 0", ComposeQuery{Positive: []ComposeInput{{ID: 1}}}, "weight must be positive"},
+		{"음수 가중치", ComposeQuery{Positive: []ComposeInput{{ID: 1, Weight: -1}}}, "weight must be positive"},
+		{"NaN 가중치", ComposeQuery{Positive: []ComposeInput{{ID: 1, Weight: math.NaN()}}}, "weight must be positive"},
+		{"무한 가중치", ComposeQuery{Positive: []ComposeInput{{ID: 1, Weight: math.Inf(1)}}}, "weight must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCompose(tt.q)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidCompose) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombineVectors(t *testing.T) {
+	one := func(field string, v ...float32) map[string][]float32 { return map[string][]float32{field: v} }
+
+	t.Run("가중 합을 단위 길이로", func(t *testing.T) {
+		// 입력 길이와 무관하게 단위 벡터로 맞춘 뒤 가중치를 곱한다: 3·(1,0) + 1·(0,1) → (3,1)/√10
+		got, ok, err := combineVectors("flavor",
+			[]ComposeInput{{ID: 1, Weight: 3}, {Text: "x", Weight: 1}},
+			[]map[string][]float32{one("flavor", 5, 0), one("", 0, 0.5)},
+			nil, nil)
+		if err != nil || !ok {
+			t.Fatalf("ok %v, err %v", ok, err)
+		}
+		want := []float64{3 / math.Sqrt(10), 1 / math.Sqrt(10)}
+		for i := range want {
+			if math.Abs(float64(got[i])-want[i]) > 1e-6 {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		}
+		if norm := vectorNorm(got); math.Abs(norm-1) > 1e-6 {
+			t.Fatalf("길이 %f", norm)
+		}
+	})
+
+	t.Run("부정 입력을 빼고 정규화", func(t *testing.T) {
+		got, ok, err := combineVectors("flavor",
+			[]ComposeInput{{ID: 1, Weight: 1}}, []map[string][]float32{one("flavor", 1, 1)},
+			[]ComposeInput{{Text: "x", Weight: 1}}, []map[string][]float32{one("", 0, 2)})
+		if err != nil || !ok {
+			t.Fatalf("ok %v, err %v", ok, err)
+		}
+		// (1,1)/√2 − (0,1) = (0.707, −0.293) → 정규화
+		if got[0] <= 0 || got[1] >= 0 || math.Abs(vectorNorm(got)-1) > 1e-6 {
+			t.Fatalf("got %v", got)
+		}
+	})
+
+	t.Run("부정이 긍정을 상쇄하면 오류", func(t *testing.T) {
+		_, _, err := combineVectors("flavor",
+			[]ComposeInput{{ID: 1, Weight: 2}}, []map[string][]float32{one("flavor", 0, 3)},
+			[]ComposeInput{{ID: 2, Weight: 2}}, []map[string][]float32{one("flavor", 0, 1)})
+		if !errors.Is(err, ErrInvalidCompose) || !strings.Contains(err.Error(), "cancel out") {
+			t.Fatalf("err = %v", err)
+		}
+	})
+
+	t.Run("차원이 다르면 오류", func(t *testing.T) {
+		_, _, err := combineVectors("flavor",
+			[]ComposeInput{{ID: 1, Weight: 1}}, []map[string][]float32{one("flavor", 1, 0)},
+			[]ComposeInput{{Text: "x", Weight: 1}}, []map[string][]float32{one("", 1, 0, 0)})
+		if !errors.Is(err, ErrInvalidCompose) || !strings.Contains(err.Error(), "dimension mismatch") {
+			t.Fatalf("err = %v", err)
+		}
+	})
+
+	t.Run("긍정 입력에 필드 벡터가 없으면 건너뜀", func(t *testing.T) {
+		_, ok, err := combineVectors("origin",
+			[]ComposeInput{{ID: 1, Weight: 1}}, []map[string][]float32{one("flavor", 1, 0)},
+			[]ComposeInput{{Text: "x", Weight: 1}}, []map[string][]float32{one("", 1, 0)})
+		if err != nil || ok {
+			t.Fatalf("ok %v, err %v", ok, err)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// VectorHash named vector 입력 내용 해시
+// 전략 버전, 모델 리비전, 입력 텍스트 중 하나라도 바뀌면 달라진다
+func VectorHash(strategyVersion, modelRevision, text string) string {
+	h := sha256.New()
+	for _, part := range []string{strategyVersion, modelRevision, text} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FieldsHash 벡터를 제외한 필드 해시 (JSON 인코딩은 맵 키를 정렬하므로 순서와 무관)
+func FieldsHash(fields map[string]interface{}) string {
+	data, _ := json.Marshal(fields)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
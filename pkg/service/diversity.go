@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Whale0928/embedding-worker/pkg/diversity"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// DefaultDiversityCandidates 다양화할 상위 후보 수 기본값
+const DefaultDiversityCandidates = 50
+
+// Diversity 검색 결과 다양화 설정
+type Diversity struct {
+	// MMR 저장된 벡터로 Maximal Marginal Relevance 재정렬
+	MMR bool
+	// Lambda MMR 관련도 비중 [0, 1] (1이면 관련도만, 0이면 다양성만)
+	Lambda float64
+	// MaxPerGroup 페이로드 필드 값별 최대 결과 수 (distillery_id: 2, categoryGroup: 3)
+	MaxPerGroup map[string]int
+}
+
+// Enabled MMR이나 그룹 제한 중 하나라도 쓰는지
+func (d Diversity) Enabled() bool {
+	if d.MMR {
+		return true
+	}
+	for _, max := range d.MaxPerGroup {
+		if max > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidDiversity 다양화 설정 오류
+var ErrInvalidDiversity = errors.New("invalid diversity options")
+
+// Validate lambda 범위와 그룹 제한 확인
+func (d Diversity) Validate() error {
+	if d.Lambda < 0 || d.Lambda > 1 {
+		return fmt.Errorf("%w: lambda must be between 0 and 1", ErrInvalidDiversity)
+	}
+	for field, max := range d.MaxPerGroup {
+		if max < 0 {
+			return fmt.Errorf("%w: max per %s must be non-negative", ErrInvalidDiversity, field)
+		}
+	}
+	return nil
+}
+
+// Diversity 요청에 다양화 설정이 없을 때 쓰는 기본값 (복사본)
+func (s *Searcher) Diversity() Diversity {
+	d := s.opts.Diversity
+	d.MaxPerGroup = make(map[string]int, len(s.opts.Diversity.MaxPerGroup))
+	for field, max := range s.opts.Diversity.MaxPerGroup {
+		d.MaxPerGroup[field] = max
+	}
+	return d
+}
+
+// diversity 요청 설정, 없으면 기본값
+func (s *Searcher) diversity(q SearchQuery) Diversity {
+	if q.Diversity != nil {
+		return *q.Diversity
+	}
+	return s.opts.Diversity
+}
+
+// diversify 관련도 순 후보에서 MMR/그룹 제한으로 limit개를 골라 고른 순서대로 반환
+// 관련도는 재순위 점수가 있으면 그것, 없으면 검색 점수. fields는 MMR 유사도에 쓸 벡터 필드
+func (s *Searcher) diversify(ctx context.Context, collection repository.Schema, hits []SearchHit, d Diversity, fields []string, limit int) ([]SearchHit, error) {
+	var vectors map[int64]map[string][]float32
+	if d.MMR {
+		var err error
+		if vectors, err = s.storedVectors(ctx, collection, hits, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	candidates := make([]diversity.Candidate, len(hits))
+	for i, hit := range hits {
+		relevance := hit.Score
+		if hit.RerankScore != nil {
+			relevance = *hit.RerankScore
+		}
+		groups := make(map[string]string, len(d.MaxPerGroup))
+		for field := range d.MaxPerGroup {
+			if value, ok := hit.Alcohol[field]; ok && value != nil {
+				groups[field] = fmt.Sprint(value)
+			}
+		}
+		candidates[i] = diversity.Candidate{ID: hit.ID, Relevance: relevance, Vectors: vectors[hit.ID], Groups: groups}
+	}
+
+	selected := diversity.Select(candidates, diversity.Options{
+		MMR:         d.MMR,
+		Lambda:      d.Lambda,
+		Fields:      fields,
+		MaxPerGroup: d.MaxPerGroup,
+		Limit:       limit,
+	})
+	results := make([]SearchHit, 0, len(selected))
+	for _, i := range selected {
+		results = append(results, hits[i])
+	}
+	return results, nil
+}
+
+// storedVectors 후보 문서의 저장된 dense 벡터 (검색 결과에는 벡터가 없어 한 번에 조회한다)
+// 검색 직후 삭제된 문서는 결과에서 빠지며, 유사도 없이 관련도만으로 다룬다
+func (s *Searcher) storedVectors(ctx context.Context, collection repository.Schema, hits []SearchHit, fields []string) (map[int64]map[string][]float32, error) {
+	if len(fields) == 0 {
+		for _, v := range collection.Vectors {
+			fields = append(fields, v.Name)
+		}
+	}
+	ids := make([]int64, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	vectors, err := s.store.GetVectors(ctx, collection, ids, fields)
+	if err != nil {
+		return nil, fmt.Errorf("후보 벡터 조회 실패: %w", err)
+	}
+	return vectors, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// fetchCountingStore 문서 단건 조회와 벡터 일괄 조회 호출을 기록한다
+type fetchCountingStore struct {
+	repository.VectorStore
+
+	mu          sync.Mutex
+	gets        int
+	vectorCalls [][]int64
+	vectorNames [][]string
+}
+
+func (s *fetchCountingStore) Get(ctx context.Context, collection repository.Schema, id int64) (*repository.Document, error) {
+	s.mu.Lock()
+	s.gets++
+	s.mu.Unlock()
+	return s.VectorStore.Get(ctx, collection, id)
+}
+
+func (s *fetchCountingStore) GetVectors(ctx context.Context, collection repository.Schema, ids []int64, names []string) (map[int64]map[string][]float32, error) {
+	s.mu.Lock()
+	s.vectorCalls = append(s.vectorCalls, append([]int64{}, ids...))
+	s.vectorNames = append(s.vectorNames, append([]string{}, names...))
+	s.mu.Unlock()
+	return s.VectorStore.GetVectors(ctx, collection, ids, names)
+}
+
+func TestSearcherMMRFetchesVectorsOnce(t *testing.T) {
+	s := testWhiskySchema()
+	store := &fetchCountingStore{VectorStore: repository.NewMemoryStore()}
+	seedSearchDocs(t, store, s)
+	collections, err := repository.NewCollections(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	searcher := NewSearcher(&fakeEmbedder{dim: 4}, store, collections, SearchOptions{Collection: s.Name, Fusion: repository.FusionRRF})
+
+	result, err := searcher.Hybrid(context.Background(), SearchQuery{Keyword: "스모키", Limit: 2, Diversity: &Diversity{MMR: true, Lambda: 0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 2 || !result.Diversified {
+		t.Fatalf("결과 %+v", result)
+	}
+	// 후보 벡터는 문서별 Get 없이 한 번에, hybrid면 컬렉션의 모든 dense 필드
+	if store.gets != 0 || len(store.vectorCalls) != 1 {
+		t.Fatalf("Get %d회, GetVectors %d회", store.gets, len(store.vectorCalls))
+	}
+	ids := store.vectorCalls[0]
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	want := []string{schema.VectorFlavor, schema.VectorIdentity, schema.VectorOrigin, schema.VectorSpec}
+	if !equalHitIDs(ids, []int64{1, 2, 3, 4}) || !reflect.DeepEqual(store.vectorNames[0], want) {
+		t.Fatalf("GetVectors ids %v, names %v", ids, store.vectorNames[0])
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/normalize"
+)
+
+// Strategy Alcohol → 임베딩 입력 변환 전략
+type Strategy interface {
+	// Name 전략 이름
+	Name() string
+	// Version 전략 정의가 바뀌면 달라지는 버전 (저장된 벡터가 어떤 전략으로 만들어졌는지 기록)
+	Version() string
+	// Build 벡터 이름별 텍스트, RAG 컨텍스트, 필터 메타데이터 생성
+	Build(a *domain.Alcohol) (*EmbeddingInput, error)
+}
+
+// EmbeddingInput 전략이 만든 임베딩 입력
+type EmbeddingInput struct {
+	ID              int64                  `json:"id"`
+	StrategyVersion string                 `json:"strategy_version"`
+	Texts           map[string]string      `json:"texts"`
+	RAGContext      string                 `json:"rag_context"`
+	FilterMetadata  map[string]interface{} `json:"filter_metadata"`
+	// Issues 정규화하지 못한 ABV/Age/Volume 값 (메타데이터에서는 생략된다)
+	Issues []*normalize.UnparseableError `json:"issues,omitempty"`
+}
+
+// builtinWhiskyVersion 코드로 정의된 v2 전략 버전 (텍스트 조합 규칙을 바꾸면 올린다)
+const builtinWhiskyVersion = "whisky-v2-builtin"
+
+// WhiskyV2Strategy Python과 바이트 단위로 동일한 텍스트를 만드는 내장 v2 전략
+type WhiskyV2Strategy struct{}
+
+func NewWhiskyV2Strategy() *WhiskyV2Strategy {
+	return &WhiskyV2Strategy{}
+}
+
+func (s *WhiskyV2Strategy) Name() string {
+	return "whisky-v2"
+}
+
+func (s *WhiskyV2Strategy) Version() string {
+	return builtinWhiskyVersion
+}
+
+func (s *WhiskyV2Strategy) Build(a *domain.Alcohol) (*EmbeddingInput, error) {
+	strategy := BuildWhiskyStrategy(a)
+	return &EmbeddingInput{
+		ID:              strategy.ID,
+		StrategyVersion: builtinWhiskyVersion,
+		Texts:           strategy.Texts(),
+		RAGContext:      strategy.RAGContext,
+		FilterMetadata:  strategy.FilterMetadata,
+		Issues:          strategy.Issues,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// maxJobErrors 작업 기록에 남기는 최근 실패 수
+const maxJobErrors = 100
+
+// NewIndexJob 새 인덱싱 작업 (ID는 시작 시각 + 임의 접미사)
+func NewIndexJob(collection string, opts IndexOptions, modelRevision, strategyVersion string) *domain.IndexJob {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	now := time.Now()
+	return &domain.IndexJob{
+		ID:              now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix),
+		Collection:      collection,
+		Status:          domain.JobRunning,
+		FromID:          opts.FromID,
+		ToID:            opts.ToID,
+		Limit:           opts.Limit,
+		ModelRevision:   modelRevision,
+		StrategyVersion: strategyVersion,
+		StartedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// CheckResume 같은 모델/전략으로만 재개할 수 있다 (섞이면 한 컬렉션에 다른 공간의 벡터가 생긴다)
+func CheckResume(job *domain.IndexJob, modelRevision, strategyVersion string) error {
+	if job.Status == domain.JobCompleted {
+		return fmt.Errorf("작업 %s는 이미 완료됨", job.ID)
+	}
+	if job.ModelRevision != modelRevision {
+		return fmt.Errorf("모델이 다름: 작업 %s, 현재 %s", job.ModelRevision, modelRevision)
+	}
+	if job.StrategyVersion != strategyVersion {
+		return fmt.Errorf("전략 버전이 다름: 작업 %s, 현재 %s", job.StrategyVersion, strategyVersion)
+	}
+	return nil
+}
+
+// RunJob 작업의 커밋 지점 다음부터 실행하며 체크포인트마다 작업을 저장한다
+// opts의 범위(FromID, ToID, Limit)는 작업 기록으로 덮어쓴다
+func (ix *Indexer) RunJob(ctx context.Context, jobs repository.JobStore, job *domain.IndexJob, opts IndexOptions, progress func(IndexProgress)) (*IndexReport, error) {
+	opts.DryRun = false
+	opts.ModelRevision = job.ModelRevision
+	opts.FromID = job.FromID
+	if job.LastCommittedID > 0 {
+		opts.FromID = job.LastCommittedID + 1
+	}
+	opts.ToID = job.ToID
+	opts.Limit = 0
+	if job.Limit > 0 {
+		opts.Limit = job.Limit - job.Processed
+		if opts.Limit <= 0 {
+			return &IndexReport{StrategyVersion: job.StrategyVersion, Failures: []IndexFailure{}}, finishJob(jobs, job, nil)
+		}
+	}
+
+	failures, err := decodeJobErrors(job.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = domain.JobRunning
+	job.FinishedAt = nil
+	job.UpdatedAt = time.Now()
+	if err := jobs.Save(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// 취소되어도 이미 커밋된 지점은 기록한다
+	saveCtx := context.WithoutCancel(ctx)
+	opts.OnCheckpoint = func(c Checkpoint) error {
+		job.LastCommittedID = c.LastID
+		job.Processed += c.Processed
+		job.Indexed += c.Indexed
+		job.Failed += c.Failed
+		job.Skipped += c.Skipped
+		if len(c.Failures) > 0 {
+			failures = append(failures, c.Failures...)
+			if len(failures) > maxJobErrors {
+				failures = failures[len(failures)-maxJobErrors:]
+			}
+			job.Errors = encodeJobErrors(failures)
+			job.LastError = c.Failures[len(c.Failures)-1].Error
+		}
+		job.UpdatedAt = time.Now()
+		return jobs.Save(saveCtx, job)
+	}
+
+	report, runErr := ix.Run(ctx, opts, progress)
+	if err := finishJob(jobs, job, runErr); err != nil {
+		return report, err
+	}
+	return report, runErr
+}
+
+// finishJob 실행 결과로 작업 상태 확정 (취소된 ctx와 무관하게 저장)
+func finishJob(jobs repository.JobStore, job *domain.IndexJob, runErr error) error {
+	now := time.Now()
+	job.UpdatedAt = now
+	switch {
+	case runErr == nil:
+		job.Status = domain.JobCompleted
+		job.FinishedAt = &now
+	case errors.Is(runErr, context.Canceled):
+		job.Status = domain.JobInterrupted
+	default:
+		job.Status = domain.JobFailed
+		job.LastError = runErr.Error()
+	}
+	return jobs.Save(context.Background(), job)
+}
+
+func decodeJobErrors(raw string) ([]IndexFailure, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var failures []IndexFailure
+	if err := json.Unmarshal([]byte(raw), &failures); err != nil {
+		return nil, fmt.Errorf("작업 실패 내역 파싱 실패: %w", err)
+	}
+	return failures, nil
+}
+
+func encodeJobErrors(failures []IndexFailure) string {
+	data, _ := json.Marshal(failures)
+	return string(data)
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// newTestDB 테스트마다 독립된 in-memory SQLite
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&domain.Region{}, &domain.Distillery{}, &domain.TastingTag{}, &domain.Alcohol{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// fakeEmbedder 텍스트 해시로 만든 결정적 벡터 (임베딩한 텍스트 수를 센다)
+type fakeEmbedder struct {
+	dim int
+
+	mu    sync.Mutex
+	texts int
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, texts []string) ([]embedder.Embedding, error) {
+	e.mu.Lock()
+	e.texts += len(texts)
+	e.mu.Unlock()
+
+	embeddings := make([]embedder.Embedding, len(texts))
+	for i, text := range texts {
+		embeddings[i] = embedder.Embedding{Dense: fakeVector(text, e.dim), Sparse: fakeSparse(text)}
+	}
+	return embeddings, nil
+}
+
+func (e *fakeEmbedder) embedded() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.texts
+}
+
+// fakeVector 텍스트 해시를 시드로 한 단위 벡터
+func fakeVector(text string, dim int) []float32 {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	seed := h.Sum64()
+	v := make([]float32, dim)
+	var norm float64
+	for i := range v {
+		seed = seed*6364136223846793005 + 1442695040888963407
+		v[i] = float32(seed>>40)/float32(1<<24) - 0.5
+		norm += float64(v[i]) * float64(v[i])
+	}
+	for i := range v {
+		v[i] /= float32(math.Sqrt(norm))
+	}
+	return v
+}
+
+// fakeSparse 공백으로 나눈 단어 → 1
+func fakeSparse(text string) map[string]float32 {
+	sparse := make(map[string]float32)
+	for _, word := range strings.Fields(text) {
+		sparse[strings.ToLower(word)] = 1
+	}
+	return sparse
+}
+
+func testWhiskySchema() repository.Schema {
+	return repository.Schema{
+		Name: "whisky",
+		Vectors: []repository.VectorField{
+			{Name: schema.VectorFlavor, Dimension: 4},
+			{Name: schema.VectorIdentity, Dimension: 4},
+			{Name: schema.VectorOrigin, Dimension: 4},
+			{Name: schema.VectorSpec, Dimension: 4},
+		},
+	}
+}
+
+func createAlcohol(t *testing.T, db *gorm.DB, id int64, name string, modified time.Time) {
+	t.Helper()
+	alcohol := domain.Alcohol{ID: id, KorName: name, EngName: name, Type: "Single Malt", LastModifyAt: &modified}
+	if err := db.Create(&alcohol).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// countingStore 문서별 Upsert 횟수를 센다
+type countingStore struct {
+	repository.VectorStore
+
+	mu       sync.Mutex
+	upserted map[int64]int
+}
+
+func newCountingStore() *countingStore {
+	return &countingStore{VectorStore: repository.NewMemoryStore(), upserted: make(map[int64]int)}
+}
+
+func (s *countingStore) Upsert(ctx context.Context, collection repository.Schema, docs []repository.Document) error {
+	s.mu.Lock()
+	for _, doc := range docs {
+		s.upserted[doc.ID]++
+	}
+	s.mu.Unlock()
+	return s.VectorStore.Upsert(ctx, collection, docs)
+}
+
+// hookJobStore Save마다 hook을 부른다 (hook이 오류를 반환하면 저장하지 않는다)
+type hookJobStore struct {
+	repository.JobStore
+	hook func(job *domain.IndexJob) error
+}
+
+func (s *hookJobStore) Save(ctx context.Context, job *domain.IndexJob) error {
+	if s.hook != nil {
+		if err := s.hook(job); err != nil {
+			return err
+		}
+	}
+	return s.JobStore.Save(ctx, job)
+}
+
+func newJobIndexer(t *testing.T, count int) (*Indexer, *countingStore, *hookJobStore) {
+	t.Helper()
+	db := newTestDB(t)
+	modified := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for id := int64(1); id <= int64(count); id++ {
+		createAlcohol(t, db, id, "술", modified)
+	}
+	files, err := repository.NewFileJobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newCountingStore()
+	indexer := NewIndexer(repository.NewAlcoholRepository(db), NewWhiskyV2Strategy(), &fakeEmbedder{dim: 4}, store, testWhiskySchema())
+	return indexer, store, &hookJobStore{JobStore: files}
+}
+
+// 한 배치씩 순서대로 커밋되도록 워커 1개, 배치 2개
+var jobTestOptions = IndexOptions{PageSize: 2, BatchSize: 2, Workers: 1}
+
+func TestRunJobResumesAfterInterrupt(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"전체", 0, 9},
+		{"limit은 남은 건수만", 7, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer, store, jobs := newJobIndexer(t, 9)
+			opts := jobTestOptions
+			opts.Limit = tt.limit
+			job := NewIndexJob("whisky", opts, "rev", indexer.strategy.Version())
+
+			// 4번까지 커밋되면 중단 (취소 뒤 이미 받은 배치도 커밋 기록이 남는다)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			jobs.hook = func(job *domain.IndexJob) error {
+				if job.LastCommittedID >= 4 {
+					cancel()
+				}
+				return nil
+			}
+			if _, err := indexer.RunJob(ctx, jobs, job, opts, nil); !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want context.Canceled", err)
+			}
+			jobs.hook = nil
+
+			interrupted, err := jobs.Get(context.Background(), job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if interrupted.Status != domain.JobInterrupted || interrupted.FinishedAt != nil {
+				t.Fatalf("중단 상태 %s", interrupted.Status)
+			}
+			resumedFrom := interrupted.Processed
+			if interrupted.LastCommittedID < 4 || int64(resumedFrom) != interrupted.LastCommittedID || resumedFrom >= tt.want {
+				t.Fatalf("커밋 지점 %d, 처리 %d", interrupted.LastCommittedID, resumedFrom)
+			}
+			if err := CheckResume(interrupted, "rev", indexer.strategy.Version()); err != nil {
+				t.Fatal(err)
+			}
+
+			// 재개는 LastCommittedID+1부터, limit은 남은 건수만
+			report, err := indexer.RunJob(context.Background(), jobs, interrupted, IndexOptions{PageSize: 2, BatchSize: 2, Workers: 1, FromID: 1, Limit: 100}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Read != tt.want-resumedFrom {
+				t.Fatalf("재개 읽음 %d, want %d", report.Read, tt.want-resumedFrom)
+			}
+
+			finished, err := jobs.Get(context.Background(), job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if finished.Status != domain.JobCompleted || finished.FinishedAt == nil {
+				t.Fatalf("완료 상태 %s", finished.Status)
+			}
+			if finished.Processed != tt.want || finished.Indexed != tt.want || finished.Skipped != 0 || finished.LastCommittedID != int64(tt.want) {
+				t.Fatalf("작업 집계 %+v", finished)
+			}
+			// 모든 ID가 정확히 한 번 저장된다
+			if len(store.upserted) != tt.want {
+				t.Fatalf("저장된 문서 %d개, want %d", len(store.upserted), tt.want)
+			}
+			for id := int64(1); id <= int64(tt.want); id++ {
+				if store.upserted[id] != 1 {
+					t.Fatalf("id %d 저장 %d회", id, store.upserted[id])
+				}
+			}
+		})
+	}
+}
+
+func TestRunJobLimitAlreadyReached(t *testing.T) {
+	indexer, store, jobs := newJobIndexer(t, 3)
+	job := NewIndexJob("whisky", IndexOptions{Limit: 2}, "rev", indexer.strategy.Version())
+	job.Status = domain.JobInterrupted
+	job.Processed = 2
+	job.LastCommittedID = 2
+
+	report, err := indexer.RunJob(context.Background(), jobs, job, jobTestOptions, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Read != 0 || len(store.upserted) != 0 {
+		t.Fatalf("남은 건수가 없는데 처리함: %+v", report)
+	}
+	if saved, err := jobs.Get(context.Background(), job.ID); err != nil || saved.Status != domain.JobCompleted {
+		t.Fatalf("상태 %+v, %v", saved, err)
+	}
+}
+
+func TestRunJobCheckpointFailure(t *testing.T) {
+	indexer, _, jobs := newJobIndexer(t, 6)
+	job := NewIndexJob("whisky", jobTestOptions, "rev", indexer.strategy.Version())
+
+	// 실행 시작 저장은 통과, 첫 체크포인트 저장만 실패
+	failed := false
+	jobs.hook = func(job *domain.IndexJob) error {
+		if job.LastCommittedID > 0 && !failed {
+			failed = true
+			return errors.New("disk full")
+		}
+		return nil
+	}
+	_, err := indexer.RunJob(context.Background(), jobs, job, jobTestOptions, nil)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("err = %v", err)
+	}
+
+	saved, err := jobs.Get(context.Background(), job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != domain.JobFailed || !strings.Contains(saved.LastError, "disk full") {
+		t.Fatalf("실패 상태 %s (%s)", saved.Status, saved.LastError)
+	}
+}
+
+func TestCheckResume(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		model    string
+		strategy string
+		want     string
+	}{
+		{"중단된 작업", domain.JobInterrupted, "rev", "v2", ""},
+		{"실패한 작업", domain.JobFailed, "rev", "v2", ""},
+		{"실행 중으로 남은 작업 (프로세스 종료)", domain.JobRunning, "rev", "v2", ""},
+		{"완료된 작업", domain.JobCompleted, "rev", "v2", "이미 완료"},
+		{"모델이 다름", domain.JobInterrupted, "rev2", "v2", "모델이 다름"},
+		{"전략이 다름", domain.JobInterrupted, "rev", "v3", "전략 버전이 다름"},
+		{"둘 다 다르면 모델부터", domain.JobInterrupted, "rev2", "v3", "모델이 다름"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := NewIndexJob("whisky", IndexOptions{}, "rev", "v2")
+			job.Status = tt.status
+			err := CheckResume(job, tt.model, tt.strategy)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,564 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// 인덱싱 기본값
+const (
+	DefaultIndexPageSize  = 100
+	DefaultIndexBatchSize = 16
+	DefaultIndexWorkers   = 2
+)
+
+// IndexOptions 인덱싱 범위와 동시성
+type IndexOptions struct {
+	// FromID, ToID ID 범위 (양끝 포함, 0이면 제한 없음)
+	FromID int64
+	ToID   int64
+	// Limit 최대 처리 건수 (0이면 제한 없음)
+	Limit int
+	// DryRun 전략 텍스트만 만들고 임베딩/저장은 하지 않는다
+	DryRun bool
+	// IDs 지정하면 범위 대신 이 ID들만 처리 (오름차순, 증분 동기화용)
+	IDs []int64
+	// ModelRevision 벡터 내용 해시에 들어가는 모델 리비전 (모델이 바뀌면 전부 다시 임베딩)
+	ModelRevision string
+
+	// PageSize DB 페이지 크기
+	PageSize int
+	// BatchSize 임베딩/저장 배치 크기
+	BatchSize int
+	// Workers 동시에 임베딩하는 배치 수
+	Workers int
+
+	// OnCheckpoint 앞선 배치가 모두 끝나 커밋 지점이 전진할 때마다 호출 (오류를 반환하면 중단)
+	OnCheckpoint func(Checkpoint) error
+}
+
+func (o *IndexOptions) applyDefaults() {
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultIndexPageSize
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultIndexBatchSize
+	}
+	if o.Workers <= 0 {
+		o.Workers = DefaultIndexWorkers
+	}
+}
+
+// IndexFailure 처리하지 못한 문서
+type IndexFailure struct {
+	ID    int64  `json:"id"`
+	Stage string `json:"stage"`
+	Error string `json:"error"`
+}
+
+// IndexProgress 진행 상황 (배치 저장 후마다 보고)
+// Skipped는 내용 해시가 모두 같아 임베딩/저장을 생략한 문서 수
+// HashLookupFailed는 저장된 해시를 조회하지 못해 해시 비교 없이 전부 다시 임베딩/저장한 배치 수
+type IndexProgress struct {
+	Read             int           `json:"read"`
+	Indexed          int           `json:"indexed"`
+	Failed           int           `json:"failed"`
+	Skipped          int           `json:"skipped"`
+	Issues           int           `json:"issues"`
+	HashLookupFailed int           `json:"hash_lookup_failed"`
+	LastID           int64         `json:"last_id"`
+	Elapsed          time.Duration `json:"elapsed"`
+}
+
+// Checkpoint 새로 커밋된 배치들의 결과 (누적값이 아니라 이번에 커밋된 만큼)
+// 배치는 순서와 관계없이 끝나므로, LastID 이하의 모든 배치가 끝났을 때만 전진한다
+type Checkpoint struct {
+	LastID    int64
+	Processed int
+	Indexed   int
+	Failed    int
+	Skipped   int
+	Failures  []IndexFailure
+}
+
+// IndexReport 인덱싱 결과
+type IndexReport struct {
+	IndexProgress
+	StrategyVersion string         `json:"strategy_version"`
+	Failures        []IndexFailure `json:"failures"`
+	// HashLookupError 마지막 해시 조회 오류 (HashLookupFailed > 0일 때)
+	HashLookupError string `json:"hash_lookup_error,omitempty"`
+}
+
+// 파이프라인 단계 이름
+const (
+	stageBuild = "build"
+	stageEmbed = "embed"
+	stageStore = "store"
+)
+
+// indexBatch 단계 사이를 흐르는 배치
+type indexBatch struct {
+	// seq 읽은 순서 (커밋 지점 계산용)
+	seq      int
+	alcohols []domain.Alcohol
+	inputs   []*EmbeddingInput
+	// docs 새 문서 (전체 저장), updates 기존 문서의 바뀐 벡터/필드 (부분 갱신)
+	docs     []repository.Document
+	updates  []repository.Document
+	failures []IndexFailure
+	issues   int
+	skipped  int
+	// hashErr 저장된 해시 조회 오류 (있으면 전부 새 문서로 보고 전체 저장했다)
+	hashErr error
+}
+
+// Indexer DB → 전략 텍스트 → 임베딩 → 벡터 저장소 파이프라인
+type Indexer struct {
+	alcohols *repository.AlcoholRepository
+	strategy Strategy
+	embedder embedder.Embedder
+	store    repository.VectorStore
+	schema   repository.Schema
+}
+
+// NewIndexer dry-run만 할 때는 embedder와 store가 nil이어도 된다
+func NewIndexer(alcohols *repository.AlcoholRepository, strategy Strategy, emb embedder.Embedder, store repository.VectorStore, s repository.Schema) *Indexer {
+	return &Indexer{
+		alcohols: alcohols,
+		strategy: strategy,
+		embedder: emb,
+		store:    store,
+		schema:   s,
+	}
+}
+
+// Run 읽기 → 임베딩(workers개) → 저장 단계를 채널로 연결해 실행
+// 문서 단위 실패는 보고서에 모으고 계속 진행, DB 오류나 취소는 중단한다
+func (ix *Indexer) Run(ctx context.Context, opts IndexOptions, progress func(IndexProgress)) (*IndexReport, error) {
+	opts.applyDefaults()
+	if !opts.DryRun && (ix.embedder == nil || ix.store == nil) {
+		return nil, errors.New("임베더와 벡터 저장소가 필요함 (dry-run이 아님)")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	started := time.Now()
+	read := make(chan indexBatch, opts.Workers)
+	embedded := make(chan indexBatch, opts.Workers)
+
+	var readErr error
+	var readCount int
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		defer close(read)
+		readCount, readErr = ix.read(ctx, opts, read)
+	}()
+
+	var workers sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for batch := range read {
+				select {
+				case embedded <- ix.embed(ctx, batch, opts):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(embedded)
+	}()
+
+	var checkpointErr error
+	commit := newCommitter()
+	report := &IndexReport{StrategyVersion: ix.strategy.Version(), Failures: []IndexFailure{}}
+	for batch := range embedded {
+		if checkpointErr != nil {
+			// 중단 후 남은 배치는 버린다
+			continue
+		}
+		if !opts.DryRun {
+			ix.write(ctx, &batch)
+		}
+
+		report.Read += len(batch.alcohols)
+		report.Indexed += len(batch.docs) + len(batch.updates)
+		report.Failed += len(batch.failures)
+		report.Skipped += batch.skipped
+		report.Issues += batch.issues
+		report.Failures = append(report.Failures, batch.failures...)
+		if batch.hashErr != nil {
+			report.HashLookupFailed++
+			report.HashLookupError = batch.hashErr.Error()
+		}
+		for _, a := range batch.alcohols {
+			if a.ID > report.LastID {
+				report.LastID = a.ID
+			}
+		}
+		report.Elapsed = time.Since(started)
+		if progress != nil {
+			progress(report.IndexProgress)
+		}
+
+		if checkpoint, ok := commit.add(batch); ok && opts.OnCheckpoint != nil {
+			if err := opts.OnCheckpoint(checkpoint); err != nil {
+				checkpointErr = fmt.Errorf("체크포인트 저장 실패: %w", err)
+				cancel()
+			}
+		}
+	}
+
+	// 취소되면 워커가 먼저 끝날 수 있으므로 읽기 종료를 기다린다
+	<-readDone
+
+	sort.Slice(report.Failures, func(i, j int) bool {
+		return report.Failures[i].ID < report.Failures[j].ID
+	})
+	if checkpointErr != nil {
+		return report, checkpointErr
+	}
+	if readErr != nil {
+		return report, readErr
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if readCount != report.Read {
+		return report, fmt.Errorf("처리 건수 불일치: 읽음 %d, 처리 %d", readCount, report.Read)
+	}
+	return report, nil
+}
+
+// read keyset 페이지(또는 ID 목록)로 읽어 BatchSize 단위로 내보낸다, 읽은 건수 반환
+func (ix *Indexer) read(ctx context.Context, opts IndexOptions, out chan<- indexBatch) (int, error) {
+	if opts.IDs != nil {
+		return ix.readIDs(ctx, opts, out)
+	}
+
+	afterID := opts.FromID - 1
+	if afterID < 0 {
+		afterID = 0
+	}
+
+	count, seq := 0, 0
+	for {
+		pageSize := opts.PageSize
+		if opts.Limit > 0 && opts.Limit-count < pageSize {
+			pageSize = opts.Limit - count
+		}
+		if pageSize <= 0 {
+			return count, nil
+		}
+
+		page, err := ix.alcohols.Iterate(ctx, afterID, pageSize)
+		if err != nil {
+			return count, err
+		}
+
+		done := len(page) < pageSize
+		if opts.ToID > 0 {
+			for i, a := range page {
+				if a.ID > opts.ToID {
+					page, done = page[:i], true
+					break
+				}
+			}
+		}
+
+		sent, err := sendBatches(ctx, page, opts.BatchSize, &seq, out)
+		count += sent
+		if err != nil {
+			return count, err
+		}
+
+		if done || len(page) == 0 {
+			return count, nil
+		}
+		afterID = page[len(page)-1].ID
+	}
+}
+
+// readIDs ID 목록을 PageSize씩 조회 (DB에서 사라진 ID는 건너뛴다)
+func (ix *Indexer) readIDs(ctx context.Context, opts IndexOptions, out chan<- indexBatch) (int, error) {
+	ids := opts.IDs
+	if opts.Limit > 0 && len(ids) > opts.Limit {
+		ids = ids[:opts.Limit]
+	}
+
+	count, seq := 0, 0
+	for start := 0; start < len(ids); start += opts.PageSize {
+		end := start + opts.PageSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		page, err := ix.alcohols.FindByIDs(ctx, ids[start:end])
+		if err != nil {
+			return count, err
+		}
+		sent, err := sendBatches(ctx, page, opts.BatchSize, &seq, out)
+		count += sent
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// sendBatches 페이지를 BatchSize 단위로 나눠 순번을 붙여 보낸다
+func sendBatches(ctx context.Context, page []domain.Alcohol, batchSize int, seq *int, out chan<- indexBatch) (int, error) {
+	sent := 0
+	for start := 0; start < len(page); start += batchSize {
+		end := start + batchSize
+		if end > len(page) {
+			end = len(page)
+		}
+		select {
+		case out <- indexBatch{seq: *seq, alcohols: page[start:end]}:
+		case <-ctx.Done():
+			return sent, ctx.Err()
+		}
+		sent += end - start
+		*seq++
+	}
+	return sent, nil
+}
+
+// embed 전략 텍스트 생성 후 내용 해시가 바뀐 벡터만 벡터 이름별로 한 번에 임베딩
+// 저장소에 없는 문서는 전체 저장, 있는 문서는 바뀐 벡터/필드만 부분 갱신, 모두 같으면 건너뛴다
+func (ix *Indexer) embed(ctx context.Context, batch indexBatch, opts IndexOptions) indexBatch {
+	for i := range batch.alcohols {
+		a := &batch.alcohols[i]
+		input, err := ix.strategy.Build(a)
+		if err != nil {
+			batch.failures = append(batch.failures, IndexFailure{ID: a.ID, Stage: stageBuild, Error: err.Error()})
+			continue
+		}
+		batch.issues += len(input.Issues)
+		batch.inputs = append(batch.inputs, input)
+	}
+	if opts.DryRun || len(batch.inputs) == 0 {
+		return batch
+	}
+
+	names := make([]string, 0, len(ix.schema.Vectors))
+	for _, v := range ix.schema.Vectors {
+		names = append(names, v.Name)
+	}
+	byID := make(map[int64]*domain.Alcohol, len(batch.alcohols))
+	for i := range batch.alcohols {
+		byID[batch.alcohols[i].ID] = &batch.alcohols[i]
+	}
+	docs := make([]repository.Document, len(batch.inputs))
+	for i, input := range batch.inputs {
+		docs[i] = toDocument(byID[input.ID], input, names, opts.ModelRevision)
+	}
+	// 조회에 실패하면 해시 비교 없이 전체 저장한다 (느리지만 결과는 같다), 보고서에 횟수를 남긴다
+	stored, err := ix.storedHashes(ctx, docs, names)
+	if err != nil {
+		batch.hashErr = err
+	}
+
+	for _, name := range names {
+		field := schema.HashField(name)
+		pending := make([]int, 0, len(docs))
+		for i, doc := range docs {
+			if existing, ok := stored[doc.ID]; !ok || existing[field] != doc.Fields[field] {
+				pending = append(pending, i)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		texts := make([]string, len(pending))
+		for j, i := range pending {
+			texts[j] = batch.inputs[i].Texts[name]
+		}
+		result, err := ix.embedder.Embed(ctx, texts)
+		if err == nil && len(result) != len(texts) {
+			err = fmt.Errorf("임베딩 결과 개수 불일치: %d != %d", len(result), len(texts))
+		}
+		if err != nil {
+			for _, input := range batch.inputs {
+				batch.failures = append(batch.failures, IndexFailure{ID: input.ID, Stage: stageEmbed, Error: fmt.Sprintf("%s: %v", name, err)})
+			}
+			return batch
+		}
+		// sparse 키워드는 identity 벡터(이름/증류소/카테고리) 텍스트의 토큰 가중치를 쓴다
+		for j, i := range pending {
+			docs[i].Vectors[name] = result[j].Dense
+			if name == schema.VectorIdentity {
+				docs[i].Sparse = result[j].Sparse
+			}
+		}
+	}
+
+	for _, doc := range docs {
+		existing, ok := stored[doc.ID]
+		if !ok {
+			batch.docs = append(batch.docs, doc)
+			continue
+		}
+		if update, changed := partialUpdate(doc, existing, names); changed {
+			batch.updates = append(batch.updates, update)
+		} else {
+			batch.skipped++
+		}
+	}
+	return batch
+}
+
+// storedHashes 저장소에 있는 문서의 내용 해시
+func (ix *Indexer) storedHashes(ctx context.Context, docs []repository.Document, names []string) (map[int64]map[string]interface{}, error) {
+	ids := make([]int64, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	fields := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		fields = append(fields, schema.HashField(name))
+	}
+	fields = append(fields, schema.FieldsHash)
+
+	stored, err := ix.store.GetFields(ctx, ix.schema, ids, fields)
+	if err != nil {
+		return nil, fmt.Errorf("저장된 해시 조회 실패: %w", err)
+	}
+	return stored, nil
+}
+
+// write 새 문서는 Upsert, 기존 문서는 Update (실패하면 해당 문서를 store 단계 실패로 옮긴다)
+func (ix *Indexer) write(ctx context.Context, batch *indexBatch) {
+	if len(batch.docs) > 0 {
+		if err := ix.store.Upsert(ctx, ix.schema, batch.docs); err != nil {
+			batch.failures = append(batch.failures, storeFailures(batch.docs, err)...)
+			batch.docs = nil
+		}
+	}
+	if len(batch.updates) > 0 {
+		if err := ix.store.Update(ctx, ix.schema, batch.updates); err != nil {
+			batch.failures = append(batch.failures, storeFailures(batch.updates, err)...)
+			batch.updates = nil
+		}
+	}
+}
+
+func storeFailures(docs []repository.Document, err error) []IndexFailure {
+	failures := make([]IndexFailure, 0, len(docs))
+	for _, doc := range docs {
+		failures = append(failures, IndexFailure{ID: doc.ID, Stage: stageStore, Error: err.Error()})
+	}
+	return failures
+}
+
+// toDocument 임베딩 입력 → 벡터가 비어 있는 저장 문서 (필드 + 내용 해시)
+func toDocument(a *domain.Alcohol, input *EmbeddingInput, names []string, modelRevision string) repository.Document {
+	doc := repository.Document{
+		ID:      input.ID,
+		Vectors: make(map[string][]float32, len(names)),
+		Fields:  make(map[string]interface{}, len(input.FilterMetadata)+len(names)+5),
+	}
+
+	for key, value := range input.FilterMetadata {
+		doc.Fields[key] = value
+	}
+	doc.Fields["kor_name"] = a.KorName
+	doc.Fields["eng_name"] = a.EngName
+	if a.ImageURL != nil {
+		doc.Fields["image_url"] = *a.ImageURL
+	}
+	doc.Fields["rag_context"] = input.RAGContext
+	doc.Fields["strategy_version"] = input.StrategyVersion
+	doc.Fields[schema.FieldsHash] = FieldsHash(doc.Fields)
+
+	for _, name := range names {
+		doc.Fields[schema.HashField(name)] = VectorHash(input.StrategyVersion, modelRevision, input.Texts[name])
+	}
+	return doc
+}
+
+// partialUpdate 저장된 해시와 비교해 다시 임베딩한 벡터와 바뀐 필드만 담은 갱신 문서
+// 필드가 바뀌었으면 벡터 해시를 뺀 전체 필드를 보내고, 이번에 값이 없는 메타데이터 키는 nil로 지운다
+func partialUpdate(doc repository.Document, stored map[string]interface{}, names []string) (repository.Document, bool) {
+	update := repository.Document{
+		ID:      doc.ID,
+		Vectors: doc.Vectors,
+		Sparse:  doc.Sparse,
+		Fields:  make(map[string]interface{}),
+	}
+	for _, name := range names {
+		field := schema.HashField(name)
+		if stored[field] != doc.Fields[field] {
+			update.Fields[field] = doc.Fields[field]
+		}
+	}
+	if stored[schema.FieldsHash] != doc.Fields[schema.FieldsHash] {
+		vectorHashes := make(map[string]bool, len(names))
+		for _, name := range names {
+			vectorHashes[schema.HashField(name)] = true
+		}
+		for key, value := range doc.Fields {
+			if !vectorHashes[key] {
+				update.Fields[key] = value
+			}
+		}
+		for _, key := range optionalMetadataKeys() {
+			if _, ok := doc.Fields[key]; !ok {
+				update.Fields[key] = nil
+			}
+		}
+	}
+	return update, len(update.Vectors) > 0 || len(update.Fields) > 0
+}
+
+// committer 순서 없이 끝나는 배치를 seq 순으로 모아 연속된 구간만 커밋
+type committer struct {
+	next    int
+	pending map[int]indexBatch
+}
+
+func newCommitter() *committer {
+	return &committer{pending: make(map[int]indexBatch)}
+}
+
+// add 배치를 받아 커밋 지점이 전진했으면 새로 커밋된 만큼의 Checkpoint 반환
+func (c *committer) add(batch indexBatch) (Checkpoint, bool) {
+	c.pending[batch.seq] = batch
+
+	var checkpoint Checkpoint
+	advanced := false
+	for {
+		done, ok := c.pending[c.next]
+		if !ok {
+			break
+		}
+		delete(c.pending, c.next)
+		c.next++
+		advanced = true
+
+		checkpoint.LastID = done.alcohols[len(done.alcohols)-1].ID
+		checkpoint.Processed += len(done.alcohols)
+		checkpoint.Indexed += len(done.docs) + len(done.updates)
+		checkpoint.Failed += len(done.failures)
+		checkpoint.Skipped += done.skipped
+		checkpoint.Failures = append(checkpoint.Failures, done.failures...)
+	}
+	return checkpoint, advanced
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// writeRecordingStore Upsert/Update로 보낸 문서를 기록한다 (getFieldsErr가 있으면 GetFields 실패)
+type writeRecordingStore struct {
+	repository.VectorStore
+	getFieldsErr error
+
+	mu       sync.Mutex
+	upserted []repository.Document
+	updated  []repository.Document
+}
+
+func (s *writeRecordingStore) Upsert(ctx context.Context, collection repository.Schema, docs []repository.Document) error {
+	s.mu.Lock()
+	s.upserted = append(s.upserted, docs...)
+	s.mu.Unlock()
+	return s.VectorStore.Upsert(ctx, collection, docs)
+}
+
+func (s *writeRecordingStore) Update(ctx context.Context, collection repository.Schema, docs []repository.Document) error {
+	s.mu.Lock()
+	s.updated = append(s.updated, docs...)
+	s.mu.Unlock()
+	return s.VectorStore.Update(ctx, collection, docs)
+}
+
+func (s *writeRecordingStore) GetFields(ctx context.Context, collection repository.Schema, ids []int64, names []string) (map[int64]map[string]interface{}, error) {
+	if s.getFieldsErr != nil {
+		return nil, s.getFieldsErr
+	}
+	return s.VectorStore.GetFields(ctx, collection, ids, names)
+}
+
+// reset 기록만 지운다 (저장된 문서는 유지)
+func (s *writeRecordingStore) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upserted, s.updated = nil, nil
+}
+
+func seedIndexedAlcohols(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	region := domain.Region{ID: 10, KorName: "아일라", EngName: "Islay", Continent: "Europe", Description: ptr("피트")}
+	if err := db.Create(&region).Error; err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, a := range []domain.Alcohol{
+		{ID: 1, KorName: "라프로익", EngName: "Laphroaig", Type: "Single Malt", ABV: ptr("40"), RegionID: ptr(int64(10)), LastModifyAt: &modified},
+		{ID: 2, KorName: "글렌피딕", EngName: "Glenfiddich", Type: "Single Malt", ABV: ptr("43"), LastModifyAt: &modified},
+	} {
+		if err := db.Create(&a).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newHashIndexer(t *testing.T) (*Indexer, *gorm.DB, *fakeEmbedder, *writeRecordingStore) {
+	t.Helper()
+	db := newTestDB(t)
+	seedIndexedAlcohols(t, db)
+	emb := &fakeEmbedder{dim: 4}
+	store := &writeRecordingStore{VectorStore: repository.NewMemoryStore()}
+	indexer := NewIndexer(repository.NewAlcoholRepository(db), NewWhiskyV2Strategy(), emb, store, testWhiskySchema())
+
+	report, err := indexer.Run(context.Background(), IndexOptions{ModelRevision: "rev"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Indexed != 2 || len(store.upserted) != 2 || emb.embedded() != 8 {
+		t.Fatalf("첫 인덱싱 %+v, upsert %d, 임베딩 %d", report.IndexProgress, len(store.upserted), emb.embedded())
+	}
+	store.reset()
+	return indexer, db, emb, store
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestIndexerSkipsUnchangedDocuments(t *testing.T) {
+	indexer, _, emb, store := newHashIndexer(t)
+
+	report, err := indexer.Run(context.Background(), IndexOptions{ModelRevision: "rev"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Skipped != 2 || report.Indexed != 0 || report.HashLookupFailed != 0 {
+		t.Fatalf("재실행 %+v", report.IndexProgress)
+	}
+	if emb.embedded() != 8 || len(store.upserted) != 0 || len(store.updated) != 0 {
+		t.Fatalf("변경이 없는데 임베딩 %d, upsert %d, update %d", emb.embedded(), len(store.upserted), len(store.updated))
+	}
+
+	// 모델 리비전이 바뀌면 모든 벡터를 다시 임베딩한다
+	report, err = indexer.Run(context.Background(), IndexOptions{ModelRevision: "rev2"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Indexed != 2 || emb.embedded() != 16 || len(store.updated) != 2 || len(store.updated[0].Vectors) != 4 {
+		t.Fatalf("모델 변경 %+v, 임베딩 %d", report.IndexProgress, emb.embedded())
+	}
+}
+
+func TestIndexerFieldOnlyChangeSendsNoTensors(t *testing.T) {
+	indexer, db, emb, store := newHashIndexer(t)
+	ctx := context.Background()
+
+	if err := db.Model(&domain.Alcohol{}).Where("id = 1").Update("image_url", "https://img/1.png").Error; err != nil {
+		t.Fatal(err)
+	}
+	report, err := indexer.Run(ctx, IndexOptions{ModelRevision: "rev"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Indexed != 1 || report.Skipped != 1 || emb.embedded() != 8 {
+		t.Fatalf("이미지 변경 %+v, 임베딩 %d", report.IndexProgress, emb.embedded())
+	}
+	if len(store.upserted) != 0 || len(store.updated) != 1 {
+		t.Fatalf("upsert %d, update %d", len(store.upserted), len(store.updated))
+	}
+	update := store.updated[0]
+	if update.ID != 1 || len(update.Vectors) != 0 || update.Sparse != nil {
+		t.Fatalf("텐서가 갱신에 포함됨: %+v", update)
+	}
+	if update.Fields["image_url"] != "https://img/1.png" || update.Fields[schema.FieldsHash] == nil {
+		t.Fatalf("갱신 필드 %v", sortedKeys(update.Fields))
+	}
+	for _, name := range schema.DenseVectors {
+		if _, ok := update.Fields[schema.HashField(name)]; ok {
+			t.Fatalf("바뀌지 않은 %s 해시가 갱신에 포함됨", name)
+		}
+	}
+
+	// 값이 사라진 필드는 nil로 보내 지운다
+	store.reset()
+	if err := db.Model(&domain.Alcohol{}).Where("id = 1").Update("image_url", nil).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := indexer.Run(ctx, IndexOptions{ModelRevision: "rev"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.updated) != 1 || store.updated[0].Fields["image_url"] != nil {
+		t.Fatalf("이미지 삭제 갱신 %+v", store.updated)
+	}
+	if value, ok := store.updated[0].Fields["image_url"]; !ok || value != nil {
+		t.Fatalf("image_url 삭제 누락: %v", store.updated[0].Fields)
+	}
+	doc, err := store.Get(ctx, testWhiskySchema(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Fields["image_url"]; ok || len(doc.Vectors) != 4 {
+		t.Fatalf("저장된 문서 %+v", doc)
+	}
+}
+
+func TestIndexerReembedsOnlyChangedVector(t *testing.T) {
+	indexer, db, emb, store := newHashIndexer(t)
+	ctx := context.Background()
+	before, err := store.Get(ctx, testWhiskySchema(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 지역 설명은 origin 텍스트에만 들어간다 (rag_context와 메타데이터는 그대로)
+	if err := db.Model(&domain.Region{}).Where("id = 10").Update("description", "바닷가 피트").Error; err != nil {
+		t.Fatal(err)
+	}
+	report, err := indexer.Run(ctx, IndexOptions{ModelRevision: "rev"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Indexed != 1 || report.Skipped != 1 || emb.embedded() != 9 {
+		t.Fatalf("origin 변경 %+v, 임베딩 %d", report.IndexProgress, emb.embedded())
+	}
+	if len(store.updated) != 1 {
+		t.Fatalf("update %d", len(store.updated))
+	}
+	update := store.updated[0]
+	if len(update.Vectors) != 1 || update.Vectors[schema.VectorOrigin] == nil || update.Sparse != nil {
+		t.Fatalf("갱신 벡터 %v", update.Vectors)
+	}
+	if keys := sortedKeys(update.Fields); len(keys) != 1 || keys[0] != schema.HashField(schema.VectorOrigin) {
+		t.Fatalf("갱신 필드 %v", keys)
+	}
+
+	after, err := store.Get(ctx, testWhiskySchema(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range schema.DenseVectors {
+		changed := !equalVector(before.Vectors[name], after.Vectors[name])
+		if changed != (name == schema.VectorOrigin) {
+			t.Fatalf("%s 벡터 변경 %v", name, changed)
+		}
+	}
+}
+
+func TestIndexerHashLookupFailure(t *testing.T) {
+	indexer, _, emb, store := newHashIndexer(t)
+	store.getFieldsErr = errors.New("vespa 503")
+
+	report, err := indexer.Run(context.Background(), IndexOptions{ModelRevision: "rev"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 조회에 실패하면 해시 비교 없이 전체 저장하고, 보고서에 남긴다
+	if report.HashLookupFailed != 1 || !strings.Contains(report.HashLookupError, "vespa 503") {
+		t.Fatalf("해시 조회 실패 보고 %+v (%q)", report.IndexProgress, report.HashLookupError)
+	}
+	if report.Indexed != 2 || len(store.upserted) != 2 || emb.embedded() != 16 {
+		t.Fatalf("전체 저장 %+v, upsert %d, 임베딩 %d", report.IndexProgress, len(store.upserted), emb.embedded())
+	}
+}
+
+func equalVector(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// outbox 기본값
+const (
+	DefaultOutboxBatchSize   = 100
+	DefaultOutboxMaxAttempts = 10
+	// DefaultOutboxLease 가져간 이벤트를 다른 워커가 다시 가져가지 못하는 시간 (한 묶음 처리 시간보다 길게)
+	DefaultOutboxLease = 5 * time.Minute
+
+	// 재시도 간격: 1s, 2s, 4s, ... 최대 5분
+	outboxBackoffBase = time.Second
+	outboxBackoffMax  = 5 * time.Minute
+)
+
+// OutboxOptions outbox 소비 설정
+type OutboxOptions struct {
+	// BatchSize 한 번에 잠그는 이벤트 수
+	BatchSize int
+	// MaxAttempts 이 횟수만큼 실패하면 격리(parked)
+	MaxAttempts int
+	// Lease 처리 중인 이벤트를 다른 워커가 가져가지 못하는 시간
+	Lease time.Duration
+	// Index 재인덱싱 옵션 (ModelRevision, 배치/워커 수)
+	Index IndexOptions
+}
+
+func (o *OutboxOptions) applyDefaults() {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultOutboxBatchSize
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultOutboxMaxAttempts
+	}
+	if o.Lease <= 0 {
+		o.Lease = DefaultOutboxLease
+	}
+}
+
+// OutboxReport 한 번 소비한 결과
+type OutboxReport struct {
+	Claimed int `json:"claimed"`
+	Indexed int `json:"indexed"`
+	Skipped int `json:"skipped"`
+	Deleted int `json:"deleted"`
+	Retried int `json:"retried"`
+	Parked  int `json:"parked"`
+}
+
+// ConsumeOutbox 이벤트 한 묶음을 lease로 가져와 처리 (임베딩 중에는 DB 트랜잭션/잠금을 잡지 않는다)
+// 이벤트를 술 ID로 풀어(region/distillery/tasting tag는 연결된 술) 같은 ID는 마지막 작업만 반영하고,
+// upsert인데 DB에 없는 술은 삭제로 처리한다. 실패한 이벤트는 backoff 후 재시도, 한도를 넘기면 격리한다
+func (ix *Indexer) ConsumeOutbox(ctx context.Context, outbox *repository.OutboxRepository, opts OutboxOptions) (*OutboxReport, error) {
+	opts.applyDefaults()
+	report := &OutboxReport{}
+
+	events, err := outbox.Lease(ctx, opts.BatchSize, opts.Lease, opts.MaxAttempts)
+	if err != nil {
+		return report, err
+	}
+	report.Claimed = len(events)
+	if len(events) == 0 {
+		return report, nil
+	}
+
+	errs := ix.applyOutbox(ctx, events, opts.Index, report)
+
+	done := make([]int64, 0, len(events))
+	failed := make([]domain.OutboxEvent, 0, len(errs))
+	now := time.Now()
+	for _, event := range events {
+		eventErr, ok := errs[event.ID]
+		if !ok {
+			done = append(done, event.ID)
+			continue
+		}
+		// attempts는 lease할 때 이미 올라가 있다
+		event.LastError = eventErr.Error()
+		if event.Attempts >= opts.MaxAttempts {
+			event.ParkedAt = &now
+			event.AvailableAt = nil
+			report.Parked++
+		} else {
+			availableAt := now.Add(outboxBackoff(event.Attempts))
+			event.AvailableAt = &availableAt
+			report.Retried++
+		}
+		failed = append(failed, event)
+	}
+	// 처리 중 취소되어도 결과는 저장한다 (저장하지 못하면 lease가 끝난 뒤 다시 처리)
+	if err := outbox.Finish(context.WithoutCancel(ctx), done, failed); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// applyOutbox 이벤트를 반영하고 실패한 이벤트 ID → 오류 반환
+func (ix *Indexer) applyOutbox(ctx context.Context, events []domain.OutboxEvent, opts IndexOptions, report *OutboxReport) map[int64]error {
+	errs := make(map[int64]error)
+	// 술 ID → 마지막 작업, 그 술에 영향을 준 이벤트
+	ops := make(map[int64]string)
+	owners := make(map[int64][]int64)
+
+	for _, event := range events {
+		switch {
+		case event.Op != domain.OutboxUpsert && event.Op != domain.OutboxDelete:
+			errs[event.ID] = fmt.Errorf("알 수 없는 작업: %s", event.Op)
+		case event.EntityType == domain.OutboxAlcohol:
+			ops[event.EntityID] = event.Op
+			owners[event.EntityID] = append(owners[event.EntityID], event.ID)
+		default:
+			// 연관 엔티티는 추가/수정/삭제 모두 연결된 술을 다시 인덱싱
+			ids, err := ix.alcohols.FindIDsByRelation(ctx, event.EntityType, event.EntityID)
+			if err != nil {
+				errs[event.ID] = err
+				continue
+			}
+			for _, id := range ids {
+				ops[id] = domain.OutboxUpsert
+				owners[id] = append(owners[id], event.ID)
+			}
+		}
+	}
+
+	fail := func(ids []int64, err error) {
+		for _, id := range ids {
+			for _, eventID := range owners[id] {
+				errs[eventID] = err
+			}
+		}
+	}
+
+	upserts := make([]int64, 0, len(ops))
+	deletes := make([]int64, 0)
+	for id, op := range ops {
+		if op == domain.OutboxDelete {
+			deletes = append(deletes, id)
+		} else {
+			upserts = append(upserts, id)
+		}
+	}
+	sort.Slice(upserts, func(i, j int) bool { return upserts[i] < upserts[j] })
+
+	// 이벤트 이후 DB에서 지워진 술은 삭제로 처리
+	if len(upserts) > 0 {
+		existing, err := ix.alcohols.ExistingIDs(ctx, upserts)
+		if err != nil {
+			fail(upserts, err)
+			upserts = nil
+		} else {
+			deletes = append(deletes, missingIDs(upserts, existing)...)
+			upserts = existing
+		}
+	}
+
+	if len(deletes) > 0 {
+		if err := ix.store.Delete(ctx, ix.schema, deletes); err != nil {
+			fail(deletes, fmt.Errorf("벡터 저장소 삭제 실패: %w", err))
+		} else {
+			report.Deleted += len(deletes)
+		}
+	}
+
+	if len(upserts) > 0 {
+		opts.IDs = upserts
+		opts.FromID, opts.ToID, opts.Limit = 0, 0, 0
+		opts.DryRun = false
+		opts.OnCheckpoint = nil
+		index, err := ix.Run(ctx, opts, nil)
+		if err != nil {
+			fail(upserts, err)
+			return errs
+		}
+		report.Indexed += index.Indexed
+		report.Skipped += index.Skipped
+		for _, failure := range index.Failures {
+			fail([]int64{failure.ID}, fmt.Errorf("%s: %s", failure.Stage, failure.Error))
+		}
+	}
+	return errs
+}
+
+// missingIDs all 중 existing에 없는 ID (둘 다 오름차순)
+func missingIDs(all, existing []int64) []int64 {
+	missing := make([]int64, 0)
+	j := 0
+	for _, id := range all {
+		for j < len(existing) && existing[j] < id {
+			j++
+		}
+		if j >= len(existing) || existing[j] != id {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// outboxBackoff attempts번째 실패 후 대기 시간
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBackoffBase
+	for i := 1; i < attempts && delay < outboxBackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > outboxBackoffMax {
+		delay = outboxBackoffMax
+	}
+	return delay
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// failingEmbedder 항상 실패하는 임베더
+type failingEmbedder struct{}
+
+func (failingEmbedder) Embed(ctx context.Context, texts []string) ([]embedder.Embedding, error) {
+	return nil, errors.New("임베딩 서버 오류")
+}
+
+func TestConsumeOutbox(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&domain.OutboxEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	outbox, err := repository.NewOutboxRepository(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := repository.NewMemoryStore()
+	s := testWhiskySchema()
+	alcohols := repository.NewAlcoholRepository(db)
+
+	t0 := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	createAlcohol(t, db, 1, "글렌피딕", t0)
+	events := []domain.OutboxEvent{
+		{ID: 1, EntityType: domain.OutboxAlcohol, EntityID: 1, Op: domain.OutboxUpsert},
+		// DB에 없는 술의 upsert는 삭제로 처리
+		{ID: 2, EntityType: domain.OutboxAlcohol, EntityID: 2, Op: domain.OutboxUpsert},
+	}
+	for i := range events {
+		if err := db.Create(&events[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 임베딩 실패: 이벤트는 남고 재시도 시각이 잡힌다
+	failing := NewIndexer(alcohols, NewWhiskyV2Strategy(), failingEmbedder{}, store, s)
+	report, err := failing.ConsumeOutbox(ctx, outbox, OutboxOptions{MaxAttempts: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Claimed != 2 || report.Retried != 1 || report.Deleted != 1 {
+		t.Fatalf("실패 처리 %+v", report)
+	}
+	var state domain.OutboxState
+	if err := db.First(&state, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if state.Attempts != 1 || state.LastError == "" || state.AvailableAt == nil || !state.AvailableAt.After(time.Now()) || state.ParkedAt != nil {
+		t.Fatalf("재시도 상태 %+v", state)
+	}
+
+	// backoff 중에는 가져가지 않는다
+	indexer := NewIndexer(alcohols, NewWhiskyV2Strategy(), &fakeEmbedder{dim: 4}, store, s)
+	report, err = indexer.ConsumeOutbox(ctx, outbox, OutboxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Claimed != 0 {
+		t.Fatalf("backoff 중 처리 %+v", report)
+	}
+
+	// 재시도 시각이 지나면 처리하고 이벤트와 상태를 지운다
+	if err := db.Model(&domain.OutboxState{}).Where("event_id = ?", 1).Update("available_at", t0).Error; err != nil {
+		t.Fatal(err)
+	}
+	report, err = indexer.ConsumeOutbox(ctx, outbox, OutboxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Claimed != 1 || report.Indexed != 1 {
+		t.Fatalf("재시도 처리 %+v", report)
+	}
+	if _, err := store.Get(ctx, s, 1); err != nil {
+		t.Fatalf("술 1 미반영: %v", err)
+	}
+	var left int64
+	db.Model(&domain.OutboxEvent{}).Count(&left)
+	var states int64
+	db.Model(&domain.OutboxState{}).Count(&states)
+	if left != 0 || states != 0 {
+		t.Fatalf("남은 이벤트 %d, 상태 %d", left, states)
+	}
+}
+
+func TestConsumeOutboxParks(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&domain.OutboxEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	outbox, err := repository.NewOutboxRepository(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createAlcohol(t, db, 1, "글렌피딕", time.Now())
+	if err := db.Create(&domain.OutboxEvent{ID: 1, EntityType: domain.OutboxAlcohol, EntityID: 1, Op: domain.OutboxUpsert}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	failing := NewIndexer(repository.NewAlcoholRepository(db), NewWhiskyV2Strategy(), failingEmbedder{}, repository.NewMemoryStore(), testWhiskySchema())
+	report, err := failing.ConsumeOutbox(ctx, outbox, OutboxOptions{MaxAttempts: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Parked != 1 || report.Retried != 0 {
+		t.Fatalf("격리 %+v", report)
+	}
+	var state domain.OutboxState
+	if err := db.First(&state, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if state.ParkedAt == nil || state.AvailableAt != nil {
+		t.Fatalf("격리 상태 %+v", state)
+	}
+}
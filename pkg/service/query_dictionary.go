@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Whale0928/embedding-worker/pkg/query"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// LoadQueryDictionary DB의 지역/증류소 한글·영문 이름으로 검색어 해석 사전 생성
+func LoadQueryDictionary(ctx context.Context, alcohols *repository.AlcoholRepository) (*query.Dictionary, error) {
+	regions, err := alcohols.Regions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	distilleries, err := alcohols.Distilleries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := &query.Dictionary{
+		Regions:      make([]query.Entry, 0, len(regions)),
+		Distilleries: make([]query.Entry, 0, len(distilleries)),
+	}
+	for _, r := range regions {
+		dict.Regions = append(dict.Regions, query.Entry{ID: r.ID, Names: []string{r.KorName, r.EngName}})
+	}
+	for _, d := range distilleries {
+		dict.Distilleries = append(dict.Distilleries, query.Entry{ID: d.ID, Names: []string{d.KorName, d.EngName}})
+	}
+	return dict, nil
+}
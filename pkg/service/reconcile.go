@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// reconcileDeleteBatch 고아 문서 삭제 배치 크기
+const reconcileDeleteBatch = 100
+
+// StaleDocument 내용 해시가 DB와 다른 문서
+type StaleDocument struct {
+	ID int64 `json:"id"`
+	// Fields 값이 다른 해시 필드 (flavor_hash, fields_hash, ...)
+	Fields []string `json:"fields"`
+}
+
+// ReconcileReport DB와 벡터 저장소 비교 결과
+type ReconcileReport struct {
+	Collection      string `json:"collection"`
+	StrategyVersion string `json:"strategy_version"`
+	ModelRevision   string `json:"model_revision"`
+	DBCount         int    `json:"db_count"`
+	StoreCount      int    `json:"store_count"`
+	// Missing DB에는 있지만 저장소에 없는 ID
+	Missing []int64 `json:"missing"`
+	// Orphaned 저장소에만 남아 있는 ID (DB에서 삭제됨)
+	Orphaned []int64 `json:"orphaned"`
+	// Stale 저장소에 있지만 지금 인덱싱하면 해시가 달라지는 문서
+	Stale []StaleDocument `json:"stale"`
+	// Failures 전략 텍스트를 만들지 못해 비교하지 못한 문서
+	Failures []IndexFailure   `json:"failures"`
+	Repair   *ReconcileRepair `json:"repair,omitempty"`
+	Elapsed  time.Duration    `json:"elapsed"`
+}
+
+// ReconcileRepair 복구 결과
+type ReconcileRepair struct {
+	Deleted int          `json:"deleted"`
+	Index   *IndexReport `json:"index,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// Drifted 차이가 하나라도 있는지
+func (r *ReconcileReport) Drifted() bool {
+	return len(r.Missing) > 0 || len(r.Orphaned) > 0 || len(r.Stale) > 0
+}
+
+// Reconcile 저장소를 VisitFields로 순회해 ID별 내용 해시를 모은 뒤 DB를 keyset 페이지로 읽으며 비교
+// 해시는 인덱싱과 같은 방식(전략 버전 + opts.ModelRevision + 입력 텍스트)으로 계산한다
+func (ix *Indexer) Reconcile(ctx context.Context, opts IndexOptions) (*ReconcileReport, error) {
+	opts.applyDefaults()
+	started := time.Now()
+
+	names := make([]string, 0, len(ix.schema.Vectors))
+	hashFields := make([]string, 0, len(ix.schema.Vectors)+1)
+	for _, v := range ix.schema.Vectors {
+		names = append(names, v.Name)
+		hashFields = append(hashFields, schema.HashField(v.Name))
+	}
+	hashFields = append(hashFields, schema.FieldsHash)
+
+	// 1. 저장소 쪽 ID → 해시 (해시 필드만 읽고 벡터는 받지 않는다)
+	stored := make(map[int64]map[string]interface{})
+	err := ix.store.VisitFields(ctx, ix.schema, hashFields, func(id int64, hashes map[string]interface{}) error {
+		stored[id] = hashes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{
+		Collection:      ix.schema.Name,
+		StrategyVersion: ix.strategy.Version(),
+		ModelRevision:   opts.ModelRevision,
+		StoreCount:      len(stored),
+		Missing:         []int64{},
+		Orphaned:        []int64{},
+		Stale:           []StaleDocument{},
+		Failures:        []IndexFailure{},
+	}
+
+	// 2. DB 쪽을 읽으며 비교 (비교한 ID는 stored에서 지운다)
+	var afterID int64
+	for {
+		page, err := ix.alcohols.Iterate(ctx, afterID, opts.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		for i := range page {
+			a := &page[i]
+			report.DBCount++
+
+			hashes, ok := stored[a.ID]
+			if !ok {
+				report.Missing = append(report.Missing, a.ID)
+				continue
+			}
+			delete(stored, a.ID)
+
+			input, err := ix.strategy.Build(a)
+			if err != nil {
+				report.Failures = append(report.Failures, IndexFailure{ID: a.ID, Stage: stageBuild, Error: err.Error()})
+				continue
+			}
+			expected := toDocument(a, input, names, opts.ModelRevision)
+			var changed []string
+			for _, field := range hashFields {
+				if hashes[field] != expected.Fields[field] {
+					changed = append(changed, field)
+				}
+			}
+			if len(changed) > 0 {
+				report.Stale = append(report.Stale, StaleDocument{ID: a.ID, Fields: changed})
+			}
+		}
+		if len(page) < opts.PageSize {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+
+	// 3. 남은 저장소 ID는 DB에 없는 고아 문서
+	for id := range stored {
+		report.Orphaned = append(report.Orphaned, id)
+	}
+	sort.Slice(report.Orphaned, func(i, j int) bool { return report.Orphaned[i] < report.Orphaned[j] })
+
+	report.Elapsed = time.Since(started)
+	return report, nil
+}
+
+// Repair 고아 문서를 삭제하고 누락/오래된 문서를 다시 인덱싱 (오래된 문서는 바뀐 벡터만 갱신된다)
+func (ix *Indexer) Repair(ctx context.Context, report *ReconcileReport, opts IndexOptions, progress func(IndexProgress)) error {
+	repair := &ReconcileRepair{}
+	report.Repair = repair
+
+	for start := 0; start < len(report.Orphaned); start += reconcileDeleteBatch {
+		end := start + reconcileDeleteBatch
+		if end > len(report.Orphaned) {
+			end = len(report.Orphaned)
+		}
+		if err := ix.store.Delete(ctx, ix.schema, report.Orphaned[start:end]); err != nil {
+			repair.Error = err.Error()
+			return err
+		}
+		repair.Deleted += end - start
+	}
+
+	ids := make([]int64, 0, len(report.Missing)+len(report.Stale))
+	ids = append(ids, report.Missing...)
+	for _, stale := range report.Stale {
+		ids = append(ids, stale.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	opts.IDs = ids
+	opts.FromID, opts.ToID, opts.Limit = 0, 0, 0
+	opts.DryRun = false
+	index, err := ix.Run(ctx, opts, progress)
+	repair.Index = index
+	if err != nil {
+		repair.Error = err.Error()
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// fieldsOnlyStore 벡터까지 읽는 Visit을 막는다 (Reconcile은 VisitFields만 써야 한다)
+type fieldsOnlyStore struct {
+	repository.VectorStore
+}
+
+func (s *fieldsOnlyStore) Visit(ctx context.Context, collection repository.Schema, fn func(doc *repository.Document) error) error {
+	return errors.New("전체 문서 순회 금지")
+}
+
+func TestReconcileAndRepair(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	modified := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for id := int64(1); id <= 4; id++ {
+		createAlcohol(t, db, id, "술", modified)
+	}
+	emb := &fakeEmbedder{dim: 4}
+	memory := repository.NewMemoryStore()
+	s := testWhiskySchema()
+	indexer := NewIndexer(repository.NewAlcoholRepository(db), NewWhiskyV2Strategy(), emb, &fieldsOnlyStore{VectorStore: memory}, s)
+	opts := IndexOptions{ModelRevision: "rev", PageSize: 2}
+
+	if _, err := indexer.Run(ctx, opts, nil); err != nil {
+		t.Fatal(err)
+	}
+	report, err := indexer.Reconcile(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Drifted() || report.DBCount != 4 || report.StoreCount != 4 {
+		t.Fatalf("인덱싱 직후 차이 %+v", report)
+	}
+
+	// 2번은 저장소에서 사라지고, 99번은 DB에 없고, 3번은 이름이 바뀜
+	if err := memory.Delete(ctx, s, []int64{2}); err != nil {
+		t.Fatal(err)
+	}
+	orphan := repository.Document{ID: 99, Fields: map[string]interface{}{"kor_name": "삭제됨"}, Vectors: map[string][]float32{}}
+	for _, v := range s.Vectors {
+		orphan.Vectors[v.Name] = fakeVector("삭제됨", 4)
+	}
+	if err := memory.Upsert(ctx, s, []repository.Document{orphan}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&domain.Alcohol{}).Where("id = 3").Update("kor_name", "새 이름").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = indexer.Reconcile(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.DBCount != 4 || report.StoreCount != 4 {
+		t.Fatalf("건수 DB %d, 저장소 %d", report.DBCount, report.StoreCount)
+	}
+	if !reflect.DeepEqual(report.Missing, []int64{2}) || !reflect.DeepEqual(report.Orphaned, []int64{99}) {
+		t.Fatalf("누락 %v, 고아 %v", report.Missing, report.Orphaned)
+	}
+	if len(report.Stale) != 1 || report.Stale[0].ID != 3 {
+		t.Fatalf("오래된 문서 %+v", report.Stale)
+	}
+	stale := report.Stale[0].Fields
+	if !slices.Contains(stale, schema.HashField(schema.VectorIdentity)) || !slices.Contains(stale, schema.FieldsHash) || slices.Contains(stale, schema.HashField(schema.VectorFlavor)) {
+		t.Fatalf("바뀐 해시 필드 %v", stale)
+	}
+
+	before := emb.embedded()
+	if err := indexer.Repair(ctx, report, opts, nil); err != nil {
+		t.Fatal(err)
+	}
+	if report.Repair.Deleted != 1 || report.Repair.Index == nil || report.Repair.Index.Indexed != 2 || report.Repair.Error != "" {
+		t.Fatalf("복구 결과 %+v", report.Repair)
+	}
+	// 누락 문서는 전체, 오래된 문서는 바뀐 벡터만 임베딩
+	if emb.embedded()-before >= 8 {
+		t.Fatalf("복구 임베딩 %d건", emb.embedded()-before)
+	}
+
+	if _, err := memory.Get(ctx, s, 99); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("고아 문서가 남음: %v", err)
+	}
+	doc, err := memory.Get(ctx, s, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Fields["kor_name"] != "새 이름" {
+		t.Fatalf("3번 문서 %v", doc.Fields["kor_name"])
+	}
+	if _, err := memory.Get(ctx, s, 2); err != nil {
+		t.Fatalf("누락 문서가 복구되지 않음: %v", err)
+	}
+
+	report, err = indexer.Reconcile(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Drifted() || report.StoreCount != 4 {
+		t.Fatalf("복구 뒤 차이 %+v", report)
+	}
+}
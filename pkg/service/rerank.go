@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+)
+
+// 재순위 기본값
+const (
+	// DefaultRerankBatchSize 한 번에 추론하는 (질의, 문서) 쌍 수
+	DefaultRerankBatchSize = 16
+	// MaxRerankDocuments POST /rerank 한 요청의 최대 문서 수
+	MaxRerankDocuments = 1000
+)
+
+var (
+	// ErrRerankerDisabled 재순위 모델이 설정되지 않음
+	ErrRerankerDisabled = errors.New("reranker is not configured")
+	// ErrEmptyRerankQuery 재순위 질의 없음
+	ErrEmptyRerankQuery = errors.New("query is required")
+)
+
+// RerankResult 재순위 결과 단건
+type RerankResult struct {
+	// Index 입력 문서 순서 (0부터)
+	Index int
+	Score float64
+}
+
+// Reranker cross-encoder로 (질의, 문서) 쌍을 배치 단위로 채점해 다시 정렬
+type Reranker struct {
+	scorer    embedder.Scorer
+	batchSize int
+}
+
+// NewReranker 생성자 (batchSize가 0 이하면 DefaultRerankBatchSize)
+func NewReranker(scorer embedder.Scorer, batchSize int) *Reranker {
+	if batchSize <= 0 {
+		batchSize = DefaultRerankBatchSize
+	}
+	return &Reranker{scorer: scorer, batchSize: batchSize}
+}
+
+// Rerank documents를 점수 내림차순으로 (동점이면 입력 순서), topN > 0이면 상위 topN개만
+func (r *Reranker) Rerank(ctx context.Context, query string, documents []string, topN int) ([]RerankResult, error) {
+	if query == "" {
+		return nil, ErrEmptyRerankQuery
+	}
+
+	results := make([]RerankResult, 0, len(documents))
+	for start := 0; start < len(documents); start += r.batchSize {
+		end := min(start+r.batchSize, len(documents))
+		scores, err := r.scorer.Score(ctx, query, documents[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("재순위 채점 실패 (%d~%d): %w", start, end-1, err)
+		}
+		if len(scores) != end-start {
+			return nil, fmt.Errorf("재순위 점수 수 불일치: %d != %d", len(scores), end-start)
+		}
+		for i, score := range scores {
+			results = append(results, RerankResult{Index: start + i, Score: float64(score)})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if topN > 0 && len(results) > topN {
+		results = results[:topN]
+	}
+	return results, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeScorer 문서 텍스트별 고정 점수 (없는 텍스트는 0), 받은 배치를 기록한다
+type fakeScorer struct {
+	scores  map[string]float32
+	err     error
+	short   bool
+	batches [][]string
+}
+
+func (s *fakeScorer) Score(ctx context.Context, query string, passages []string) ([]float32, error) {
+	s.batches = append(s.batches, append([]string{}, passages...))
+	if s.err != nil {
+		return nil, s.err
+	}
+	scores := make([]float32, len(passages))
+	for i, p := range passages {
+		scores[i] = s.scores[p]
+	}
+	if s.short {
+		scores = scores[1:]
+	}
+	return scores, nil
+}
+
+func rerankIndexes(results []RerankResult) []int {
+	indexes := make([]int, len(results))
+	for i, r := range results {
+		indexes[i] = r.Index
+	}
+	return indexes
+}
+
+func TestRerankerRerank(t *testing.T) {
+	documents := []string{"a", "b", "c", "d", "e"}
+	scores := map[string]float32{"a": 0.1, "b": 0.9, "c": 0.5, "d": 0.9, "e": 0.5}
+	tests := []struct {
+		name    string
+		batch   int
+		topN    int
+		want    []int
+		batches []int
+	}{
+		// 동점(b·d, c·e)은 입력 순서
+		{"배치 2개씩", 2, 0, []int{1, 3, 2, 4, 0}, []int{2, 2, 1}},
+		{"배치가 문서보다 큼", 10, 0, []int{1, 3, 2, 4, 0}, []int{5}},
+		{"배치 기본값", 0, 0, []int{1, 3, 2, 4, 0}, []int{5}},
+		{"top_n", 2, 3, []int{1, 3, 2}, []int{2, 2, 1}},
+		{"top_n이 문서보다 많음", 3, 9, []int{1, 3, 2, 4, 0}, []int{3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := &fakeScorer{scores: scores}
+			results, err := NewReranker(scorer, tt.batch).Rerank(context.Background(), "스모키", documents, tt.topN)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rerankIndexes(results); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			sizes := make([]int, len(scorer.batches))
+			for i, b := range scorer.batches {
+				sizes[i] = len(b)
+			}
+			if !reflect.DeepEqual(sizes, tt.batches) {
+				t.Fatalf("배치 크기 %v, want %v", sizes, tt.batches)
+			}
+			// 점수는 입력 문서의 점수 그대로
+			for _, r := range results {
+				if r.Score != float64(scores[documents[r.Index]]) {
+					t.Fatalf("문서 %d 점수 %f", r.Index, r.Score)
+				}
+			}
+		})
+	}
+}
+
+func TestRerankerErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewReranker(&fakeScorer{}, 2).Rerank(ctx, "", []string{"a"}, 0); !errors.Is(err, ErrEmptyRerankQuery) {
+		t.Fatalf("빈 질의 err = %v", err)
+	}
+
+	results, err := NewReranker(&fakeScorer{}, 2).Rerank(ctx, "q", nil, 0)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("빈 문서 %v, %v", results, err)
+	}
+
+	// 실패한 배치 범위를 오류에 남긴다
+	scorer := &fakeScorer{err: errors.New("onnx")}
+	if _, err := NewReranker(scorer, 2).Rerank(ctx, "q", []string{"a", "b", "c"}, 0); err == nil || !strings.Contains(err.Error(), "(0~1)") || !strings.Contains(err.Error(), "onnx") {
+		t.Fatalf("채점 실패 err = %v", err)
+	}
+	if _, err := NewReranker(&fakeScorer{short: true}, 2).Rerank(ctx, "q", []string{"a", "b"}, 0); err == nil || !strings.Contains(err.Error(), "불일치") {
+		t.Fatalf("점수 수 불일치 err = %v", err)
+	}
+}
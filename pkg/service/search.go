@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Whale0928/embedding-worker/pkg/embedder"
+	"github.com/Whale0928/embedding-worker/pkg/query"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// 검색 기본값
+const (
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 100
+	// MaxSearchOffset 페이지 시작 위치 상한 (Vespa 기본 maxOffset, 저장소에는 offset+limit개 후보를 요청한다)
+	MaxSearchOffset = 1000
+	// CaskBoost 검색어에 캐스크 키워드가 있을 때 flavor 필드 가중치 배수 (캐스크는 flavor 입력에 들어간다)
+	CaskBoost = 1.5
+)
+
+var (
+	// ErrEmptyKeyword 검색어 없음
+	ErrEmptyKeyword = errors.New("keyword is required")
+	// ErrUnknownVectorType 검색 컬렉션에 없는 벡터 타입
+	ErrUnknownVectorType = errors.New("unknown vector type")
+)
+
+// SearchQuery 키워드 검색 요청
+type SearchQuery struct {
+	Keyword string
+	// Filters 메타데이터 조건 (AND)
+	Filters []repository.Filter
+	Offset  int
+	Limit   int
+	// Literal 검색어를 해석하지 않고 그대로 임베딩 (parse=false)
+	Literal bool
+	// Rerank 재순위 단계 사용 여부 (nil이면 SearchOptions.Rerank)
+	Rerank *bool
+	// Diversity 결과 다양화 설정 (nil이면 SearchOptions.Diversity)
+	Diversity *Diversity
+}
+
+// SearchHit 검색 결과 단건
+type SearchHit struct {
+	ID    int64   `json:"id"`
+	Score float64 `json:"score"`
+	// VectorTypes 이 문서를 찾은 벡터 필드 (flavor, identity, ..., keywords)
+	VectorTypes []string `json:"vector_types"`
+	// Scores 벡터 필드별 원점수
+	Scores map[string]float64 `json:"scores"`
+	// RerankScore cross-encoder 점수 (재순위했을 때만, 결과는 이 점수 순서)
+	RerankScore *float64 `json:"rerank_score,omitempty"`
+	// Alcohol 벡터 저장소에 저장된 술 페이로드 (내용 해시 제외)
+	Alcohol map[string]interface{} `json:"alcohol"`
+}
+
+// SearchResult 검색 응답 (Python /qdrant/search와 같은 모양)
+type SearchResult struct {
+	Keyword string `json:"keyword"`
+	// VectorType 단일 벡터 검색이면 검색한 필드
+	VectorType string `json:"vector_type,omitempty"`
+	// Query 검색어에서 뽑아낸 조건 (해석하지 않았으면 없음)
+	Query *query.Parsed `json:"query,omitempty"`
+	// Reranked 융합 상위 후보를 cross-encoder로 다시 정렬했는지
+	Reranked bool `json:"reranked,omitempty"`
+	// Diversified MMR/그룹 제한으로 결과를 다양화했는지
+	Diversified bool        `json:"diversified,omitempty"`
+	Count       int         `json:"count"`
+	Results     []SearchHit `json:"results"`
+}
+
+// SearchOptions 검색 설정
+type SearchOptions struct {
+	// Collection 검색 대상 컬렉션, alias여도 된다 (검색할 때마다 활성 버전 조회)
+	Collection string
+	// Fusion 하이브리드 융합 방식
+	Fusion repository.Fusion
+	// Weights 필드(leg)별 융합 가중치 (없으면 1.0)
+	Weights map[string]float64
+	// Parser 검색어 해석기 (nil이면 검색어를 그대로 임베딩)
+	Parser *query.Parser
+	// Reranker 하이브리드 결과 재순위 모델 (nil이면 재순위 불가)
+	Reranker *Reranker
+	// Rerank 요청에 rerank 파라미터가 없을 때 재순위 여부
+	Rerank bool
+	// RerankCandidates 재순위할 융합 상위 후보 수 (offset+limit보다 작으면 offset+limit)
+	RerankCandidates int
+	// Diversity 요청에 다양화 설정이 없을 때 기본값
+	Diversity Diversity
+	// DiversityCandidates 다양화할 상위 후보 수 (0이면 DefaultDiversityCandidates)
+	DiversityCandidates int
+}
+
+// Searcher 검색어를 임베딩해 벡터 저장소를 검색
+type Searcher struct {
+	embedder    embedder.Embedder
+	store       repository.VectorStore
+	collections *repository.Collections
+	opts        SearchOptions
+}
+
+// NewSearcher 생성자
+func NewSearcher(emb embedder.Embedder, store repository.VectorStore, collections *repository.Collections, opts SearchOptions) *Searcher {
+	return &Searcher{
+		embedder:    emb,
+		store:       store,
+		collections: collections,
+		opts:        opts,
+	}
+}
+
+// Hybrid 검색어를 한 번 임베딩(dense + sparse)해 4개 named vector와 sparse를 함께 검색하고 융합
+func (s *Searcher) Hybrid(ctx context.Context, q SearchQuery) (*SearchResult, error) {
+	q, err := normalizeSearchQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.schema()
+	if err != nil {
+		return nil, err
+	}
+	rerank, err := s.shouldRerank(q)
+	if err != nil {
+		return nil, err
+	}
+	parsed, text, filters := s.understand(q)
+	embedding, err := s.embedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	d := s.diversity(q)
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 재순위/다양화하면 상위 후보를 한 번에 받아 다시 고른 뒤 페이지를 자른다
+	staged := rerank || d.Enabled()
+	offset, limit := q.Offset, q.Limit
+	if staged {
+		offset, limit = 0, s.candidates(q, rerank, d)
+	}
+	hits, err := s.store.HybridSearch(ctx, collection, repository.HybridQuery{
+		Dense:   embedding.Dense,
+		Sparse:  embedding.Sparse,
+		Filters: filters,
+		Weights: boostWeights(s.opts.Weights, parsed),
+		Fusion:  s.opts.Fusion,
+		Offset:  offset,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("하이브리드 검색 실패: %w", err)
+	}
+
+	results := toSearchHits(hits)
+	if staged {
+		if results, err = s.stage(ctx, collection, q.Keyword, results, rerank, d, nil, q.Offset, q.Limit); err != nil {
+			return nil, err
+		}
+	}
+	return &SearchResult{
+		Keyword:     q.Keyword,
+		Query:       parsed,
+		Reranked:    rerank,
+		Diversified: d.Enabled(),
+		Count:       len(results),
+		Results:     results,
+	}, nil
+}
+
+// Vector 검색어 dense 벡터로 한 named vector 필드만 kNN 검색 (대소문자 무시: FLAVOR == flavor)
+func (s *Searcher) Vector(ctx context.Context, vectorType string, q SearchQuery) (*SearchResult, error) {
+	q, err := normalizeSearchQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.schema()
+	if err != nil {
+		return nil, err
+	}
+	field, err := vectorField(collection, vectorType)
+	if err != nil {
+		return nil, err
+	}
+	parsed, text, filters := s.understand(q)
+	embedding, err := s.embedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	d := s.diversity(q)
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	offset, limit := q.Offset, q.Limit
+	if d.Enabled() {
+		offset, limit = 0, s.candidates(q, false, d)
+	}
+	hits, err := s.store.Search(ctx, collection, repository.KNNQuery{
+		Field:   field,
+		Vector:  embedding.Dense,
+		Filters: filters,
+		Offset:  offset,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s 검색 실패: %w", field, err)
+	}
+
+	results := toSearchHits(hits)
+	// kNN 결과는 모두 이 필드로 찾은 문서, 점수가 곧 그 필드의 점수
+	for i := range results {
+		results[i].VectorTypes = []string{field}
+		results[i].Scores = map[string]float64{field: results[i].Score}
+	}
+	if d.Enabled() {
+		// 단일 필드 검색은 그 필드 벡터끼리만 비교
+		if results, err = s.stage(ctx, collection, q.Keyword, results, false, d, []string{field}, q.Offset, q.Limit); err != nil {
+			return nil, err
+		}
+	}
+	return &SearchResult{
+		Keyword:     q.Keyword,
+		VectorType:  field,
+		Query:       parsed,
+		Diversified: d.Enabled(),
+		Count:       len(results),
+		Results:     results,
+	}, nil
+}
+
+// VectorTypes 검색 컬렉션의 named vector 필드 (설정 순서)
+func (s *Searcher) VectorTypes() []string {
+	collection, err := s.schema()
+	if err != nil {
+		return []string{}
+	}
+	types := make([]string, 0, len(collection.Vectors))
+	for _, v := range collection.Vectors {
+		types = append(types, v.Name)
+	}
+	return types
+}
+
+// shouldRerank 요청 파라미터가 없으면 설정 기본값, 재순위 모델 없이 요청하면 ErrRerankerDisabled
+func (s *Searcher) shouldRerank(q SearchQuery) (bool, error) {
+	rerank := s.opts.Rerank
+	if q.Rerank != nil {
+		rerank = *q.Rerank
+	}
+	if rerank && s.opts.Reranker == nil {
+		if q.Rerank != nil {
+			return false, ErrRerankerDisabled
+		}
+		return false, nil
+	}
+	return rerank, nil
+}
+
+// candidates 재순위/다양화할 상위 후보 수 (적어도 offset+limit)
+func (s *Searcher) candidates(q SearchQuery, rerank bool, d Diversity) int {
+	n := q.Offset + q.Limit
+	if rerank {
+		n = max(n, s.opts.RerankCandidates)
+	}
+	if d.Enabled() {
+		pool := s.opts.DiversityCandidates
+		if pool <= 0 {
+			pool = DefaultDiversityCandidates
+		}
+		n = max(n, pool)
+	}
+	return n
+}
+
+// stage 후보 재순위 → 다양화 → 페이지 자르기 (keyword는 재순위할 때만 쓴다)
+func (s *Searcher) stage(ctx context.Context, collection repository.Schema, keyword string, hits []SearchHit, rerank bool, d Diversity, fields []string, offset, limit int) ([]SearchHit, error) {
+	var err error
+	if rerank {
+		if hits, err = s.rerankHits(ctx, keyword, hits); err != nil {
+			return nil, err
+		}
+	}
+	if d.Enabled() {
+		if hits, err = s.diversify(ctx, collection, hits, d, fields, offset+limit); err != nil {
+			return nil, err
+		}
+	}
+	return page(hits, offset, limit), nil
+}
+
+// page offset부터 limit개
+func page(hits []SearchHit, offset, limit int) []SearchHit {
+	return hits[min(offset, len(hits)):min(offset+limit, len(hits))]
+}
+
+// rerankHits 후보의 rag_context를 원래 검색어와 함께 채점해 재순위 점수 순서로
+// (검색어 해석으로 지운 조건도 문맥에 도움이 되므로 원문을 쓴다)
+func (s *Searcher) rerankHits(ctx context.Context, keyword string, hits []SearchHit) ([]SearchHit, error) {
+	passages := make([]string, len(hits))
+	for i, hit := range hits {
+		passages[i] = rerankPassage(hit.Alcohol)
+	}
+	ranked, err := s.opts.Reranker.Rerank(ctx, keyword, passages, 0)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]SearchHit, 0, len(ranked))
+	for _, r := range ranked {
+		hit := hits[r.Index]
+		score := r.Score
+		hit.RerankScore = &score
+		results = append(results, hit)
+	}
+	return results, nil
+}
+
+// rerankPassage 재순위 입력 문서: 저장된 rag_context, 없으면 술 이름
+func rerankPassage(payload map[string]interface{}) string {
+	if text, ok := payload["rag_context"].(string); ok && strings.TrimSpace(text) != "" {
+		return text
+	}
+	var names []string
+	for _, field := range []string{"kor_name", "eng_name"} {
+		if name, ok := payload[field].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// understand 검색어 해석: 뽑아낸 조건은 요청 필터에 AND로 더하고, 나머지 텍스트를 임베딩한다
+// 해석기가 없거나 Literal이면 검색어를 그대로 쓴다
+func (s *Searcher) understand(q SearchQuery) (*query.Parsed, string, []repository.Filter) {
+	if s.opts.Parser == nil || q.Literal {
+		return nil, q.Keyword, q.Filters
+	}
+	parsed := s.opts.Parser.Parse(q.Keyword)
+	filters := append(append([]repository.Filter{}, q.Filters...), parsedFilters(parsed)...)
+	return parsed, parsed.Text, filters
+}
+
+// parsedFilters 해석 결과 → 메타데이터 필터
+// 도수/숙성 연수는 저장된 범위(*_min ~ *_max)와 겹치면, 용량은 volume_ml이 범위 안이면 통과
+// 지역/증류소는 ID (같은 종류가 여러 개면 OR)
+func parsedFilters(parsed *query.Parsed) []repository.Filter {
+	var filters []repository.Filter
+	for _, spec := range []struct {
+		field string
+		bound *query.Bound
+	}{{"abv", parsed.ABV}, {"age", parsed.Age}} {
+		if spec.bound != nil {
+			filters = append(filters, overlapFilters(spec.field, spec.bound)...)
+		}
+	}
+	if parsed.Volume != nil {
+		filter := repository.RangeFilter("volume_ml", parsed.Volume.Min, parsed.Volume.Max)
+		filter.ExclusiveMin = parsed.Volume.ExclusiveMin
+		filter.ExclusiveMax = parsed.Volume.ExclusiveMax
+		filters = append(filters, filter)
+	}
+	if filter, ok := idFilter("region_id", parsed.Regions); ok {
+		filters = append(filters, filter)
+	}
+	if filter, ok := idFilter("distillery_id", parsed.Distilleries); ok {
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// overlapFilters 저장된 범위 [field_min, field_max]와 검색 범위가 겹치는 조건 (/search의 abv_min 등 파라미터와 같은 의미)
+func overlapFilters(field string, bound *query.Bound) []repository.Filter {
+	r := repository.RangeFilter(field, bound.Min, bound.Max)
+	r.ExclusiveMin = bound.ExclusiveMin
+	r.ExclusiveMax = bound.ExclusiveMax
+	return repository.OverlapFilters(r)
+}
+
+func idFilter(field string, matches []query.Match) (repository.Filter, bool) {
+	switch len(matches) {
+	case 0:
+		return repository.Filter{}, false
+	case 1:
+		return repository.Eq(field, matches[0].ID), true
+	}
+	ids := make([]interface{}, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return repository.In(field, ids...), true
+}
+
+// boostWeights 캐스크 키워드가 있으면 flavor 가중치를 CaskBoost배 (설정 가중치는 바꾸지 않는다)
+func boostWeights(weights map[string]float64, parsed *query.Parsed) map[string]float64 {
+	if parsed == nil || len(parsed.Casks) == 0 {
+		return weights
+	}
+	boosted := make(map[string]float64, len(weights)+1)
+	for field, weight := range weights {
+		boosted[field] = weight
+	}
+	weight, ok := boosted[schema.VectorFlavor]
+	if !ok {
+		weight = 1.0
+	}
+	boosted[schema.VectorFlavor] = weight * CaskBoost
+	return boosted
+}
+
+// schema 검색 대상 컬렉션 (alias면 현재 활성 버전)
+func (s *Searcher) schema() (repository.Schema, error) {
+	collection, ok := s.collections.Get(s.opts.Collection)
+	if !ok {
+		return repository.Schema{}, fmt.Errorf("알 수 없는 검색 컬렉션: %s", s.opts.Collection)
+	}
+	return collection, nil
+}
+
+// vectorField 컬렉션에 설정된 named vector 중 vectorType과 같은 필드
+func vectorField(collection repository.Schema, vectorType string) (string, error) {
+	for _, v := range collection.Vectors {
+		if strings.EqualFold(v.Name, vectorType) {
+			return v.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownVectorType, vectorType)
+}
+
+// embedQuery 검색어 임베딩
+func (s *Searcher) embedQuery(ctx context.Context, keyword string) (embedder.Embedding, error) {
+	result, err := s.embedder.Embed(ctx, []string{keyword})
+	if err != nil {
+		return embedder.Embedding{}, fmt.Errorf("검색어 임베딩 실패: %w", err)
+	}
+	if len(result) != 1 {
+		return embedder.Embedding{}, fmt.Errorf("검색어 임베딩 결과 수 불일치: %d", len(result))
+	}
+	return result[0], nil
+}
+
+// normalizeSearchQuery 검색어 정리와 offset/limit 기본값, 범위 확인
+func normalizeSearchQuery(q SearchQuery) (SearchQuery, error) {
+	q.Keyword = strings.TrimSpace(q.Keyword)
+	if q.Keyword == "" {
+		return q, ErrEmptyKeyword
+	}
+	q.Offset, q.Limit = normalizePage(q.Offset, q.Limit)
+	return q, nil
+}
+
+// normalizePage offset/limit 기본값과 범위
+func normalizePage(offset, limit int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > MaxSearchOffset {
+		offset = MaxSearchOffset
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+	return offset, limit
+}
+
+// toSearchHits 저장소 Hit → 응답 결과
+func toSearchHits(hits []repository.Hit) []SearchHit {
+	results := make([]SearchHit, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, toSearchHit(hit))
+	}
+	return results
+}
+
+// toSearchHit 점수가 0보다 큰 필드를 매칭된 벡터 타입으로 본다
+// (Vespa는 nearestNeighbor로 찾지 못한 필드도 closeness 0으로 돌려준다)
+func toSearchHit(hit repository.Hit) SearchHit {
+	types := make([]string, 0, len(hit.Scores))
+	scores := make(map[string]float64, len(hit.Scores))
+	for field, score := range hit.Scores {
+		scores[field] = score
+		if score > 0 {
+			types = append(types, field)
+		}
+	}
+	sort.Strings(types)
+
+	return SearchHit{
+		ID:          hit.ID,
+		Score:       hit.Score,
+		VectorTypes: types,
+		Scores:      scores,
+		Alcohol:     alcoholPayload(hit),
+	}
+}
+
+// alcoholPayload 검색 응답에 필요 없는 내용 해시 필드(*_hash, fields_hash)를 뺀 페이로드
+func alcoholPayload(hit repository.Hit) map[string]interface{} {
+	payload := make(map[string]interface{}, len(hit.Fields)+1)
+	for name, value := range hit.Fields {
+		if strings.HasSuffix(name, "_hash") {
+			continue
+		}
+		payload[name] = value
+	}
+	payload["id"] = hit.ID
+	return payload
+}
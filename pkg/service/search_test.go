@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/Whale0928/embedding-worker/pkg/query"
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// recordingStore 마지막 하이브리드 쿼리를 기록하는 저장소
+type recordingStore struct {
+	repository.VectorStore
+
+	mu   sync.Mutex
+	last repository.HybridQuery
+}
+
+func (s *recordingStore) HybridSearch(ctx context.Context, collection repository.Schema, q repository.HybridQuery) ([]repository.Hit, error) {
+	s.mu.Lock()
+	s.last = q
+	s.mu.Unlock()
+	return s.VectorStore.HybridSearch(ctx, collection, q)
+}
+
+func (s *recordingStore) lastQuery() repository.HybridQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// seedSearchDocs 문서마다 벡터 필드 하나만 둬서 RRF 점수가 그 필드 가중치로만 정해지게 한다
+// 1: flavor, 2: identity, 3: origin, 4: spec
+func seedSearchDocs(t *testing.T, store repository.VectorStore, s repository.Schema) {
+	t.Helper()
+	docs := []repository.Document{
+		{ID: 1, Vectors: map[string][]float32{schema.VectorFlavor: fakeVector("a", 4)}, Fields: map[string]interface{}{"type": "Single Malt", "abv": 40.0, "abv_min": 40.0, "abv_max": 40.0, "region_id": int64(10), "kor_name": "하나", "fields_hash": "x"}},
+		{ID: 2, Vectors: map[string][]float32{schema.VectorIdentity: fakeVector("b", 4)}, Fields: map[string]interface{}{"type": "Single Malt", "abv": 46.0, "abv_min": 46.0, "abv_max": 46.0, "region_id": int64(20)}},
+		{ID: 3, Vectors: map[string][]float32{schema.VectorOrigin: fakeVector("c", 4)}, Fields: map[string]interface{}{"type": "Blend", "abv": 43.0, "abv_min": 43.0, "abv_max": 43.0, "region_id": int64(10)}},
+		{ID: 4, Vectors: map[string][]float32{schema.VectorSpec: fakeVector("d", 4)}, Fields: map[string]interface{}{"type": "Blend", "abv": 50.0, "abv_min": 50.0, "abv_max": 50.0, "region_id": int64(20)}},
+	}
+	if err := store.Upsert(context.Background(), s, docs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestSearcher(t *testing.T, opts SearchOptions) (*Searcher, *recordingStore) {
+	t.Helper()
+	s := testWhiskySchema()
+	store := &recordingStore{VectorStore: repository.NewMemoryStore()}
+	seedSearchDocs(t, store, s)
+	collections, err := repository.NewCollections(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Collection = s.Name
+	return NewSearcher(&fakeEmbedder{dim: 4}, store, collections, opts), store
+}
+
+func hitIDs(hits []SearchHit) []int64 {
+	ids := make([]int64, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func equalHitIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearcherHybridFusionWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]float64
+		want    []int64
+	}{
+		{"가중치 없음: 동점은 ID 순", nil, []int64{1, 2, 3, 4}},
+		{"spec 우선", map[string]float64{schema.VectorSpec: 3, schema.VectorOrigin: 2}, []int64{4, 3, 1, 2}},
+		{"가중치 0인 필드는 융합에서 빠진다", map[string]float64{schema.VectorFlavor: 0}, []int64{2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher, _ := newTestSearcher(t, SearchOptions{Fusion: repository.FusionRRF, Weights: tt.weights})
+			result, err := searcher.Hybrid(context.Background(), SearchQuery{Keyword: "스모키"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIDs(result.Results); !equalHitIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearcherHybridCaskBoost(t *testing.T) {
+	parser := query.NewParser(nil)
+	searcher, store := newTestSearcher(t, SearchOptions{
+		Fusion:  repository.FusionRRF,
+		Weights: map[string]float64{schema.VectorFlavor: 0.5, schema.VectorIdentity: 1},
+		Parser:  parser,
+	})
+
+	result, err := searcher.Hybrid(context.Background(), SearchQuery{Keyword: "셰리 캐스크"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := store.lastQuery().Weights[schema.VectorFlavor]; got != 0.5*CaskBoost {
+		t.Fatalf("flavor 가중치 %v, want %v", got, 0.5*CaskBoost)
+	}
+	if result.Query == nil || len(result.Query.Casks) != 1 || result.Query.Casks[0] != "sherry" {
+		t.Fatalf("해석 결과 %+v", result.Query)
+	}
+	// 설정 가중치는 바꾸지 않는다
+	if searcher.opts.Weights[schema.VectorFlavor] != 0.5 {
+		t.Fatalf("설정 가중치 변경됨: %v", searcher.opts.Weights)
+	}
+
+	if _, err := searcher.Hybrid(context.Background(), SearchQuery{Keyword: "셰리 캐스크", Literal: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.lastQuery().Weights[schema.VectorFlavor]; got != 0.5 {
+		t.Fatalf("parse=false flavor 가중치 %v", got)
+	}
+}
+
+func TestSearcherHybridFilters(t *testing.T) {
+	searcher, store := newTestSearcher(t, SearchOptions{Fusion: repository.FusionRRF, Parser: query.NewParser(nil)})
+
+	result, err := searcher.Hybrid(context.Background(), SearchQuery{
+		Keyword: "스모키 45도 이하",
+		Filters: []repository.Filter{repository.Eq("region_id", int64(10))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 요청 필터(region_id=10)와 검색어 조건(abv <= 45)을 AND
+	if got := hitIDs(result.Results); !equalHitIDs(got, []int64{1, 3}) {
+		t.Fatalf("got %v", got)
+	}
+	filters := store.lastQuery().Filters
+	if len(filters) != 2 || filters[0].Field != "region_id" || filters[1].Field != "abv_min" || *filters[1].Max != 45 {
+		t.Fatalf("필터 %+v", filters)
+	}
+	hit := result.Results[0]
+	if _, ok := hit.Alcohol["fields_hash"]; ok {
+		t.Fatalf("해시 필드가 응답에 남음: %v", hit.Alcohol)
+	}
+	if _, ok := hit.Scores[schema.VectorFlavor]; !ok || hit.Alcohol["id"] != int64(1) {
+		t.Fatalf("결과 %+v", hit)
+	}
+}
+
+// 도수/숙성 조건은 저장된 범위와 겹치면 통과 (중간값만 보면 40~43% 술이 "43도"에 빠진다)
+func TestSearcherHybridSpecRangeOverlap(t *testing.T) {
+	searcher, store := newTestSearcher(t, SearchOptions{Fusion: repository.FusionRRF, Parser: query.NewParser(nil)})
+	ranged := repository.Document{
+		ID:      5,
+		Vectors: map[string][]float32{schema.VectorFlavor: fakeVector("e", 4)},
+		Fields: map[string]interface{}{
+			"type": "Single Malt", "abv": 41.5, "abv_min": 40.0, "abv_max": 43.0,
+			"age": 13, "age_min": 12, "age_max": 15,
+		},
+	}
+	if err := store.Upsert(context.Background(), testWhiskySchema(), []repository.Document{ranged}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		keyword string
+		want    []int64
+	}{
+		{"43도", []int64{3, 5}},
+		{"40도 이상", []int64{1, 2, 3, 4, 5}},
+		{"43도 초과", []int64{2, 4}},
+		{"40도 미만", []int64{}},
+		{"42~45도", []int64{3, 5}},
+		{"14년", []int64{5}},
+		{"15년 이상", []int64{5}},
+		{"12년 미만", []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			result, err := searcher.Hybrid(context.Background(), SearchQuery{Keyword: tt.keyword + " 스모키"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := hitIDs(result.Results)
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !equalHitIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v (filters %+v)", got, tt.want, store.lastQuery().Filters)
+			}
+		})
+	}
+}
+
+func TestSearcherHybridPagination(t *testing.T) {
+	searcher, store := newTestSearcher(t, SearchOptions{Fusion: repository.FusionRRF})
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		offset, limit int
+		wantOffset    int
+		wantLimit     int
+		want          []int64
+	}{
+		{"기본 limit", 0, 0, 0, DefaultSearchLimit, []int64{1, 2, 3, 4}},
+		{"두 번째 페이지", 2, 1, 2, 1, []int64{3}},
+		{"음수 offset은 0", -1, 2, 0, 2, []int64{1, 2}},
+		{"최대 limit", 0, MaxSearchLimit + 1, 0, MaxSearchLimit, []int64{1, 2, 3, 4}},
+		{"끝 이후", 10, 5, 10, 5, []int64{}},
+		{"최대 offset", 100000000, 5, MaxSearchOffset, 5, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := searcher.Hybrid(ctx, SearchQuery{Keyword: "스모키", Offset: tt.offset, Limit: tt.limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := store.lastQuery()
+			if q.Offset != tt.wantOffset || q.Limit != tt.wantLimit {
+				t.Fatalf("저장소 offset/limit %d/%d, want %d/%d", q.Offset, q.Limit, tt.wantOffset, tt.wantLimit)
+			}
+			if got := hitIDs(result.Results); !equalHitIDs(got, tt.want) || result.Count != len(tt.want) {
+				t.Fatalf("got %v (count %d), want %v", got, result.Count, tt.want)
+			}
+		})
+	}
+
+	// 다양화하면 후보를 한 번에 받아 고른 뒤 페이지를 자른다
+	d := Diversity{MaxPerGroup: map[string]int{"region_id": 1}}
+	result, err := searcher.Hybrid(ctx, SearchQuery{Keyword: "스모키", Offset: 1, Limit: 1, Diversity: &d})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := store.lastQuery(); q.Offset != 0 || q.Limit != DefaultDiversityCandidates {
+		t.Fatalf("다양화 후보 offset/limit %d/%d", q.Offset, q.Limit)
+	}
+	if got := hitIDs(result.Results); !equalHitIDs(got, []int64{2}) || !result.Diversified {
+		t.Fatalf("다양화 두 번째 페이지 %v", got)
+	}
+}
+
+func TestSearcherHybridErrors(t *testing.T) {
+	searcher, _ := newTestSearcher(t, SearchOptions{})
+	rerank := true
+
+	tests := []struct {
+		name string
+		q    SearchQuery
+		want error
+	}{
+		{"빈 검색어", SearchQuery{Keyword: "  "}, ErrEmptyKeyword},
+		{"재순위 모델 없음", SearchQuery{Keyword: "스모키", Rerank: &rerank}, ErrRerankerDisabled},
+		{"lambda 범위", SearchQuery{Keyword: "스모키", Diversity: &Diversity{MMR: true, Lambda: 2}}, ErrInvalidDiversity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := searcher.Hybrid(context.Background(), tt.q); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearcherVector(t *testing.T) {
+	s := testWhiskySchema()
+	store := repository.NewMemoryStore()
+	docs := make([]repository.Document, 0, 4)
+	for id, typ := range map[int64]string{1: "Single Malt", 2: "Single Malt", 3: "Blend", 4: "Blend"} {
+		docs = append(docs, repository.Document{
+			ID:      id,
+			Vectors: map[string][]float32{schema.VectorFlavor: fakeVector(typ+string(rune('a'+id)), 4), schema.VectorSpec: fakeVector("spec", 4)},
+			Fields:  map[string]interface{}{"type": typ},
+		})
+	}
+	if err := store.Upsert(context.Background(), s, docs); err != nil {
+		t.Fatal(err)
+	}
+	collections, err := repository.NewCollections(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	searcher := NewSearcher(&fakeEmbedder{dim: 4}, store, collections, SearchOptions{Collection: s.Name})
+	ctx := context.Background()
+
+	all, err := searcher.Vector(ctx, "FLAVOR", SearchQuery{Keyword: "스모키"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 타입은 대소문자 구분 없이 컬렉션 필드 이름으로 맞춘다
+	if all.VectorType != schema.VectorFlavor || all.Count != 4 {
+		t.Fatalf("결과 %+v", all)
+	}
+	for _, hit := range all.Results {
+		if !reflect.DeepEqual(hit.VectorTypes, []string{schema.VectorFlavor}) || hit.Scores[schema.VectorFlavor] != hit.Score {
+			t.Fatalf("단일 필드 점수 표시 %+v", hit)
+		}
+	}
+	ids := hitIDs(all.Results)
+
+	page, err := searcher.Vector(ctx, schema.VectorFlavor, SearchQuery{Keyword: "스모키", Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hitIDs(page.Results); !equalHitIDs(got, ids[1:3]) {
+		t.Fatalf("페이지 %v, 전체 %v", got, ids)
+	}
+
+	blend, err := searcher.Vector(ctx, schema.VectorFlavor, SearchQuery{Keyword: "스모키", Filters: []repository.Filter{repository.Eq("type", "Blend")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hit := range blend.Results {
+		if hit.Alcohol["type"] != "Blend" {
+			t.Fatalf("필터 결과 %+v", hit)
+		}
+	}
+	if blend.Count != 2 {
+		t.Fatalf("Blend %d건", blend.Count)
+	}
+
+	if _, err := searcher.Vector(ctx, "taste", SearchQuery{Keyword: "스모키"}); !errors.Is(err, ErrUnknownVectorType) {
+		t.Fatalf("없는 타입 err = %v", err)
+	}
+	if _, err := searcher.Vector(ctx, schema.VectorFlavor, SearchQuery{Keyword: " "}); !errors.Is(err, ErrEmptyKeyword) {
+		t.Fatalf("빈 검색어 err = %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// SimilarHybrid 모든 dense 필드로 찾아 융합하는 유사 술 검색 타입
+const SimilarHybrid = "hybrid"
+
+// ErrAlcoholNotFound 벡터 저장소에 없는 술
+var ErrAlcoholNotFound = errors.New("alcohol not found in vector store")
+
+// SimilarQuery 유사 술 검색 요청
+type SimilarQuery struct {
+	// VectorType dense 필드 이름 또는 hybrid (비우면 hybrid)
+	VectorType string
+	// Filters 메타데이터 조건 (AND)
+	Filters []repository.Filter
+	// ExcludeSameDistillery 기준 술과 같은 증류소 제외
+	ExcludeSameDistillery bool
+	Offset                int
+	Limit                 int
+	// Diversity 결과 다양화 설정 (nil이면 SearchOptions.Diversity)
+	Diversity *Diversity
+}
+
+// SimilarResult 유사 술 검색 응답
+type SimilarResult struct {
+	// ID 기준 술
+	ID         int64  `json:"id"`
+	VectorType string `json:"vector_type"`
+	// Diversified MMR/그룹 제한으로 결과를 다양화했는지
+	Diversified bool        `json:"diversified,omitempty"`
+	Count       int         `json:"count"`
+	Results     []SearchHit `json:"results"`
+}
+
+// Similar 저장된 벡터로 비슷한 술 검색 (다시 임베딩하지 않는다), 기준 술 자신은 결과에서 뺀다
+//   - 필드 이름: 그 필드 벡터로 kNN
+//   - hybrid: 문서에 있는 dense 필드마다 자기 벡터로 kNN 후 SEARCH_FUSION/SEARCH_WEIGHTS로 융합
+func (s *Searcher) Similar(ctx context.Context, id int64, q SimilarQuery) (*SimilarResult, error) {
+	q.Offset, q.Limit = normalizePage(q.Offset, q.Limit)
+	collection, err := s.schema()
+	if err != nil {
+		return nil, err
+	}
+	fields, vectorType, err := similarFields(collection, q.VectorType)
+	if err != nil {
+		return nil, err
+	}
+	d := s.diversity(SearchQuery{Diversity: q.Diversity})
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	doc, err := s.store.Get(ctx, collection, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %d", ErrAlcoholNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("술 %d 조회 실패: %w", id, err)
+	}
+
+	filters := append([]repository.Filter{}, q.Filters...)
+	if q.ExcludeSameDistillery {
+		if distillery, ok := doc.Fields["distillery_id"]; ok && distillery != nil {
+			filters = append(filters, repository.Not(repository.Eq("distillery_id", distillery)))
+		}
+	}
+
+	// 기준 술 자신이 한 자리를 차지하므로 한 개 더 받는다
+	limit := q.Offset + q.Limit + 1
+	if d.Enabled() {
+		limit = max(limit, s.candidates(SearchQuery{Offset: q.Offset, Limit: q.Limit}, false, d)+1)
+	}
+	legs := make(map[string][]repository.Hit, len(fields))
+	for _, field := range fields {
+		vector, ok := doc.Vectors[field]
+		if !ok {
+			continue
+		}
+		hits, err := s.store.Search(ctx, collection, repository.KNNQuery{Field: field, Vector: vector, Filters: filters, Limit: limit})
+		if err != nil {
+			return nil, fmt.Errorf("%s 유사 검색 실패: %w", field, err)
+		}
+		legs[field] = hits
+	}
+
+	results, err := s.legResults(ctx, collection, legs, vectorType, map[int64]bool{id: true}, d, q.Offset, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &SimilarResult{
+		ID:          id,
+		VectorType:  vectorType,
+		Diversified: d.Enabled(),
+		Count:       len(results),
+		Results:     results,
+	}, nil
+}
+
+// legResults 필드별 kNN 결과를 hybrid면 융합하고 exclude를 뺀 뒤 다양화 또는 페이지 적용
+// 단일 필드면 점수를 그 필드 점수로 표시한다 (Similar, Compose 공통)
+func (s *Searcher) legResults(ctx context.Context, collection repository.Schema, legs map[string][]repository.Hit, vectorType string, exclude map[int64]bool, d Diversity, offset, limit int) ([]SearchHit, error) {
+	var hits []repository.Hit
+	if vectorType == SimilarHybrid {
+		hits = repository.FuseHits(legs, s.opts.Weights, s.opts.Fusion)
+	} else {
+		hits = legs[vectorType]
+	}
+
+	results := make([]SearchHit, 0, len(hits))
+	for _, hit := range toSearchHits(hits) {
+		if exclude[hit.ID] {
+			continue
+		}
+		if vectorType != SimilarHybrid {
+			hit.VectorTypes = []string{vectorType}
+			hit.Scores = map[string]float64{vectorType: hit.Score}
+		}
+		results = append(results, hit)
+	}
+
+	if !d.Enabled() {
+		return page(results, offset, limit), nil
+	}
+	var simFields []string
+	if vectorType != SimilarHybrid {
+		simFields = []string{vectorType}
+	}
+	return s.stage(ctx, collection, "", results, false, d, simFields, offset, limit)
+}
+
+// similarFields 검색할 dense 필드와 응답에 쓸 타입 (hybrid면 컬렉션의 모든 dense 필드)
+func similarFields(collection repository.Schema, vectorType string) ([]string, string, error) {
+	if vectorType == "" || strings.EqualFold(vectorType, SimilarHybrid) {
+		fields := make([]string, 0, len(collection.Vectors))
+		for _, v := range collection.Vectors {
+			fields = append(fields, v.Name)
+		}
+		return fields, SimilarHybrid, nil
+	}
+	field, err := vectorField(collection, vectorType)
+	if err != nil {
+		return nil, "", err
+	}
+	return []string{field}, field, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// knnRecordingStore kNN 쿼리를 기록하는 저장소
+type knnRecordingStore struct {
+	repository.VectorStore
+
+	mu      sync.Mutex
+	queries []repository.KNNQuery
+}
+
+func (s *knnRecordingStore) Search(ctx context.Context, collection repository.Schema, q repository.KNNQuery) ([]repository.Hit, error) {
+	s.mu.Lock()
+	s.queries = append(s.queries, q)
+	s.mu.Unlock()
+	return s.VectorStore.Search(ctx, collection, q)
+}
+
+// newSimilarSearcher 1~4 (1과 가까운 순서), 1·2는 증류소 7, 3·4는 증류소 8, 4에는 identity 벡터 없음
+func newSimilarSearcher(t *testing.T) (*Searcher, *knnRecordingStore) {
+	t.Helper()
+	s := testWhiskySchema()
+	store := &knnRecordingStore{VectorStore: repository.NewMemoryStore()}
+	docs := []repository.Document{
+		{ID: 1, Vectors: map[string][]float32{schema.VectorFlavor: {1, 0, 0, 0}, schema.VectorIdentity: {0, 0, 1, 0}}, Fields: map[string]interface{}{"distillery_id": int64(7)}},
+		{ID: 2, Vectors: map[string][]float32{schema.VectorFlavor: {1, 0.2, 0, 0}, schema.VectorIdentity: {0, 0, 1, 1}}, Fields: map[string]interface{}{"distillery_id": int64(7)}},
+		{ID: 3, Vectors: map[string][]float32{schema.VectorFlavor: {1, 0.5, 0, 0}, schema.VectorIdentity: {0, 0, 1, 0.1}}, Fields: map[string]interface{}{"distillery_id": int64(8)}},
+		{ID: 4, Vectors: map[string][]float32{schema.VectorFlavor: {1, 1, 0, 0}}, Fields: map[string]interface{}{"distillery_id": int64(8)}},
+	}
+	if err := store.Upsert(context.Background(), s, docs); err != nil {
+		t.Fatal(err)
+	}
+	collections, err := repository.NewCollections(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSearcher(&fakeEmbedder{dim: 4}, store, collections, SearchOptions{Collection: s.Name, Fusion: repository.FusionRRF}), store
+}
+
+func TestSearcherSimilar(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    int64
+		q     SimilarQuery
+		want  []int64
+		typ   string
+		field int
+	}{
+		{"단일 필드, 자기 자신 제외", 1, SimilarQuery{VectorType: "FLAVOR"}, []int64{2, 3, 4}, schema.VectorFlavor, 1},
+		{"identity 순서", 1, SimilarQuery{VectorType: schema.VectorIdentity}, []int64{3, 2}, schema.VectorIdentity, 1},
+		// hybrid는 기준 문서에 있는 필드만 검색한다 (4는 flavor만 있음)
+		{"hybrid는 문서에 있는 필드만", 4, SimilarQuery{}, []int64{3, 2, 1}, SimilarHybrid, 1},
+		{"페이지", 1, SimilarQuery{VectorType: schema.VectorFlavor, Offset: 1, Limit: 1}, []int64{3}, schema.VectorFlavor, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher, store := newSimilarSearcher(t)
+			result, err := searcher.Similar(context.Background(), tt.id, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIDs(result.Results); !equalHitIDs(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if result.ID != tt.id || result.VectorType != tt.typ || result.Count != len(tt.want) {
+				t.Fatalf("결과 %+v", result)
+			}
+			if len(store.queries) != tt.field {
+				t.Fatalf("kNN %d회", len(store.queries))
+			}
+			// 자기 자신이 한 자리를 차지하므로 한 개 더 받는다
+			offset, limit := normalizePage(tt.q.Offset, tt.q.Limit)
+			if q := store.queries[0]; q.Limit != offset+limit+1 {
+				t.Fatalf("kNN limit %d", q.Limit)
+			}
+			if tt.typ != SimilarHybrid {
+				for _, hit := range result.Results {
+					if !reflect.DeepEqual(hit.VectorTypes, []string{tt.typ}) || hit.Scores[tt.typ] != hit.Score {
+						t.Fatalf("단일 필드 점수 표시 %+v", hit)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSearcherSimilarExcludeSameDistillery(t *testing.T) {
+	searcher, store := newSimilarSearcher(t)
+	typeFilter := repository.Eq("type", "Single Malt")
+
+	result, err := searcher.Similar(context.Background(), 1, SimilarQuery{VectorType: schema.VectorFlavor, ExcludeSameDistillery: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hitIDs(result.Results); !equalHitIDs(got, []int64{3, 4}) {
+		t.Fatalf("got %v", got)
+	}
+	want := []repository.Filter{repository.Not(repository.Eq("distillery_id", int64(7)))}
+	if got := store.queries[0].Filters; !reflect.DeepEqual(got, want) {
+		t.Fatalf("필터 %+v", got)
+	}
+
+	// 요청 필터 뒤에 붙인다 (요청 필터 슬라이스는 건드리지 않는다)
+	store.queries = nil
+	filters := []repository.Filter{typeFilter}
+	if _, err := searcher.Similar(context.Background(), 1, SimilarQuery{VectorType: schema.VectorFlavor, Filters: filters, ExcludeSameDistillery: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.queries[0].Filters; len(got) != 2 || !reflect.DeepEqual(got[0], typeFilter) || len(filters) != 1 {
+		t.Fatalf("필터 %+v", got)
+	}
+
+	// 증류소가 없는 문서는 조건을 붙이지 않는다
+	store.queries = nil
+	if err := store.Update(context.Background(), testWhiskySchema(), []repository.Document{{ID: 1, Fields: map[string]interface{}{"distillery_id": nil}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := searcher.Similar(context.Background(), 1, SimilarQuery{VectorType: schema.VectorFlavor, ExcludeSameDistillery: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.queries[0].Filters; len(got) != 0 {
+		t.Fatalf("증류소 없는 문서 필터 %+v", got)
+	}
+}
+
+func TestSearcherSimilarErrors(t *testing.T) {
+	searcher, _ := newSimilarSearcher(t)
+	if _, err := searcher.Similar(context.Background(), 99, SimilarQuery{}); !errors.Is(err, ErrAlcoholNotFound) {
+		t.Fatalf("없는 술 err = %v", err)
+	}
+	if _, err := searcher.Similar(context.Background(), 1, SimilarQuery{VectorType: "taste"}); !errors.Is(err, ErrUnknownVectorType) {
+		t.Fatalf("없는 타입 err = %v", err)
+	}
+}
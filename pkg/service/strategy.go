@@ -0,0 +1,182 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+	"github.com/Whale0928/embedding-worker/pkg/normalize"
+	"github.com/Whale0928/embedding-worker/pkg/schema"
+)
+
+// WhiskyStrategy v2 임베딩 입력 (Python WhiskyEmbeddingStrategy 텍스트 부분)
+// 4개 검색 의도별 텍스트 + RAG 컨텍스트 + 필터 메타데이터
+type WhiskyStrategy struct {
+	ID int64 `json:"id"`
+
+	FlavorText   string `json:"flavor_semantic_text"`
+	IdentityText string `json:"identity_keyword_text"`
+	OriginText   string `json:"origin_context_text"`
+	SpecText     string `json:"spec_attribute_text"`
+
+	RAGContext string `json:"rag_context_text"`
+
+	// FilterMetadata type, abv, age, categoryGroup, region_id, distillery_id, tastingTags (nil 값은 생략)
+	// + 정규화된 범위 abv_min/abv_max, age_min/age_max, nas, volume_ml
+	FilterMetadata map[string]interface{} `json:"filter_metadata"`
+
+	// Issues 정규화하지 못한 ABV/Age/Volume 값
+	Issues []*normalize.UnparseableError `json:"issues,omitempty"`
+}
+
+// Texts named vector 이름 → 임베딩할 텍스트
+func (s *WhiskyStrategy) Texts() map[string]string {
+	return map[string]string{
+		schema.VectorFlavor:   s.FlavorText,
+		schema.VectorIdentity: s.IdentityText,
+		schema.VectorOrigin:   s.OriginText,
+		schema.VectorSpec:     s.SpecText,
+	}
+}
+
+// BuildWhiskyStrategy Alcohol → v2 전략 텍스트 (PYTHON_EMBEDDER_FEATURES.md 7장의 to_whisky_strategy 명세 포팅)
+func BuildWhiskyStrategy(a *domain.Alcohol) *WhiskyStrategy {
+	metadata, issues := filterMetadata(a)
+	return &WhiskyStrategy{
+		ID:             a.ID,
+		FlavorText:     flavorText(a),
+		IdentityText:   identityText(a),
+		OriginText:     originText(a),
+		SpecText:       specText(a),
+		RAGContext:     ragContext(a),
+		FilterMetadata: metadata,
+		Issues:         issues,
+	}
+}
+
+// flavorText tastingTags + cask + description
+func flavorText(a *domain.Alcohol) string {
+	parts := make([]string, 0)
+	for _, tag := range a.TastingTags {
+		parts = append(parts, tag.KorName+" "+tag.EngName)
+		if value(tag.Description) != "" {
+			parts = append(parts, *tag.Description)
+		}
+	}
+	if value(a.Cask) != "" {
+		parts = append(parts, *a.Cask)
+	}
+	if value(a.Description) != "" {
+		parts = append(parts, *a.Description)
+	}
+	return strings.Join(parts, " ")
+}
+
+// identityText 이름 + 증류소 + 카테고리
+func identityText(a *domain.Alcohol) string {
+	parts := []string{a.KorName, a.EngName}
+	if a.Distillery != nil {
+		parts = append(parts, a.Distillery.KorName, a.Distillery.EngName)
+	}
+	if value(a.KorCategory) != "" {
+		parts = append(parts, *a.KorCategory)
+		// Python은 eng_category가 None이면 join에서 실패하므로 생략한다
+		if a.EngCategory != nil {
+			parts = append(parts, *a.EngCategory)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// originText region 전체 정보 (description이 없으면 빈 문자열로 join, Python과 동일하게 끝에 공백이 남는다)
+func originText(a *domain.Alcohol) string {
+	if a.Region == nil {
+		return ""
+	}
+	return strings.Join([]string{
+		a.Region.KorName,
+		a.Region.EngName,
+		a.Region.Continent,
+		value(a.Region.Description),
+	}, " ")
+}
+
+// specText type + abv + age + cask + volume + category_group
+func specText(a *domain.Alcohol) string {
+	parts := []string{a.Type}
+	if value(a.ABV) != "" {
+		parts = append(parts, *a.ABV+"도")
+	}
+	if value(a.Age) != "" {
+		parts = append(parts, *a.Age+"년")
+	}
+	if value(a.Cask) != "" {
+		parts = append(parts, *a.Cask)
+	}
+	if value(a.Volume) != "" {
+		parts = append(parts, *a.Volume+"ml")
+	}
+	if value(a.CategoryGroup) != "" {
+		parts = append(parts, *a.CategoryGroup)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ragContext RAG용 자연어 설명 (Python rag_context와 동일하게 끝 공백을 남긴다)
+func ragContext(a *domain.Alcohol) string {
+	var b strings.Builder
+
+	b.WriteString(a.KorName + "(" + a.EngName + ")은 ")
+	if a.Region != nil {
+		b.WriteString(a.Region.KorName + "의 ")
+	}
+	if a.Distillery != nil {
+		b.WriteString(a.Distillery.KorName + "에서 생산된 ")
+	}
+	b.WriteString(a.Type + "입니다. ")
+	if value(a.ABV) != "" {
+		b.WriteString("도수는 " + *a.ABV + "도이며, ")
+	}
+	if value(a.Age) != "" {
+		b.WriteString(*a.Age + "년 숙성되었습니다. ")
+	}
+	return b.String()
+}
+
+// filterMetadata 벡터 저장소 필터용 메타데이터 (Python filter_metadata 키 + 정규화된 범위)
+// abv/age는 범위의 중간값 (Python _parse_number와 동일), 해석하지 못한 값은 issues로 반환
+func filterMetadata(a *domain.Alcohol) (map[string]interface{}, []*normalize.UnparseableError) {
+	specs := normalize.Alcohol(a)
+
+	metadata := specs.Metadata()
+	metadata["type"] = a.Type
+	if a.CategoryGroup != nil {
+		metadata["categoryGroup"] = *a.CategoryGroup
+	}
+	if a.RegionID != nil {
+		metadata["region_id"] = *a.RegionID
+	}
+	if a.DistilleryID != nil {
+		metadata["distillery_id"] = *a.DistilleryID
+	}
+
+	tags := make([]string, 0, len(a.TastingTags))
+	for _, tag := range a.TastingTags {
+		tags = append(tags, tag.KorName)
+	}
+	metadata["tastingTags"] = tags
+
+	return metadata, specs.Issues
+}
+
+// optionalMetadataKeys 문서 필드에서 값이 없으면 빠지는 키 (부분 갱신 때 남은 이전 값을 지운다)
+func optionalMetadataKeys() []string {
+	return append([]string{"categoryGroup", "region_id", "distillery_id", "image_url"}, normalize.MetadataKeys...)
+}
+
+// value nil-safe 문자열 역참조
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
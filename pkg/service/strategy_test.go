@@ -0,0 +1,54 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// golden 파일은 PYTHON_EMBEDDER_FEATURES.md 7장 명세를 옮겨 쓴 스크립트로 만든다:
+// python3 pkg/service/testdata/whisky_strategy_spec.py
+// 실제 Python 서비스 출력이 아니므로 Python과의 일치가 아니라 명세 기준 회귀만 확인한다
+func TestBuildWhiskyStrategyMatchesSpec(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "alcohols.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []struct {
+		Name    string         `json:"name"`
+		Alcohol domain.Alcohol `json:"alcohol"`
+	}
+	if err := json.Unmarshal(raw, &cases); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", "whisky_strategy_spec", tc.Name+".golden"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want map[string]string
+			if err := json.Unmarshal(golden, &want); err != nil {
+				t.Fatal(err)
+			}
+
+			strategy := BuildWhiskyStrategy(&tc.Alcohol)
+			got := map[string]string{
+				"flavor_semantic_text":  strategy.FlavorText,
+				"identity_keyword_text": strategy.IdentityText,
+				"origin_context_text":   strategy.OriginText,
+				"spec_attribute_text":   strategy.SpecText,
+				"rag_context_text":      strategy.RAGContext,
+			}
+			for key, text := range want {
+				if got[key] != text {
+					t.Errorf("%s\n got: %q\nwant: %q", key, got[key], text)
+				}
+			}
+		})
+	}
+}
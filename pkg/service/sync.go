@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+// SyncReport 증분 동기화 결과
+type SyncReport struct {
+	Since time.Time `json:"since"`
+	// ScanFrom 실제 조회 시작 시각 (Since - Overlap, 늦게 커밋된 행을 다시 확인)
+	ScanFrom time.Time    `json:"scan_from"`
+	Until    time.Time    `json:"until"`
+	Changed  int          `json:"changed"`
+	Index    *IndexReport `json:"index,omitempty"`
+	// Advanced 기준 시각을 Until로 옮겼는지 (실패가 있으면 다음 동기화에서 다시 처리)
+	Advanced bool `json:"advanced"`
+}
+
+// Sync 기준 시각 이후 바뀐 술만 다시 임베딩
+// 1. 기준 시각(since)과 현재 최신 수정 시각(until)을 구하고
+// 2. [since - overlap, until] 구간에 술 또는 연결된 region/distillery/tasting tag가 바뀐 술을 인덱싱한 뒤
+// 3. 실패가 없을 때만 기준 시각을 until로 전진한다
+//
+// last_modify_at은 커밋 시각이 아니라 쓰기 시각이라, 긴 트랜잭션이 until보다 이른 값으로 늦게 커밋되면
+// (since, until] 구간만 보는 동기화는 그 행을 영영 놓친다. overlap만큼 겹쳐 다시 조회하고,
+// 이미 반영된 술은 내용 해시가 같아 임베딩/저장을 건너뛴다.
+// alcohol_tasting_tags 연결만 바뀐 경우나 last_modify_at 컬럼이 없는 연관 테이블의 변경은 잡지 못한다 (outbox 또는 reconcile로 반영).
+func (ix *Indexer) Sync(ctx context.Context, state repository.JobStore, opts IndexOptions, overlap time.Duration, progress func(IndexProgress)) (*SyncReport, error) {
+	since, err := state.Watermark(ctx, ix.schema.Name)
+	if err != nil {
+		return nil, err
+	}
+	until, err := ix.alcohols.MaxModifiedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from := since
+	if !since.IsZero() && overlap > 0 {
+		from = since.Add(-overlap)
+	}
+	report := &SyncReport{Since: since, ScanFrom: from, Until: until}
+	advance := until.After(since)
+	if !advance {
+		// 새 변경이 없어도 overlap 구간의 늦은 커밋은 확인한다
+		if since.IsZero() || overlap <= 0 {
+			return report, nil
+		}
+		until = since
+		report.Until = since
+	}
+
+	ids, err := ix.alcohols.FindChangedIDs(ctx, from, until)
+	if err != nil {
+		return nil, err
+	}
+	report.Changed = len(ids)
+
+	if len(ids) > 0 {
+		opts.IDs = ids
+		opts.FromID, opts.ToID, opts.Limit = 0, 0, 0
+		report.Index, err = ix.Run(ctx, opts, progress)
+		if err != nil {
+			return report, err
+		}
+		if report.Index.Failed > 0 {
+			return report, nil
+		}
+	}
+	if opts.DryRun || !advance {
+		return report, nil
+	}
+
+	if err := state.SaveWatermark(ctx, ix.schema.Name, until); err != nil {
+		return report, fmt.Errorf("기준 시각 저장 실패: %w", err)
+	}
+	report.Advanced = true
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Whale0928/embedding-worker/pkg/repository"
+)
+
+func TestSyncRescansOverlapForLateCommits(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	jobs, err := repository.NewFileJobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	emb := &fakeEmbedder{dim: 4}
+	store := repository.NewMemoryStore()
+	s := testWhiskySchema()
+	indexer := NewIndexer(repository.NewAlcoholRepository(db), NewWhiskyV2Strategy(), emb, store, s)
+
+	t0 := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	createAlcohol(t, db, 1, "글렌피딕", t0.Add(-time.Hour))
+	createAlcohol(t, db, 2, "라프로익", t0)
+
+	report, err := indexer.Sync(ctx, jobs, IndexOptions{}, 5*time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Advanced || report.Changed != 2 || !report.Until.Equal(t0) {
+		t.Fatalf("첫 동기화 %+v", report)
+	}
+
+	// 첫 동기화 뒤에 커밋됐지만 last_modify_at은 기준 시각보다 이른 행
+	createAlcohol(t, db, 3, "맥캘란", t0.Add(-time.Minute))
+
+	// 겹치지 않으면 새 최신 시각이 없어 놓친다
+	missed, err := indexer.Sync(ctx, jobs, IndexOptions{}, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missed.Changed != 0 {
+		t.Fatalf("overlap 0 동기화 %+v", missed)
+	}
+
+	before := emb.embedded()
+	report, err = indexer.Sync(ctx, jobs, IndexOptions{}, 5*time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.ScanFrom.Equal(t0.Add(-5*time.Minute)) || report.Advanced {
+		t.Fatalf("overlap 동기화 %+v", report)
+	}
+	// 겹친 구간의 2는 해시가 같아 건너뛰고, 늦게 커밋된 3만 임베딩한다 (1은 구간 밖)
+	if report.Changed != 2 || report.Index.Indexed != 1 || report.Index.Skipped != 1 {
+		t.Fatalf("overlap 동기화 결과 %+v", report.Index)
+	}
+	if _, err := store.Get(ctx, s, 3); err != nil {
+		t.Fatalf("늦게 커밋된 술 미반영: %v", err)
+	}
+	if got := emb.embedded() - before; got != len(s.Vectors) {
+		t.Fatalf("임베딩한 텍스트 %d개, want %d", got, len(s.Vectors))
+	}
+
+	watermark, err := jobs.Watermark(ctx, s.Name)
+	if err != nil || !watermark.Equal(t0) {
+		t.Fatalf("기준 시각 %s, %v", watermark, err)
+	}
+}
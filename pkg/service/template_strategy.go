@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+// StrategyDefinition 선언형 임베딩 전략 (벡터 이름 → text/template)
+// 템플릿의 데이터는 *domain.Alcohol 이다
+type StrategyDefinition struct {
+	Name       string            `json:"name"`
+	Vectors    map[string]string `json:"vectors"`
+	RAGContext string            `json:"rag_context"`
+}
+
+// TemplateStrategy 템플릿 기반 전략, 버전은 정의 내용의 해시
+type TemplateStrategy struct {
+	name    string
+	version string
+	vectors map[string]*template.Template
+	rag     *template.Template
+}
+
+// NewTemplateStrategy 템플릿 파싱 및 버전 계산
+func NewTemplateStrategy(def StrategyDefinition) (*TemplateStrategy, error) {
+	if def.Name == "" {
+		return nil, fmt.Errorf("전략 이름 필수")
+	}
+	if len(def.Vectors) == 0 {
+		return nil, fmt.Errorf("전략 %s: 벡터 템플릿 최소 1개 필요", def.Name)
+	}
+
+	s := &TemplateStrategy{
+		name:    def.Name,
+		version: definitionVersion(def),
+		vectors: make(map[string]*template.Template, len(def.Vectors)),
+	}
+	for name, text := range def.Vectors {
+		tmpl, err := newTemplate(def.Name+"."+name, text)
+		if err != nil {
+			return nil, err
+		}
+		s.vectors[name] = tmpl
+	}
+	if def.RAGContext != "" {
+		tmpl, err := newTemplate(def.Name+".rag_context", def.RAGContext)
+		if err != nil {
+			return nil, err
+		}
+		s.rag = tmpl
+	}
+	return s, nil
+}
+
+func newTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("템플릿 %s 파싱 실패: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// definitionVersion 정의 내용(JSON, 키 정렬)의 SHA-256 앞 12자리
+func definitionVersion(def StrategyDefinition) string {
+	canonical, _ := json.Marshal(def)
+	sum := sha256.Sum256(canonical)
+	return def.Name + "-" + hex.EncodeToString(sum[:])[:12]
+}
+
+func (s *TemplateStrategy) Name() string {
+	return s.name
+}
+
+func (s *TemplateStrategy) Version() string {
+	return s.version
+}
+
+// Vectors 템플릿이 정의된 벡터 이름 (정렬)
+func (s *TemplateStrategy) Vectors() []string {
+	names := make([]string, 0, len(s.vectors))
+	for name := range s.vectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build 벡터별 템플릿 실행, 연속 공백은 하나로 정리한다
+func (s *TemplateStrategy) Build(a *domain.Alcohol) (*EmbeddingInput, error) {
+	metadata, issues := filterMetadata(a)
+	input := &EmbeddingInput{
+		ID:              a.ID,
+		StrategyVersion: s.version,
+		Texts:           make(map[string]string, len(s.vectors)),
+		FilterMetadata:  metadata,
+		Issues:          issues,
+	}
+	for name, tmpl := range s.vectors {
+		text, err := execute(tmpl, a)
+		if err != nil {
+			return nil, err
+		}
+		input.Texts[name] = text
+	}
+	if s.rag != nil {
+		text, err := execute(s.rag, a)
+		if err != nil {
+			return nil, err
+		}
+		input.RAGContext = text
+	}
+	return input, nil
+}
+
+func execute(tmpl *template.Template, a *domain.Alcohol) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, a); err != nil {
+		return "", fmt.Errorf("템플릿 %s 실행 실패 (alcohol %d): %w", tmpl.Name(), a.ID, err)
+	}
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
+
+// templateFuncs 템플릿 헬퍼
+//
+//	str      포인터 역참조 (nil이면 "")
+//	join     공백으로 연결, nil/빈 값은 건너뜀
+//	joinWith 구분자로 연결, nil/빈 값은 건너뜀
+//	unit     값이 있으면 단위 접미사 추가 ("40" "도" → "40도"), 없으면 ""
+//	joinTags 테이스팅 태그를 "한글 영문" 쌍으로 공백 연결
+//	tagNames 테이스팅 태그 한글 이름을 ", "로 연결
+var templateFuncs = template.FuncMap{
+	"str": func(v interface{}) string {
+		return stringify(v)
+	},
+	"join": func(values ...interface{}) string {
+		return joinNonEmpty(" ", values)
+	},
+	"joinWith": func(sep string, values ...interface{}) string {
+		return joinNonEmpty(sep, values)
+	},
+	"unit": func(v interface{}, suffix string) string {
+		s := stringify(v)
+		if s == "" {
+			return ""
+		}
+		return s + suffix
+	},
+	"joinTags": func(tags []domain.TastingTag) string {
+		parts := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			parts = append(parts, joinNonEmpty(" ", []interface{}{tag.KorName, tag.EngName}))
+		}
+		return strings.Join(parts, " ")
+	},
+	"tagNames": func(tags []domain.TastingTag) string {
+		names := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			names = append(names, tag.KorName)
+		}
+		return strings.Join(names, ", ")
+	},
+}
+
+func joinNonEmpty(sep string, values []interface{}) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		if s := stringify(v); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// stringify nil 포인터는 "", 포인터는 역참조 후 문자열화
+func stringify(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	return strings.TrimSpace(fmt.Sprint(rv.Interface()))
+}
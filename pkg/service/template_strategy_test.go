@@ -0,0 +1,212 @@
+package service
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/Whale0928/embedding-worker/pkg/domain"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNewTemplateStrategyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		def  StrategyDefinition
+		want string
+	}{
+		{"이름 없음", StrategyDefinition{Vectors: map[string]string{"flavor": "x"}}, "이름 필수"},
+		{"벡터 없음", StrategyDefinition{Name: "t"}, "최소 1개"},
+		{"벡터 템플릿 문법 오류", StrategyDefinition{Name: "t", Vectors: map[string]string{"flavor": "{{.KorName"}}, "t.flavor"},
+		{"없는 헬퍼", StrategyDefinition{Name: "t", Vectors: map[string]string{"flavor": "{{upper .KorName}}"}}, "t.flavor"},
+		{"RAG 템플릿 문법 오류", StrategyDefinition{Name: "t", Vectors: map[string]string{"flavor": "x"}, RAGContext: "{{end}}"}, "t.rag_context"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTemplateStrategy(tt.def)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateStrategyHelpers(t *testing.T) {
+	full := &domain.Alcohol{
+		ID:          1,
+		KorName:     "글렌피딕",
+		EngName:     "Glenfiddich",
+		ABV:         ptr("40"),
+		Age:         ptr(" 12 "),
+		Cask:        ptr(""),
+		RegionID:    ptr(int64(10)),
+		TastingTags: []domain.TastingTag{{KorName: "서양배", EngName: "Pear"}, {KorName: "오크"}},
+	}
+	empty := &domain.Alcohol{ID: 2, KorName: "블렌드"}
+
+	tests := []struct {
+		name  string
+		tmpl  string
+		full  string
+		empty string
+	}{
+		{"str 역참조, nil은 빈 문자열", `[{{str .ABV}}]`, "[40]", "[]"},
+		{"str 앞뒤 공백 제거", `[{{str .Age}}]`, "[12]", "[]"},
+		{"str 숫자 포인터", `{{str .RegionID}}`, "10", ""},
+		{"join nil/빈 값 건너뜀", `{{join .KorName .Cask .ABV .EngName}}`, "글렌피딕 40 Glenfiddich", "블렌드"},
+		{"joinWith", `{{joinWith ", " .KorName .ABV .Age}}`, "글렌피딕, 40, 12", "블렌드"},
+		{"unit 값이 있을 때만", `[{{unit .ABV "도"}}|{{unit .Cask "cask"}}]`, "[40도|]", "[|]"},
+		{"joinTags 한글 영문 쌍", `{{joinTags .TastingTags}}`, "서양배 Pear 오크", ""},
+		{"tagNames", `{{tagNames .TastingTags}}`, "서양배, 오크", ""},
+		{"nil 연관은 with로 건너뜀", `{{with .Region}}{{.KorName}}{{else}}-{{end}}`, "-", "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewTemplateStrategy(StrategyDefinition{Name: "t", Vectors: map[string]string{"v": tt.tmpl}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range []struct {
+				alcohol *domain.Alcohol
+				want    string
+			}{{full, tt.full}, {empty, tt.empty}} {
+				input, err := strategy.Build(c.alcohol)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := input.Texts["v"]; got != c.want {
+					t.Errorf("alcohol %d: got %q, want %q", c.alcohol.ID, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateStrategyBuild(t *testing.T) {
+	strategy, err := NewTemplateStrategy(StrategyDefinition{
+		Name: "t",
+		Vectors: map[string]string{
+			"identity": "  {{.KorName}}\n\n {{with .Distillery}}{{.KorName}}{{end}}\t {{.EngName}}  ",
+			"spec":     "{{.Type}} {{unit .ABV \"도\"}} {{unit .Volume \"ml\"}}",
+		},
+		RAGContext: "{{.KorName}}은\n{{with .Region}}{{.KorName}}의 {{end}}{{.Type}}입니다.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &domain.Alcohol{ID: 7, KorName: "라프로익", EngName: "Laphroaig", Type: "Single Malt", ABV: ptr("40~43"), RegionID: ptr(int64(3))}
+
+	input, err := strategy.Build(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 연속 공백/줄바꿈/탭은 하나로, 앞뒤 공백 제거 (nil 필드가 남긴 빈 자리도 정리된다)
+	want := map[string]string{"identity": "라프로익 Laphroaig", "spec": "Single Malt 40~43도"}
+	if !reflect.DeepEqual(input.Texts, want) {
+		t.Fatalf("Texts = %q", input.Texts)
+	}
+	if input.RAGContext != "라프로익은 Single Malt입니다." {
+		t.Fatalf("RAGContext = %q", input.RAGContext)
+	}
+	if input.ID != 7 || input.StrategyVersion != strategy.Version() {
+		t.Fatalf("ID/버전 %d %q", input.ID, input.StrategyVersion)
+	}
+	// 필터 메타데이터는 내장 전략과 같은 규칙
+	if input.FilterMetadata["abv_max"] != 43.0 || input.FilterMetadata["region_id"] != int64(3) {
+		t.Fatalf("FilterMetadata = %v", input.FilterMetadata)
+	}
+	if got := strategy.Vectors(); !reflect.DeepEqual(got, []string{"identity", "spec"}) {
+		t.Fatalf("Vectors = %v", got)
+	}
+
+	// 없는 필드는 실행 오류
+	broken, err := NewTemplateStrategy(StrategyDefinition{Name: "t", Vectors: map[string]string{"v": "{{.Nope}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := broken.Build(a); err == nil || !strings.Contains(err.Error(), "alcohol 7") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestTemplateStrategyVersion(t *testing.T) {
+	base := StrategyDefinition{
+		Name:       "t",
+		Vectors:    map[string]string{"flavor": "{{.Cask}}", "spec": "{{.Type}}"},
+		RAGContext: "{{.KorName}}",
+	}
+	version := func(def StrategyDefinition) string {
+		t.Helper()
+		strategy, err := NewTemplateStrategy(def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strategy.Version()
+	}
+
+	v := version(base)
+	if !strings.HasPrefix(v, "t-") || len(v) != len("t-")+12 {
+		t.Fatalf("버전 형식 %q", v)
+	}
+	// 같은 정의 (맵 순서만 다름)는 같은 버전
+	same := StrategyDefinition{Name: "t", Vectors: map[string]string{"spec": "{{.Type}}", "flavor": "{{.Cask}}"}, RAGContext: "{{.KorName}}"}
+	if got := version(same); got != v {
+		t.Fatalf("같은 정의인데 버전이 다름: %q != %q", got, v)
+	}
+
+	changed := []struct {
+		name string
+		def  StrategyDefinition
+	}{
+		{"템플릿", StrategyDefinition{Name: "t", Vectors: map[string]string{"flavor": "{{.Cask}} ", "spec": "{{.Type}}"}, RAGContext: "{{.KorName}}"}},
+		{"벡터 추가", StrategyDefinition{Name: "t", Vectors: map[string]string{"flavor": "{{.Cask}}", "spec": "{{.Type}}", "origin": "x"}, RAGContext: "{{.KorName}}"}},
+		{"벡터 이름", StrategyDefinition{Name: "t", Vectors: map[string]string{"flavour": "{{.Cask}}", "spec": "{{.Type}}"}, RAGContext: "{{.KorName}}"}},
+		{"RAG", StrategyDefinition{Name: "t", Vectors: base.Vectors}},
+	}
+	for _, tt := range changed {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := version(tt.def); got == v {
+				t.Fatalf("정의가 바뀌었는데 버전이 같음: %q", got)
+			}
+		})
+	}
+}
+
+// 예제 전략 파일은 로드/실행되고, 내장 v2와 텍스트가 다르므로 v2 이름을 쓰지 않는다
+func TestStrategyExampleFile(t *testing.T) {
+	v := viper.New()
+	v.SetConfigFile(filepath.Join("..", "..", "strategy.example.yaml"))
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	var def StrategyDefinition
+	def.Name = v.GetString("name")
+	def.Vectors = v.GetStringMapString("vectors")
+	def.RAGContext = v.GetString("rag_context")
+
+	strategy, err := NewTemplateStrategy(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strategy.Name() == NewWhiskyV2Strategy().Name() {
+		t.Fatalf("예제 전략 이름이 내장 전략과 같음: %s", strategy.Name())
+	}
+	if got := strategy.Vectors(); !reflect.DeepEqual(got, []string{"flavor", "identity", "origin", "spec"}) {
+		t.Fatalf("Vectors = %v", got)
+	}
+
+	a := &domain.Alcohol{ID: 1, KorName: "글렌피딕 12년", EngName: "Glenfiddich 12", Type: "Single Malt", ABV: ptr("40")}
+	input, err := strategy.Build(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input.Texts["spec"] != "Single Malt 40도" || input.Texts["origin"] != "" {
+		t.Fatalf("Texts = %q", input.Texts)
+	}
+}
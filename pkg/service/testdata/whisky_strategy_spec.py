@@ -0,0 +1,96 @@
+"""PYTHON_EMBEDDER_FEATURES.md 7.2, 7.3의 텍스트 조합 명세를 옮겨 쓴 스크립트로 golden 파일 생성
+
+실제 Python 서비스의 v2 WhiskyEmbeddingStrategy 출력이 아니다 (명세 기준 회귀 테스트용)
+
+    python3 pkg/service/testdata/whisky_strategy_spec.py
+"""
+import json
+import os
+from types import SimpleNamespace
+
+
+def to_namespace(value):
+    if isinstance(value, dict):
+        return SimpleNamespace(**{k: to_namespace(v) for k, v in value.items()})
+    if isinstance(value, list):
+        return [to_namespace(v) for v in value]
+    return value
+
+
+def to_whisky_strategy(alcohol):
+    flavor_parts = []
+    for tag in alcohol.tasting_tags:
+        flavor_parts.append(f"{tag.kor_name} {tag.eng_name}")
+        if tag.description:
+            flavor_parts.append(tag.description)
+    if alcohol.cask:
+        flavor_parts.append(alcohol.cask)
+    if alcohol.description:
+        flavor_parts.append(alcohol.description)
+    flavor_semantic_text = " ".join(flavor_parts)
+
+    identity_parts = [alcohol.kor_name, alcohol.eng_name]
+    if alcohol.distillery:
+        identity_parts.extend([alcohol.distillery.kor_name, alcohol.distillery.eng_name])
+    if alcohol.kor_category:
+        identity_parts.extend([alcohol.kor_category, alcohol.eng_category])
+    identity_keyword_text = " ".join(identity_parts)
+
+    origin_parts = []
+    if alcohol.region:
+        origin_parts.extend([
+            alcohol.region.kor_name,
+            alcohol.region.eng_name,
+            alcohol.region.continent,
+            alcohol.region.description or ""
+        ])
+    origin_context_text = " ".join(origin_parts)
+
+    spec_parts = [alcohol.type]
+    if alcohol.abv:
+        spec_parts.append(f"{alcohol.abv}도")
+    if alcohol.age:
+        spec_parts.append(f"{alcohol.age}년")
+    if alcohol.cask:
+        spec_parts.append(alcohol.cask)
+    if alcohol.volume:
+        spec_parts.append(f"{alcohol.volume}ml")
+    if alcohol.category_group:
+        spec_parts.append(alcohol.category_group)
+    spec_attribute_text = " ".join(spec_parts)
+
+    rag_context = f"{alcohol.kor_name}({alcohol.eng_name})은 "
+    if alcohol.region:
+        rag_context += f"{alcohol.region.kor_name}의 "
+    if alcohol.distillery:
+        rag_context += f"{alcohol.distillery.kor_name}에서 생산된 "
+    rag_context += f"{alcohol.type}입니다. "
+    if alcohol.abv:
+        rag_context += f"도수는 {alcohol.abv}도이며, "
+    if alcohol.age:
+        rag_context += f"{alcohol.age}년 숙성되었습니다. "
+
+    return {
+        "flavor_semantic_text": flavor_semantic_text,
+        "identity_keyword_text": identity_keyword_text,
+        "origin_context_text": origin_context_text,
+        "spec_attribute_text": spec_attribute_text,
+        "rag_context_text": rag_context,
+    }
+
+
+def main():
+    here = os.path.dirname(os.path.abspath(__file__))
+    with open(os.path.join(here, "alcohols.json"), encoding="utf-8") as f:
+        cases = json.load(f)
+    for case in cases:
+        strategy = to_whisky_strategy(to_namespace(case["alcohol"]))
+        path = os.path.join(here, "whisky_strategy_spec", case["name"] + ".golden")
+        os.makedirs(os.path.dirname(path), exist_ok=True)
+        with open(path, "w", encoding="utf-8") as f:
+            json.dump(strategy, f, ensure_ascii=False, indent=2)
+            f.write("\n")
+
+
+if __name__ == "__main__":
+    main()